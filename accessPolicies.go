@@ -2,8 +2,10 @@ package safeguard
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"time"
 )
 
@@ -130,12 +132,12 @@ func (a AccessPolicy) GetReasonCodes() []ReasonCode {
 // Returns:
 //   - []ApproverSet: A slice of ApproverSet objects
 //   - error: An error if the request fails
-func (a AccessPolicy) GetApproverSets() ([]ApproverSet, error) {
+func (a AccessPolicy) GetApproverSets(ctx context.Context) ([]ApproverSet, error) {
 	var approverSets []ApproverSet
 
 	query := fmt.Sprintf("AccessPolicies/%d/ApproverSets", a.Id)
 
-	response, err := a.apiClient.GetRequest(query)
+	response, err := a.apiClient.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -150,12 +152,13 @@ func (a AccessPolicy) GetApproverSets() ([]ApproverSet, error) {
 // SetApproverSets sets who can approve access requests for this policy.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - approverSets: A slice of ApproverSet objects to set as approvers
 //
 // Returns:
 //   - []ApproverSet: The updated ApproverSet objects
 //   - error: An error if the request fails
-func (a AccessPolicy) SetApproverSets(approverSets []ApproverSet) ([]ApproverSet, error) {
+func (a AccessPolicy) SetApproverSets(ctx context.Context, approverSets []ApproverSet) ([]ApproverSet, error) {
 	var updatedApproverSets []ApproverSet
 
 	query := fmt.Sprintf("AccessPolicies/%d/ApproverSets", a.Id)
@@ -164,7 +167,7 @@ func (a AccessPolicy) SetApproverSets(approverSets []ApproverSet) ([]ApproverSet
 		return nil, err
 	}
 
-	response, err := a.apiClient.PutRequest(query, bytes.NewReader(data))
+	response, err := a.apiClient.PutRequest(ctx, query, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -179,13 +182,14 @@ func (a AccessPolicy) SetApproverSets(approverSets []ApproverSet) ([]ApproverSet
 // ModifyApproverSets adds or removes approvers who can approve access requests for this policy.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - operation: The operation to perform (Add or Remove)
 //   - approverSets: A slice of ApproverSet objects to modify
 //
 // Returns:
 //   - []ApproverSet: The updated ApproverSet objects
 //   - error: An error if the request fails
-func (a AccessPolicy) ModifyApproverSets(operation ApiSetOperation, approverSets []ApproverSet) ([]ApproverSet, error) {
+func (a AccessPolicy) ModifyApproverSets(ctx context.Context, operation ApiSetOperation, approverSets []ApproverSet) ([]ApproverSet, error) {
 	var updatedApproverSets []ApproverSet
 
 	query := fmt.Sprintf("AccessPolicies/%d/ApproverSets/%s", a.Id, operation)
@@ -194,7 +198,7 @@ func (a AccessPolicy) ModifyApproverSets(operation ApiSetOperation, approverSets
 		return nil, err
 	}
 
-	response, err := a.apiClient.PostRequest(query, bytes.NewReader(data))
+	response, err := a.apiClient.PostRequest(ctx, query, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -214,12 +218,12 @@ func (a AccessPolicy) ModifyApproverSets(operation ApiSetOperation, approverSets
 // Returns:
 //   - A slice of Identity objects representing the reviewers.
 //   - An error if the request or unmarshalling fails.
-func (a AccessPolicy) GetReviewers() ([]Identity, error) {
+func (a AccessPolicy) GetReviewers(ctx context.Context) ([]Identity, error) {
 	var reviewers []Identity
 
 	query := fmt.Sprintf("AccessPolicies/%d/Reviewers", a.Id)
 
-	response, err := a.apiClient.GetRequest(query)
+	response, err := a.apiClient.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -237,6 +241,7 @@ func (a AccessPolicy) GetReviewers() ([]Identity, error) {
 //
 // Parameters:
 //
+//	ctx - Context for cancellation and timeouts.
 //	reviewers - A slice of Identity objects representing the reviewers to be set.
 //
 // Returns:
@@ -249,7 +254,7 @@ func (a AccessPolicy) GetReviewers() ([]Identity, error) {
 //  3. Sends a PUT request to the API with the marshaled data.
 //  4. Unmarshals the response into a slice of modified Identity objects.
 //  5. Adds the API client to the modified reviewers slice and returns it.
-func (a AccessPolicy) SetReviewers(reviewers []Identity) ([]Identity, error) {
+func (a AccessPolicy) SetReviewers(ctx context.Context, reviewers []Identity) ([]Identity, error) {
 	var modifiedReviewers []Identity
 
 	query := fmt.Sprintf("AccessPolicies/%d/Reviewers", a.Id)
@@ -258,7 +263,7 @@ func (a AccessPolicy) SetReviewers(reviewers []Identity) ([]Identity, error) {
 		return nil, err
 	}
 
-	response, err := a.apiClient.PutRequest(query, bytes.NewReader(data))
+	response, err := a.apiClient.PutRequest(ctx, query, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -274,6 +279,7 @@ func (a AccessPolicy) SetReviewers(reviewers []Identity) ([]Identity, error) {
 // It sends a POST request to the API with the updated list of reviewers and returns the updated list.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - operation: The operation to perform on the reviewers (e.g., add or remove).
 //   - reviewers: A slice of Identity objects representing the reviewers to be modified.
 //
@@ -287,7 +293,7 @@ func (a AccessPolicy) SetReviewers(reviewers []Identity) ([]Identity, error) {
 //	if err != nil {
 //	    log.Fatalf("Failed to modify reviewers: %v", err)
 //	}
-func (a AccessPolicy) ModifyReviewers(operation ApiSetOperation, reviewers []Identity) ([]Identity, error) {
+func (a AccessPolicy) ModifyReviewers(ctx context.Context, operation ApiSetOperation, reviewers []Identity) ([]Identity, error) {
 	var updatedReviewers []Identity
 
 	query := fmt.Sprintf("AccessPolicies/%d/Reviewers/%s", a.Id, operation)
@@ -296,7 +302,7 @@ func (a AccessPolicy) ModifyReviewers(operation ApiSetOperation, reviewers []Ide
 		return nil, err
 	}
 
-	response, err := a.apiClient.PostRequest(query, bytes.NewReader(data))
+	response, err := a.apiClient.PostRequest(ctx, query, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -415,17 +421,18 @@ type ReviewerProperties struct {
 // It takes a Filter as parameter and uses the global client reference to make the API request.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: A Filter object used to filter the access policies.
 //
 // Returns:
 //   - A slice of AccessPolicy objects.
 //   - An error if the request fails or the response cannot be unmarshaled.
-func (c *SafeguardClient) GetAccessPolicies(filter Filter) ([]AccessPolicy, error) {
+func (c *SafeguardClient) GetAccessPolicies(ctx context.Context, filter Filter) ([]AccessPolicy, error) {
 	var accessPolicies []AccessPolicy
 
 	query := "AccessPolicies" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -437,17 +444,54 @@ func (c *SafeguardClient) GetAccessPolicies(filter Filter) ([]AccessPolicy, erro
 	return addClientToSlice(c, accessPolicies), nil
 }
 
+// AccessPolicies returns an iterator over all access policies matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.AccessPolicies(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[AccessPolicy, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) AccessPolicies(ctx context.Context, filter Filter) iter.Seq2[AccessPolicy, error] {
+	return paginate[AccessPolicy](ctx, c, "AccessPolicies", filter)
+}
+
+// CountAccessPolicies returns the total number of access policies matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching access policies
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountAccessPolicies(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "AccessPolicies", filter)
+}
+
 // GetAccessPolicy retrieves an access policy by its ID from the Safeguard API.
 // It uses the global client reference to make the API request.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: An integer representing the ID of the access policy to retrieve.
 //   - fields: Optional fields to include in the query.
 //
 // Returns:
 //   - AccessPolicy: The retrieved access policy.
 //   - error: An error if any occurred during the request or unmarshalling process.
-func (c *SafeguardClient) GetAccessPolicy(id int, fields Fields) (AccessPolicy, error) {
+func (c *SafeguardClient) GetAccessPolicy(ctx context.Context, id int, fields Fields) (AccessPolicy, error) {
 	var accessPolicy AccessPolicy
 
 	query := fmt.Sprintf("AccessPolicies/%d", id)
@@ -456,7 +500,7 @@ func (c *SafeguardClient) GetAccessPolicy(id int, fields Fields) (AccessPolicy,
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return accessPolicy, err
 	}
@@ -471,14 +515,15 @@ func (c *SafeguardClient) GetAccessPolicy(id int, fields Fields) (AccessPolicy,
 // It uses the global client reference to make the API request.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: An integer representing the ID of the access policy to be deleted.
 //
 // Returns:
 //   - error: An error object if the DELETE request fails, otherwise nil.
-func (c *SafeguardClient) DeleteAccessPolicy(id int) error {
+func (c *SafeguardClient) DeleteAccessPolicy(ctx context.Context, id int) error {
 	query := fmt.Sprintf("AccessPolicies/%d", id)
 
-	_, err := c.DeleteRequest(query)
+	_, err := c.DeleteRequest(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -489,8 +534,8 @@ func (c *SafeguardClient) DeleteAccessPolicy(id int) error {
 // Delete removes the access policy from the system.
 // It calls the DeleteAccessPolicy function with the client and policy ID.
 // Returns an error if the deletion fails.
-func (a AccessPolicy) Delete() error {
-	return a.apiClient.DeleteAccessPolicy(a.Id)
+func (a AccessPolicy) Delete(ctx context.Context) error {
+	return a.apiClient.DeleteAccessPolicy(ctx, a.Id)
 }
 
 // UpdateAccessPolicy updates an existing access policy with the provided details.
@@ -499,13 +544,14 @@ func (a AccessPolicy) Delete() error {
 // AccessPolicy object with the client reference attached.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the access policy to update
 //   - updatedAccessPolicy: AccessPolicy object containing the updated values
 //
 // Returns:
 //   - AccessPolicy: The updated access policy object
 //   - error: An error if the update operation fails
-func (c *SafeguardClient) UpdateAccessPolicy(id int, updatedAccessPolicy AccessPolicy) (AccessPolicy, error) {
+func (c *SafeguardClient) UpdateAccessPolicy(ctx context.Context, id int, updatedAccessPolicy AccessPolicy) (AccessPolicy, error) {
 	var accessPolicy AccessPolicy
 
 	query := fmt.Sprintf("AccessPolicies/%d", id)
@@ -514,7 +560,7 @@ func (c *SafeguardClient) UpdateAccessPolicy(id int, updatedAccessPolicy AccessP
 		return accessPolicy, err
 	}
 
-	response, err := c.PutRequest(query, bytes.NewReader(accessPolicyJSON))
+	response, err := c.PutRequest(ctx, query, bytes.NewReader(accessPolicyJSON))
 	if err != nil {
 		return accessPolicy, err
 	}
@@ -530,11 +576,12 @@ func (c *SafeguardClient) UpdateAccessPolicy(id int, updatedAccessPolicy AccessP
 // with the details from updatedAccessPolicy.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - updatedAccessPolicy: The AccessPolicy object containing the updated fields
 //
 // Returns:
 //   - AccessPolicy: The updated AccessPolicy object
 //   - error: An error if the update operation fails, nil otherwise
-func (a AccessPolicy) Update(updatedAccessPolicy AccessPolicy) (AccessPolicy, error) {
-	return a.apiClient.UpdateAccessPolicy(a.Id, updatedAccessPolicy)
+func (a AccessPolicy) Update(ctx context.Context, updatedAccessPolicy AccessPolicy) (AccessPolicy, error) {
+	return a.apiClient.UpdateAccessPolicy(ctx, a.Id, updatedAccessPolicy)
 }
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"strconv"
 	"time"
 )
@@ -183,34 +185,30 @@ type AccessRequestBatchResponse struct {
 	StatusCode       string        `json:"StatusCode,omitempty"`
 	StatusCodeNumber int           `json:"StatusCodeNumber,omitempty"`
 	IsSuccess        bool          `json:"IsSuccess,omitempty"`
-	Error            ApiError      `json:"Error,omitempty"`
+	Error            APIError      `json:"Error,omitempty"`
 	Request          BatchRequest  `json:"Request,omitempty"`
 }
 
+// SetClient associates the batch item and the access request in its Response
+// with the client, so methods of the created request can be called directly.
 func (a AccessRequestBatchResponse) SetClient(c *SafeguardClient) any {
 	a.apiClient = c
+	a.Response = addClient(c, a.Response)
 	return a
 }
 
 // hasError checks if the AccessRequestBatchResponse indicates a successful operation.
-// If the operation was successful, it returns nil. Otherwise, it returns an error
-// containing the error message from the response.
+// If the operation was successful, it returns nil. Otherwise, it returns an *APIError
+// carrying the status and error details of the failed item.
 //
 // Returns:
-//   - error: nil if the operation was successful, otherwise an error with the response message.
+//   - error: nil if the operation was successful, otherwise an *APIError for the item.
 func (ab AccessRequestBatchResponse) hasError() error {
 	if ab.IsSuccess {
 		return nil
 	}
 
-	return fmt.Errorf("error: %s", ab.Error.Message)
-}
-
-// ApiError represents error information returned by the API
-type ApiError struct {
-	Code       int    `json:"Code,omitempty"`
-	Message    string `json:"Message,omitempty"`
-	InnerError string `json:"InnerError,omitempty"`
+	return ab.Error.forBatchItem(ab.StatusCodeNumber, "AccessRequests/BatchCreate")
 }
 
 // BatchRequest represents the request portion of a batch response
@@ -265,16 +263,17 @@ type AccessRequestReviewBatchResponse struct {
 // The requests are sorted by creation date in descending order.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Filter criteria for the requests
 //
 // Returns:
 //   - []AccessRequest: Matching access requests
 //   - error: API or unmarshalling errors
-func (c *SafeguardClient) GetAccessRequests(filter Filter) ([]AccessRequest, error) {
+func (c *SafeguardClient) GetAccessRequests(ctx context.Context, filter Filter) ([]AccessRequest, error) {
 
 	query := "AccessRequests" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return []AccessRequest{}, err
 	}
@@ -287,23 +286,60 @@ func (c *SafeguardClient) GetAccessRequests(filter Filter) ([]AccessRequest, err
 	return addClientToSlice(c, accessRequests), err
 }
 
+// AccessRequests returns an iterator over all access requests matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.AccessRequests(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[AccessRequest, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) AccessRequests(ctx context.Context, filter Filter) iter.Seq2[AccessRequest, error] {
+	return paginate[AccessRequest](ctx, c, "AccessRequests", filter)
+}
+
+// CountAccessRequests returns the total number of access requests matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching access requests
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountAccessRequests(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "AccessRequests", filter)
+}
+
 // GetAccessRequest retrieves a specific access request by its ID.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the access request.
 //   - fields: Optional fields to include in the response.
 //
 // Returns:
 //   - AccessRequest: The retrieved access request.
 //   - error: An error if the request fails or unmarshalling fails.
-func (c *SafeguardClient) GetAccessRequest(id string, fields Fields) (AccessRequest, error) {
+func (c *SafeguardClient) GetAccessRequest(ctx context.Context, id string, fields Fields) (AccessRequest, error) {
 
 	query := "AccessRequests/" + id
 	if fields != nil {
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return AccessRequest{}, err
 	}
@@ -319,12 +355,13 @@ func (c *SafeguardClient) GetAccessRequest(id string, fields Fields) (AccessRequ
 // NewAccessRequests creates multiple access requests in a single batch operation.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - accountEntitlements: Slice of account entitlements to request access for.
 //
 // Returns:
 //   - []AccessRequestBatchResponse: Responses for each request in the batch.
 //   - error: An error if the batch operation fails.
-func (c *SafeguardClient) NewAccessRequests(accountEntitlements []AccountEntitlement, requestDuration time.Duration) ([]AccessRequestBatchResponse, error) {
+func (c *SafeguardClient) NewAccessRequests(ctx context.Context, accountEntitlements []AccountEntitlement, requestDuration time.Duration) ([]AccessRequestBatchResponse, error) {
 	var accessRequests []batchAccessRequest
 
 	// Reduce properties to the required ones for the request
@@ -339,7 +376,7 @@ func (c *SafeguardClient) NewAccessRequests(accountEntitlements []AccountEntitle
 	// 		 If requestDuration is to long, set it to the maximum!
 	//		 This should be maybe be solved in a program and not in an API
 
-	accessBatchRequests, err := c.batchCreateAccessRequest(accessRequests)
+	accessBatchRequests, err := c.batchCreateAccessRequest(ctx, accessRequests)
 	return addClientToSlice(c, accessBatchRequests), err
 }
 
@@ -397,6 +434,7 @@ type batchAccessRequest struct {
 // AccessRequestBatchResponse and an error.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: A pointer to a SafeguardClient used to make the API request.
 //   - accessRequests: A slice of batchAccessRequest containing the access requests to be created.
 //
@@ -410,12 +448,12 @@ type batchAccessRequest struct {
 //  3. Unmarshals the response into a slice of AccessRequestBatchResponse.
 //  4. Adds the client to each AccessRequest in the response.
 //  5. Collects any errors encountered during the process and returns them along with the responses.
-func (c *SafeguardClient) batchCreateAccessRequest(accessRequests []batchAccessRequest) ([]AccessRequestBatchResponse, error) {
+func (c *SafeguardClient) batchCreateAccessRequest(ctx context.Context, accessRequests []batchAccessRequest) ([]AccessRequestBatchResponse, error) {
 	requestBody, err := json.Marshal(accessRequests)
 	if err != nil {
 		return []AccessRequestBatchResponse{}, err
 	}
-	response, err := c.PostRequest("AccessRequests/BatchCreate", bytes.NewReader(requestBody))
+	response, err := c.PostRequest(ctx, "AccessRequests/BatchCreate", bytes.NewReader(requestBody))
 	if err != nil {
 		return []AccessRequestBatchResponse{}, err
 	}
@@ -425,14 +463,14 @@ func (c *SafeguardClient) batchCreateAccessRequest(accessRequests []batchAccessR
 		return []AccessRequestBatchResponse{}, err
 	}
 
-	var collectedErrors error
+	var batchErrors []error
 	for i := range createdAccessRequests {
 		if err := createdAccessRequests[i].hasError(); err != nil {
-			collectedErrors = fmt.Errorf("%v\n%v", collectedErrors, err)
+			batchErrors = append(batchErrors, err)
 		}
 	}
 
-	if collectedErrors != nil {
+	if collectedErrors := errors.Join(batchErrors...); collectedErrors != nil {
 		return addClientToSlice(c, createdAccessRequests), collectedErrors
 	}
 
@@ -447,16 +485,16 @@ func (c *SafeguardClient) batchCreateAccessRequest(accessRequests []batchAccessR
 // Returns:
 //   - AccessRequest: Updated request state
 //   - error: Any errors during close
-func (ar AccessRequest) Close() (AccessRequest, error) {
+func (ar AccessRequest) Close(ctx context.Context) (AccessRequest, error) {
 	switch ar.State {
 	case "PasswordCheckedOut":
-		return ar.CheckIn()
+		return ar.CheckIn(ctx)
 	case "Pending":
-		return ar.Cancel()
+		return ar.Cancel(ctx)
 	case "RequestAvailable":
-		return ar.Cancel()
+		return ar.Cancel(ctx)
 	case "PendingAccountRestored":
-		return ar.Cancel()
+		return ar.Cancel(ctx)
 	case "Complete":
 		return ar, nil
 	default:
@@ -469,15 +507,16 @@ func (ar AccessRequest) Close() (AccessRequest, error) {
 // into an AccessRequest object.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: A pointer to a SafeguardClient used to make the request.
 //   - id: The ID of the access request to be canceled.
 //
 // Returns:
 //   - AccessRequest: The canceled access request object.
 //   - error: An error object if the request fails or if there is an issue unmarshaling the response.
-func (c *SafeguardClient) CancelAccessRequest(id string) (AccessRequest, error) {
+func (c *SafeguardClient) CancelAccessRequest(ctx context.Context, id string) (AccessRequest, error) {
 
-	response, err := c.PostRequest("AccessRequests/"+id+"/Cancel", nil)
+	response, err := c.PostRequest(ctx, "AccessRequests/"+id+"/Cancel", nil)
 	if err != nil {
 		return AccessRequest{}, err
 	}
@@ -495,23 +534,24 @@ func (c *SafeguardClient) CancelAccessRequest(id string) (AccessRequest, error)
 // Returns:
 //   - AccessRequest: The updated access request showing canceled state.
 //   - error: An error if the cancellation fails.
-func (ar AccessRequest) Cancel() (AccessRequest, error) {
-	return ar.apiClient.CancelAccessRequest(ar.Id)
+func (ar AccessRequest) Cancel(ctx context.Context) (AccessRequest, error) {
+	return ar.apiClient.CancelAccessRequest(ctx, ar.Id)
 }
 
 // CheckInAccessRequest checks in an access request with the given ID using the provided SafeguardClient.
 // It sends a POST request to the "AccessRequests/{id}/CheckIn" endpoint and unmarshals the response into an AccessRequest object.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: A pointer to a SafeguardClient used to make the request.
 //   - id: The ID of the access request to check in.
 //
 // Returns:
 //   - AccessRequest: The checked-in access request object.
 //   - error: An error object if an error occurred during the request or unmarshalling.
-func (c *SafeguardClient) CheckInAccessRequest(id string) (AccessRequest, error) {
+func (c *SafeguardClient) CheckInAccessRequest(ctx context.Context, id string) (AccessRequest, error) {
 
-	response, err := c.PostRequest("AccessRequests/"+id+"/CheckIn", nil)
+	response, err := c.PostRequest(ctx, "AccessRequests/"+id+"/CheckIn", nil)
 	if err != nil {
 		return AccessRequest{}, err
 	}
@@ -529,8 +569,8 @@ func (c *SafeguardClient) CheckInAccessRequest(id string) (AccessRequest, error)
 // Returns:
 //   - AccessRequest: The updated access request showing checked-in state.
 //   - error: An error if the check-in fails.
-func (ar AccessRequest) CheckIn() (AccessRequest, error) {
-	return ar.apiClient.CheckInAccessRequest(ar.Id)
+func (ar AccessRequest) CheckIn(ctx context.Context) (AccessRequest, error) {
+	return ar.apiClient.CheckInAccessRequest(ctx, ar.Id)
 }
 
 // CheckOutPassword retrieves the password for the request, optionally waiting
@@ -578,7 +618,7 @@ func (c *SafeguardClient) CheckOutPassword(ctx context.Context, accessRequest Ac
 			case <-ctx.Done():
 				return "", fmt.Errorf("password request timed out")
 			case <-ticker.C:
-				accessRequest, err := c.GetAccessRequest(accessRequest.Id, nil)
+				accessRequest, err := c.GetAccessRequest(ctx, accessRequest.Id, nil)
 				if err != nil {
 					return "", err
 				}
@@ -590,7 +630,7 @@ func (c *SafeguardClient) CheckOutPassword(ctx context.Context, accessRequest Ac
 		}
 	}
 
-	return c.getPasswordforAccessRequest(accessRequest)
+	return c.getPasswordforAccessRequest(ctx, accessRequest)
 }
 
 // IsPending checks if the access request is in any pending state.
@@ -679,16 +719,17 @@ func isAccessRequestValid(accessRequest AccessRequest) bool {
 // It sends a POST request to the "AccessRequests/{id}/CheckOutPassword" endpoint.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests.
 //   - accessRequest: The access request for which the password is being checked out.
 //
 // Returns:
 //   - string: The checked-out password.
 //   - error: An error if the password checkout fails.
-func (c *SafeguardClient) getPasswordforAccessRequest(accessRequest AccessRequest) (string, error) {
+func (c *SafeguardClient) getPasswordforAccessRequest(ctx context.Context, accessRequest AccessRequest) (string, error) {
 	query := fmt.Sprintf("AccessRequests/%s/CheckOutPassword", accessRequest.Id)
 
-	response, err := c.PostRequest(query, nil)
+	response, err := c.PostRequest(ctx, query, nil)
 	if err != nil {
 		return "", err
 	}
@@ -710,6 +751,6 @@ func (c *SafeguardClient) getPasswordforAccessRequest(accessRequest AccessReques
 // Returns:
 //   - AccessRequest: Updated request state
 //   - error: API or unmarshalling errors
-func (ar AccessRequest) RefreshState() (AccessRequest, error) {
-	return ar.apiClient.GetAccessRequest(ar.Id, nil)
+func (ar AccessRequest) RefreshState(ctx context.Context) (AccessRequest, error) {
+	return ar.apiClient.GetAccessRequest(ctx, ar.Id, nil)
 }
@@ -475,7 +475,7 @@ func (p ActivityLog) getMatchingAccountTask(ctx context.Context) (AccountTaskDat
 		case <-ctx.Done():
 			return AccountTaskData{}, fmt.Errorf("timeout waiting for task to become available")
 		case <-ticker.C:
-			taskData, err := p.apiClient.GetAccountTaskSchedules(TaskNames(p.Name), filter)
+			taskData, err := p.apiClient.GetAccountTaskSchedules(ctx, TaskNames(p.Name), filter)
 			if err != nil {
 				continue
 			}
@@ -0,0 +1,148 @@
+package safeguard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// APIError represents an error returned by the Safeguard API.
+// It is returned by every request helper for non-successful HTTP responses
+// and is also embedded in batch responses for the individual items that failed.
+// Use errors.As to inspect it:
+//
+//	var apiErr *safeguard.APIError
+//	if errors.As(err, &apiErr) {
+//	    fmt.Println(apiErr.StatusCode, apiErr.Code, apiErr.Message)
+//	}
+type APIError struct {
+	StatusCode int    `json:"-"` // HTTP status code of the response
+	Method     string `json:"-"` // HTTP method of the failed request
+	Path       string `json:"-"` // URL path of the failed request
+
+	Code       int                 `json:"Code,omitempty"`       // Safeguard specific error code
+	Message    string              `json:"Message,omitempty"`    // Human readable error message
+	InnerError string              `json:"InnerError,omitempty"` // Additional error details, if any
+	ModelState map[string][]string `json:"ModelState,omitempty"` // Validation errors keyed by field name
+
+	body []byte
+}
+
+// ApiError represents error information returned by the API.
+//
+// Deprecated: Use APIError instead.
+type ApiError = APIError
+
+// Error implements the error interface.
+// The message contains the request, the HTTP status and the Safeguard error details.
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	if e.Method != "" || e.Path != "" {
+		fmt.Fprintf(&sb, "error during %s request to %s: ", e.Method, e.Path)
+	}
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&sb, "HTTP %d - ", e.StatusCode)
+	}
+
+	switch {
+	case e.Message != "" && e.Code != 0:
+		fmt.Fprintf(&sb, "%d: %s", e.Code, e.Message)
+	case e.Message != "":
+		sb.WriteString(e.Message)
+	case len(e.body) > 0:
+		sb.Write(e.body)
+	default:
+		sb.WriteString(http.StatusText(e.StatusCode))
+	}
+
+	if len(e.ModelState) > 0 {
+		fields := make([]string, 0, len(e.ModelState))
+		for field := range e.ModelState {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		details := make([]string, 0, len(fields))
+		for _, field := range fields {
+			details = append(details, fmt.Sprintf("%s: %s", field, strings.Join(e.ModelState[field], ", ")))
+		}
+		fmt.Fprintf(&sb, " (%s)", strings.Join(details, "; "))
+	}
+
+	return sb.String()
+}
+
+// newAPIError builds an APIError from a non-successful HTTP response.
+// If the body contains a Safeguard error document, its code, message and
+// model state are decoded; otherwise the raw body is kept for the error message.
+//
+// Parameters:
+//   - req: The request that failed.
+//   - statusCode: The HTTP status code of the response.
+//   - body: The response body.
+//
+// Returns:
+//   - *APIError: The populated error.
+func newAPIError(req *http.Request, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{}
+	if err := json.Unmarshal(body, apiErr); err != nil {
+		// Not a Safeguard error document, keep the raw body for the message
+		apiErr = &APIError{}
+	}
+
+	apiErr.StatusCode = statusCode
+	apiErr.Method = req.Method
+	apiErr.Path = req.URL.Path
+	apiErr.body = body
+	return apiErr
+}
+
+// forBatchItem returns a copy of the error as reported for a single item of a
+// batch request, filled in with the item's status code and the batch endpoint.
+//
+// Parameters:
+//   - statusCode: The HTTP status code reported for the batch item.
+//   - path: The batch endpoint the item was submitted to.
+//
+// Returns:
+//   - *APIError: The populated error.
+func (e APIError) forBatchItem(statusCode int, path string) *APIError {
+	e.StatusCode = statusCode
+	e.Method = http.MethodPost
+	e.Path = path
+	return &e
+}
+
+// hasStatus reports whether err is an APIError with the given HTTP status code.
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsBadRequest reports whether err is an APIError with HTTP status 400.
+func IsBadRequest(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
+
+// IsUnauthorized reports whether err is an APIError with HTTP status 401.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an APIError with HTTP status 403.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsNotFound reports whether err is an APIError with HTTP status 404.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an APIError with HTTP status 409.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
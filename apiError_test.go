@@ -0,0 +1,159 @@
+package safeguard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSendHttpRequestReturnsAPIError(t *testing.T) {
+	tests := []struct {
+		name           string
+		statusCode     int
+		body           string
+		wantCode       int
+		wantMessage    string
+		wantModelState map[string][]string
+		check          func(error) bool
+	}{
+		{
+			name:        "not found with safeguard error document",
+			statusCode:  http.StatusNotFound,
+			body:        `{"Code":70000,"Message":"Asset not found."}`,
+			wantCode:    70000,
+			wantMessage: "Asset not found.",
+			check:       IsNotFound,
+		},
+		{
+			name:        "conflict",
+			statusCode:  http.StatusConflict,
+			body:        `{"Code":60108,"Message":"An asset with this name already exists."}`,
+			wantCode:    60108,
+			wantMessage: "An asset with this name already exists.",
+			check:       IsConflict,
+		},
+		{
+			name:        "bad request with model state",
+			statusCode:  http.StatusBadRequest,
+			body:        `{"Code":70000,"Message":"The request is invalid.","ModelState":{"entity.Name":["The Name field is required."]}}`,
+			wantCode:    70000,
+			wantMessage: "The request is invalid.",
+			wantModelState: map[string][]string{
+				"entity.Name": {"The Name field is required."},
+			},
+			check: IsBadRequest,
+		},
+		{
+			name:       "unauthorized with plain text body",
+			statusCode: http.StatusUnauthorized,
+			body:       "Authorization has been denied for this request.",
+			check:      IsUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				fmt.Fprint(w, tt.body)
+			})
+
+			_, err := client.GetRequest(context.Background(), "Assets/1")
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError, got %T", err)
+			}
+			if apiErr.StatusCode != tt.statusCode {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.statusCode)
+			}
+			if apiErr.Method != http.MethodGet {
+				t.Errorf("Method = %s, want %s", apiErr.Method, http.MethodGet)
+			}
+			if apiErr.Path != "/service/core/v4/Assets/1" {
+				t.Errorf("Path = %s, want /service/core/v4/Assets/1", apiErr.Path)
+			}
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("Code = %d, want %d", apiErr.Code, tt.wantCode)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+			for field, msgs := range tt.wantModelState {
+				if got := apiErr.ModelState[field]; len(got) != len(msgs) || got[0] != msgs[0] {
+					t.Errorf("ModelState[%s] = %v, want %v", field, got, msgs)
+				}
+			}
+			if !tt.check(err) {
+				t.Errorf("status check returned false for %v", err)
+			}
+			if !strings.Contains(err.Error(), fmt.Sprintf("HTTP %d", tt.statusCode)) {
+				t.Errorf("error message %q does not contain status code", err.Error())
+			}
+		})
+	}
+}
+
+func TestAPIErrorStatusChecks(t *testing.T) {
+	notFound := &APIError{StatusCode: http.StatusNotFound}
+	wrapped := fmt.Errorf("lookup failed: %w", notFound)
+
+	if !IsNotFound(wrapped) {
+		t.Error("IsNotFound should detect wrapped APIError")
+	}
+	if IsConflict(wrapped) {
+		t.Error("IsConflict should be false for 404")
+	}
+	if IsNotFound(errors.New("plain error")) {
+		t.Error("IsNotFound should be false for non-API errors")
+	}
+	if IsNotFound(nil) {
+		t.Error("IsNotFound should be false for nil")
+	}
+}
+
+func TestBatchResponseErrors(t *testing.T) {
+	var responses []AssetAccountBatchResponse
+	body := `[
+		{"StatusCodeNumber":200,"IsSuccess":true},
+		{"StatusCodeNumber":409,"IsSuccess":false,"Error":{"Code":60108,"Message":"Account already exists."}},
+		{"StatusCodeNumber":400,"IsSuccess":false,"Error":{"Code":70000,"Message":"Invalid.","ModelState":{"Name":["Required"]}}}
+	]`
+	if err := json.Unmarshal([]byte(body), &responses); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if err := responses[0].hasError(); err != nil {
+		t.Errorf("expected no error for successful item, got %v", err)
+	}
+
+	var errs []error
+	for _, r := range responses {
+		if err := r.hasError(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	joined := errors.Join(errs...)
+
+	if !IsConflict(joined) {
+		t.Error("expected joined batch errors to contain a conflict")
+	}
+
+	var apiErr *APIError
+	if !errors.As(errs[1], &apiErr) {
+		t.Fatalf("expected *APIError, got %T", errs[1])
+	}
+	if apiErr.Path != "AssetAccounts/BatchCreate" || apiErr.Method != http.MethodPost {
+		t.Errorf("unexpected request info: %s %s", apiErr.Method, apiErr.Path)
+	}
+	if got := apiErr.ModelState["Name"]; len(got) != 1 || got[0] != "Required" {
+		t.Errorf("unexpected model state: %v", apiErr.ModelState)
+	}
+}
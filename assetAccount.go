@@ -2,8 +2,11 @@ package safeguard
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
 	"time"
 )
 
@@ -230,37 +233,43 @@ type AssetAccountBatchResponse struct {
 	StatusCode       string       `json:"StatusCode,omitempty"`
 	StatusCodeNumber int          `json:"StatusCodeNumber,omitempty"`
 	IsSuccess        bool         `json:"IsSuccess,omitempty"`
-	Error            ApiError     `json:"Error,omitempty"`
+	Error            APIError     `json:"Error,omitempty"`
 	Request          AssetAccount `json:"Request,omitempty"`
 }
 
+// SetClient associates the batch item and the asset account in its Response
+// with the client, so methods of the account can be called directly.
 func (a AssetAccountBatchResponse) SetClient(c *SafeguardClient) any {
 	a.apiClient = c
+	a.Response = addClient(c, a.Response)
 	return a
 }
 
+// hasError returns nil for a successful batch item, otherwise an *APIError
+// carrying the status and error details of the failed item.
 func (ab AssetAccountBatchResponse) hasError() error {
 	if ab.IsSuccess {
 		return nil
 	}
 
-	return fmt.Errorf("error: %s", ab.Error.Message)
+	return ab.Error.forBatchItem(ab.StatusCodeNumber, "AssetAccounts/BatchCreate")
 }
 
 // GetAssetAccounts retrieves accounts matching the provided filter.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Query parameters for filtering accounts
 //
 // Returns:
 //   - []AssetAccount: Matching accounts
 //   - error: API or unmarshalling errors
-func (c *SafeguardClient) GetAssetAccounts(filter Filter) ([]AssetAccount, error) {
+func (c *SafeguardClient) GetAssetAccounts(ctx context.Context, filter Filter) ([]AssetAccount, error) {
 	var users []AssetAccount
 
 	query := "AssetAccounts" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -272,8 +281,45 @@ func (c *SafeguardClient) GetAssetAccounts(filter Filter) ([]AssetAccount, error
 	return addClientToSlice(c, users), nil
 }
 
+// AssetAccounts returns an iterator over all asset accounts matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.AssetAccounts(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[AssetAccount, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) AssetAccounts(ctx context.Context, filter Filter) iter.Seq2[AssetAccount, error] {
+	return paginate[AssetAccount](ctx, c, "AssetAccounts", filter)
+}
+
+// CountAssetAccounts returns the total number of asset accounts matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching asset accounts
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountAssetAccounts(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "AssetAccounts", filter)
+}
+
 // GetAssetAccount retrieves a specific asset account by ID from Safeguard.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests
 //   - id: The ID of the asset account to retrieve
 //   - fields: Specific fields to include in the response
@@ -281,7 +327,7 @@ func (c *SafeguardClient) GetAssetAccounts(filter Filter) ([]AssetAccount, error
 // Returns:
 //   - AssetAccount: The requested asset account
 //   - error: An error if the request fails, nil otherwise
-func (c *SafeguardClient) GetAssetAccount(id int, fields Fields) (AssetAccount, error) {
+func (c *SafeguardClient) GetAssetAccount(ctx context.Context, id int, fields Fields) (AssetAccount, error) {
 	var user AssetAccount
 
 	query := fmt.Sprintf("AssetAccounts/%d", id)
@@ -289,7 +335,7 @@ func (c *SafeguardClient) GetAssetAccount(id int, fields Fields) (AssetAccount,
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return user, err
 	}
@@ -302,15 +348,16 @@ func (c *SafeguardClient) GetAssetAccount(id int, fields Fields) (AssetAccount,
 
 // DeleteAssetAccount deletes an asset account from Safeguard.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests
 //   - id: The ID of the asset account to delete
 //
 // Returns:
 //   - error: An error if the deletion fails, nil otherwise
-func (c *SafeguardClient) DeleteAssetAccount(id int) error {
+func (c *SafeguardClient) DeleteAssetAccount(ctx context.Context, id int) error {
 	query := fmt.Sprintf("AssetAccounts/%d", id)
 
-	_, err := c.DeleteRequest(query)
+	_, err := c.DeleteRequest(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -322,68 +369,93 @@ func (c *SafeguardClient) DeleteAssetAccount(id int) error {
 //
 // Returns:
 //   - error: Deletion errors
-func (a AssetAccount) Delete() error {
-	return a.apiClient.DeleteAssetAccount(a.Id)
+func (a AssetAccount) Delete(ctx context.Context) error {
+	return a.apiClient.DeleteAssetAccount(ctx, a.Id)
 }
 
-// ChangePassword initiates a password change operation for the asset account.
+// ChangeAssetAccountPassword initiates a password change for an asset account.
 // Parameters:
-//   - c: The SafeguardClient instance for making API requests
+//   - ctx: Context for cancellation and timeouts.
+//   - a: The AssetAccount whose password is changed
 //
 // Returns:
-//   - PasswordActivityLog: Log details of the password change activity
+//   - ActivityLog: Log details of the password change activity
 //   - error: An error if the password change fails or cannot be initiated
-func (a AssetAccount) ChangePassword() (ActivityLog, error) {
-	var log ActivityLog
-
-	query := fmt.Sprintf("AssetAccounts/%d/ChangePassword", a.Id)
-
-	response, err := a.apiClient.PostRequest(query, nil)
-	if err != nil {
-		return log, err
-	}
+func (c *SafeguardClient) ChangeAssetAccountPassword(ctx context.Context, a AssetAccount) (ActivityLog, error) {
+	return c.runAssetAccountTask(ctx, a, "ChangePassword")
+}
 
-	if json.Unmarshal(response, &log) != nil {
-		return log, err
-	}
+// ChangePassword initiates a password change operation for the asset account.
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//
+// Returns:
+//   - ActivityLog: Log details of the password change activity
+//   - error: An error if the password change fails or cannot be initiated
+func (a AssetAccount) ChangePassword(ctx context.Context) (ActivityLog, error) {
+	return a.apiClient.ChangeAssetAccountPassword(ctx, a)
+}
 
-	return addClient(a.apiClient, log), nil
+// CheckAssetAccountPassword verifies that the stored password of an asset account
+// is valid on the asset.
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - a: The AssetAccount whose password is checked
+//
+// Returns:
+//   - ActivityLog: Log details of the password check activity
+//   - error: An error if the password check fails or cannot be initiated
+func (c *SafeguardClient) CheckAssetAccountPassword(ctx context.Context, a AssetAccount) (ActivityLog, error) {
+	return c.runAssetAccountTask(ctx, a, "CheckPassword")
 }
 
 // CheckPassword verifies if the current password for the asset account is valid.
 // Parameters:
-//   - c: The SafeguardClient instance for making API requests
+//   - ctx: Context for cancellation and timeouts.
 //
 // Returns:
-//   - PasswordActivityLog: Log details of the password check activity
+//   - ActivityLog: Log details of the password check activity
 //   - error: An error if the password check fails or cannot be initiated
-func (a AssetAccount) CheckPassword() (ActivityLog, error) {
-	var log ActivityLog
+func (a AssetAccount) CheckPassword(ctx context.Context) (ActivityLog, error) {
+	return a.apiClient.CheckAssetAccountPassword(ctx, a)
+}
 
-	query := fmt.Sprintf("AssetAccounts/%d/CheckPassword", a.Id)
+// runAssetAccountTask starts a task such as ChangePassword on an asset account.
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - a: The AssetAccount to run the task for
+//   - task: The name of the task endpoint, e.g. "CheckPassword"
+//
+// Returns:
+//   - ActivityLog: Log details of the task
+//   - error: An error if the task fails or cannot be initiated
+func (c *SafeguardClient) runAssetAccountTask(ctx context.Context, a AssetAccount, task string) (ActivityLog, error) {
+	query := fmt.Sprintf("AssetAccounts/%d/%s", a.Id, task)
 
-	response, err := a.apiClient.PostRequest(query, nil)
+	response, err := c.PostRequest(ctx, query, nil)
 	if err != nil {
-		return log, err
+		return ActivityLog{}, err
 	}
 
-	if json.Unmarshal(response, &log) != nil {
-		return log, err
+	var log ActivityLog
+	if err := json.Unmarshal(response, &log); err != nil {
+		return ActivityLog{}, err
 	}
 
-	return addClient(a.apiClient, log), nil
+	return addClient(c, log), nil
 }
 
 // CreateAssetAccount creates a new asset account in Safeguard.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests
 //   - assetAccount: The AssetAccount object containing the account details to create
 //
 // Returns:
 //   - AssetAccount: The newly created asset account with updated fields
 //   - error: An error if the creation fails, nil otherwise
-func (c *SafeguardClient) CreateAssetAccount(assetAccount AssetAccount) (AssetAccount, error) {
-	assetAccountsBatch, err := c.batchCreateAssetAccounts([]AssetAccount{assetAccount})
+func (c *SafeguardClient) CreateAssetAccount(ctx context.Context, assetAccount AssetAccount) (AssetAccount, error) {
+	assetAccountsBatch, err := c.batchCreateAssetAccounts(ctx, []AssetAccount{assetAccount})
 	if err != nil {
 		return AssetAccount{}, err
 	}
@@ -397,14 +469,15 @@ func (c *SafeguardClient) CreateAssetAccount(assetAccount AssetAccount) (AssetAc
 
 // CreateAssetAccounts creates multiple asset accounts in a single batch request.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests
 //   - assetAccounts: A slice of AssetAccount objects to create
 //
 // Returns:
 //   - []AssetAccount: A slice of the newly created asset accounts
 //   - error: An error if any of the creations fail, nil otherwise
-func (c *SafeguardClient) CreateAssetAccounts(assetAccounts []AssetAccount) ([]AssetAccount, error) {
-	batchCreatedAccounts, err := c.batchCreateAssetAccounts(assetAccounts)
+func (c *SafeguardClient) CreateAssetAccounts(ctx context.Context, assetAccounts []AssetAccount) ([]AssetAccount, error) {
+	batchCreatedAccounts, err := c.batchCreateAssetAccounts(ctx, assetAccounts)
 	if err != nil {
 		return []AssetAccount{}, err
 	}
@@ -423,19 +496,20 @@ func (c *SafeguardClient) CreateAssetAccounts(assetAccounts []AssetAccount) ([]A
 // Returns:
 //   - AssetAccount: Created account with server-assigned fields
 //   - error: Creation errors
-func (a AssetAccount) Create() (AssetAccount, error) {
-	return a.apiClient.CreateAssetAccount(a)
+func (a AssetAccount) Create(ctx context.Context) (AssetAccount, error) {
+	return a.apiClient.CreateAssetAccount(ctx, a)
 }
 
 // UpdateAssetAccount updates an existing asset account in Safeguard.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests
 //   - assetAccount: The AssetAccount object containing the updated account details
 //
 // Returns:
 //   - AssetAccount: The updated asset account with current fields
 //   - error: An error if the update fails, nil otherwise
-func (c *SafeguardClient) UpdateAssetAccount(assetAccount AssetAccount) (AssetAccount, error) {
+func (c *SafeguardClient) UpdateAssetAccount(ctx context.Context, assetAccount AssetAccount) (AssetAccount, error) {
 	query := fmt.Sprintf("AssetAccounts/%d", assetAccount.Id)
 
 	assetAccountJSON, err := json.Marshal(assetAccount)
@@ -443,7 +517,7 @@ func (c *SafeguardClient) UpdateAssetAccount(assetAccount AssetAccount) (AssetAc
 		return AssetAccount{}, err
 	}
 
-	response, err := c.PutRequest(query, bytes.NewReader(assetAccountJSON))
+	response, err := c.PutRequest(ctx, query, bytes.NewReader(assetAccountJSON))
 	if err != nil {
 		return AssetAccount{}, err
 	}
@@ -463,12 +537,13 @@ func (c *SafeguardClient) UpdateAssetAccount(assetAccount AssetAccount) (AssetAc
 // Returns:
 //   - AssetAccount: Updated account state
 //   - error: Update errors
-func (a AssetAccount) Update() (AssetAccount, error) {
-	return a.apiClient.UpdateAssetAccount(a)
+func (a AssetAccount) Update(ctx context.Context) (AssetAccount, error) {
+	return a.apiClient.UpdateAssetAccount(ctx, a)
 }
 
 // UpdatePasswordProfile updates the password profile for an asset account.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests
 //   - assetAccount: The AssetAccount object to update
 //   - passwordPolicy: The AccountPasswordRule to apply to the account
@@ -476,7 +551,7 @@ func (a AssetAccount) Update() (AssetAccount, error) {
 // Returns:
 //   - AssetAccount: The updated asset account with the new password profile
 //   - error: An error if the update fails, nil otherwise
-func (c *SafeguardClient) UpdatePasswordProfile(assetAccount AssetAccount, passwordPolicy AccountPasswordRule) (AssetAccount, error) {
+func (c *SafeguardClient) UpdatePasswordProfile(ctx context.Context, assetAccount AssetAccount, passwordPolicy AccountPasswordRule) (AssetAccount, error) {
 	var passwordProfile Profile
 	passwordProfile.Id = passwordPolicy.Id
 	passwordProfile.Name = passwordPolicy.Name
@@ -484,7 +559,7 @@ func (c *SafeguardClient) UpdatePasswordProfile(assetAccount AssetAccount, passw
 
 	assetAccount.PasswordProfile = passwordProfile
 
-	updatedAssetAccount, err := c.UpdateAssetAccount(assetAccount)
+	updatedAssetAccount, err := c.UpdateAssetAccount(ctx, assetAccount)
 	if err != nil {
 		return AssetAccount{}, err
 	}
@@ -495,27 +570,29 @@ func (c *SafeguardClient) UpdatePasswordProfile(assetAccount AssetAccount, passw
 // UpdatePasswordProfile updates the password profile for this asset account.
 // It uses the UpdatePasswordProfile function with the current client.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - passwordPolicy: The AccountPasswordRule to apply to this account
 //
 // Returns:
 //   - AssetAccount: The updated asset account with the new password profile
 //   - error: An error if the update fails, nil otherwise
-func (a AssetAccount) UpdatePasswordProfile(passwordPolicy AccountPasswordRule) (AssetAccount, error) {
-	return a.apiClient.UpdatePasswordProfile(a, passwordPolicy)
+func (a AssetAccount) UpdatePasswordProfile(ctx context.Context, passwordPolicy AccountPasswordRule) (AssetAccount, error) {
+	return a.apiClient.UpdatePasswordProfile(ctx, a, passwordPolicy)
 }
 
 // DisableAssetAccount disables an asset account in Safeguard.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests
 //   - assetAccount: The AssetAccount to disable
 //
 // Returns:
 //   - AssetAccount: The updated asset account reflecting the disabled state
 //   - error: An error if the disable operation fails, nil otherwise
-func (c *SafeguardClient) DisableAssetAccount(assetAccount AssetAccount) (AssetAccount, error) {
+func (c *SafeguardClient) DisableAssetAccount(ctx context.Context, assetAccount AssetAccount) (AssetAccount, error) {
 	query := fmt.Sprintf("AssetAccounts/%d/Disable", assetAccount.Id)
 
-	response, err := c.PostRequest(query, nil)
+	response, err := c.PostRequest(ctx, query, nil)
 	if err != nil {
 		return AssetAccount{}, err
 	}
@@ -533,22 +610,23 @@ func (c *SafeguardClient) DisableAssetAccount(assetAccount AssetAccount) (AssetA
 // Returns:
 //   - AssetAccount: Updated account showing disabled
 //   - error: Disable operation errors
-func (a AssetAccount) Disable() (AssetAccount, error) {
-	return a.apiClient.DisableAssetAccount(a)
+func (a AssetAccount) Disable(ctx context.Context) (AssetAccount, error) {
+	return a.apiClient.DisableAssetAccount(ctx, a)
 }
 
 // EnableAssetAccount enables a previously disabled asset account in Safeguard.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests
 //   - assetAccount: The AssetAccount to enable
 //
 // Returns:
 //   - AssetAccount: The updated asset account reflecting the enabled state
 //   - error: An error if the enable operation fails, nil otherwise
-func (c *SafeguardClient) EnableAssetAccount(assetAccount AssetAccount) (AssetAccount, error) {
+func (c *SafeguardClient) EnableAssetAccount(ctx context.Context, assetAccount AssetAccount) (AssetAccount, error) {
 	query := fmt.Sprintf("AssetAccounts/%d/Enable", assetAccount.Id)
 
-	response, err := c.PostRequest(query, nil)
+	response, err := c.PostRequest(ctx, query, nil)
 	if err != nil {
 		return AssetAccount{}, err
 	}
@@ -566,24 +644,25 @@ func (c *SafeguardClient) EnableAssetAccount(assetAccount AssetAccount) (AssetAc
 // Returns:
 //   - AssetAccount: Updated account showing enabled
 //   - error: Enable operation errors
-func (a AssetAccount) Enable() (AssetAccount, error) {
-	return a.apiClient.EnableAssetAccount(a)
+func (a AssetAccount) Enable(ctx context.Context) (AssetAccount, error) {
+	return a.apiClient.EnableAssetAccount(ctx, a)
 }
 
 // batchCreateAssetAccounts handles the batch creation of multiple asset accounts.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests
 //   - accessRequests: A slice of AssetAccount objects to create in batch
 //
 // Returns:
 //   - []AssetAccountBatchResponse: A slice of responses for each account creation attempt
 //   - error: An error if the batch request fails or if any individual creation fails
-func (c *SafeguardClient) batchCreateAssetAccounts(accessRequests []AssetAccount) ([]AssetAccountBatchResponse, error) {
+func (c *SafeguardClient) batchCreateAssetAccounts(ctx context.Context, accessRequests []AssetAccount) ([]AssetAccountBatchResponse, error) {
 	requestBody, err := json.Marshal(accessRequests)
 	if err != nil {
 		return []AssetAccountBatchResponse{}, err
 	}
-	response, err := c.PostRequest("AssetAccounts/BatchCreate", bytes.NewReader(requestBody))
+	response, err := c.PostRequest(ctx, "AssetAccounts/BatchCreate", bytes.NewReader(requestBody))
 	if err != nil {
 		return []AssetAccountBatchResponse{}, err
 	}
@@ -593,14 +672,14 @@ func (c *SafeguardClient) batchCreateAssetAccounts(accessRequests []AssetAccount
 		return []AssetAccountBatchResponse{}, err
 	}
 
-	var collectedErrors error
+	var batchErrors []error
 	for i := range createdAssetAccounts {
 		if err := createdAssetAccounts[i].hasError(); err != nil {
-			collectedErrors = fmt.Errorf("%v\n%v", collectedErrors, err)
+			batchErrors = append(batchErrors, err)
 		}
 	}
 
-	if collectedErrors != nil {
+	if collectedErrors := errors.Join(batchErrors...); collectedErrors != nil {
 		return createdAssetAccounts, collectedErrors
 	}
 
@@ -609,26 +688,15 @@ func (c *SafeguardClient) batchCreateAssetAccounts(accessRequests []AssetAccount
 
 // SuspendAssetAccount suspends an asset account in Safeguard.
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: The SafeguardClient instance for making API requests
 //   - a: The AssetAccount to suspend
 //
 // Returns:
 //   - PasswordActivityLog: Log details of the suspend activity
 //   - error: An error if the suspend operation fails, nil otherwise
-func (c *SafeguardClient) SuspendAssetAccount(a AssetAccount) (ActivityLog, error) {
-	query := fmt.Sprintf("AssetAccounts/%d/SuspendAccount", a.Id)
-
-	response, err := c.PostRequest(query, nil)
-	if err != nil {
-		return ActivityLog{}, err
-	}
-
-	var log ActivityLog
-	if err := json.Unmarshal(response, &log); err != nil {
-		return ActivityLog{}, err
-	}
-
-	return addClient(c, log), nil
+func (c *SafeguardClient) SuspendAssetAccount(ctx context.Context, a AssetAccount) (ActivityLog, error) {
+	return c.runAssetAccountTask(ctx, a, "SuspendAccount")
 }
 
 // Suspend temporarily disables the account on target system.
@@ -636,6 +704,6 @@ func (c *SafeguardClient) SuspendAssetAccount(a AssetAccount) (ActivityLog, erro
 // Returns:
 //   - PasswordActivityLog: Suspension details
 //   - error: Suspend operation errors
-func (a AssetAccount) Suspend() (ActivityLog, error) {
-	return a.apiClient.SuspendAssetAccount(a)
+func (a AssetAccount) Suspend(ctx context.Context) (ActivityLog, error) {
+	return a.apiClient.SuspendAssetAccount(ctx, a)
 }
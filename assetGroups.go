@@ -2,8 +2,10 @@ package safeguard
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"time"
 )
 
@@ -70,17 +72,18 @@ func (a AssetGroup) ToJson() (string, error) {
 // GetAssetGroups retrieves all asset groups matching the specified filter criteria.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Query parameters to filter the results
 //
 // Returns:
 //   - ([]AssetGroup): Slice of matching asset groups
 //   - (error): An error if the API request fails
-func (c *SafeguardClient) GetAssetGroups(filter Filter) ([]AssetGroup, error) {
+func (c *SafeguardClient) GetAssetGroups(ctx context.Context, filter Filter) ([]AssetGroup, error) {
 	var assetGroup []AssetGroup
 
 	query := "AssetGroups" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -92,16 +95,53 @@ func (c *SafeguardClient) GetAssetGroups(filter Filter) ([]AssetGroup, error) {
 	return addClientToSlice(c, assetGroup), nil
 }
 
+// AssetGroups returns an iterator over all asset groups matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.AssetGroups(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[AssetGroup, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) AssetGroups(ctx context.Context, filter Filter) iter.Seq2[AssetGroup, error] {
+	return paginate[AssetGroup](ctx, c, "AssetGroups", filter)
+}
+
+// CountAssetGroups returns the total number of asset groups matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching asset groups
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountAssetGroups(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "AssetGroups", filter)
+}
+
 // GetAssetGroup retrieves a single asset group by its ID.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: Unique identifier of the asset group
 //   - fields: Optional fields to include in the response
 //
 // Returns:
 //   - (AssetGroup): The requested asset group
 //   - (error): An error if the API request fails
-func (c *SafeguardClient) GetAssetGroup(id int, fields Fields) (AssetGroup, error) {
+func (c *SafeguardClient) GetAssetGroup(ctx context.Context, id int, fields Fields) (AssetGroup, error) {
 	var assetGroup AssetGroup
 
 	query := fmt.Sprintf("AssetGroups/%d", id)
@@ -109,7 +149,7 @@ func (c *SafeguardClient) GetAssetGroup(id int, fields Fields) (AssetGroup, erro
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return assetGroup, err
 	}
@@ -123,13 +163,14 @@ func (c *SafeguardClient) GetAssetGroup(id int, fields Fields) (AssetGroup, erro
 // UpdateAssetGroup modifies an existing asset group.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: Unique identifier of the asset group to update
 //   - assetGroup: Modified asset group data
 //
 // Returns:
 //   - (AssetGroup): The updated asset group
 //   - (error): An error if the update fails
-func (c *SafeguardClient) UpdateAssetGroup(id int, assetGroup AssetGroup) (AssetGroup, error) {
+func (c *SafeguardClient) UpdateAssetGroup(ctx context.Context, id int, assetGroup AssetGroup) (AssetGroup, error) {
 	query := fmt.Sprintf("AssetGroups/%d", id)
 
 	assetGroupJSON, err := assetGroup.ToJson()
@@ -137,7 +178,7 @@ func (c *SafeguardClient) UpdateAssetGroup(id int, assetGroup AssetGroup) (Asset
 		return AssetGroup{}, err
 	}
 
-	response, err := c.PutRequest(query, bytes.NewReader([]byte(assetGroupJSON)))
+	response, err := c.PutRequest(ctx, query, bytes.NewReader([]byte(assetGroupJSON)))
 	if err != nil {
 		return AssetGroup{}, err
 	}
@@ -155,21 +196,22 @@ func (c *SafeguardClient) UpdateAssetGroup(id int, assetGroup AssetGroup) (Asset
 // Returns:
 //   - (AssetGroup): The updated asset group
 //   - (error): An error if the update fails
-func (a AssetGroup) Update() (AssetGroup, error) {
-	return a.apiClient.UpdateAssetGroup(a.Id, a)
+func (a AssetGroup) Update(ctx context.Context) (AssetGroup, error) {
+	return a.apiClient.UpdateAssetGroup(ctx, a.Id, a)
 }
 
 // DeleteAssetGroup removes an asset group from the system.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: Unique identifier of the asset group to delete
 //
 // Returns:
 //   - (error): An error if the deletion fails
-func (c *SafeguardClient) DeleteAssetGroup(id int) error {
+func (c *SafeguardClient) DeleteAssetGroup(ctx context.Context, id int) error {
 	query := fmt.Sprintf("AssetGroups/%d", id)
 
-	_, err := c.DeleteRequest(query)
+	_, err := c.DeleteRequest(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -181,6 +223,6 @@ func (c *SafeguardClient) DeleteAssetGroup(id int) error {
 //
 // Returns:
 //   - (error): An error if the deletion fails
-func (a AssetGroup) Delete() error {
-	return a.apiClient.DeleteAssetGroup(a.Id)
+func (a AssetGroup) Delete(ctx context.Context) error {
+	return a.apiClient.DeleteAssetGroup(ctx, a.Id)
 }
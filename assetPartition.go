@@ -1,6 +1,7 @@
 package safeguard
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -93,13 +94,14 @@ func (a AccountPasswordRule) SetClient(c *SafeguardClient) any {
 // This operation updates the asset account's password profile with the current rule.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - assetAccount: The asset account to modify
 //
 // Returns:
 //   - (AssetAccount): The updated asset account
 //   - (error): An error if the assignment fails
-func (r AccountPasswordRule) Assign(assetAccount AssetAccount) (AssetAccount, error) {
-	return r.apiClient.UpdatePasswordProfile(assetAccount, r)
+func (r AccountPasswordRule) Assign(ctx context.Context, assetAccount AssetAccount) (AssetAccount, error) {
+	return r.apiClient.UpdatePasswordProfile(ctx, assetAccount, r)
 }
 
 // ToJson serializes the AccountPasswordRule instance into a JSON string representation.
@@ -118,17 +120,18 @@ func (r AccountPasswordRule) ToJson() (string, error) {
 // GetAssetPartitions retrieves all asset partitions matching the specified filter criteria.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Query parameters to filter the results
 //
 // Returns:
 //   - ([]AssetPartition): Slice of matching asset partitions
 //   - (error): An error if the API request fails
-func (c *SafeguardClient) GetAssetPartitions(filter Filter) ([]AssetPartition, error) {
+func (c *SafeguardClient) GetAssetPartitions(ctx context.Context, filter Filter) ([]AssetPartition, error) {
 	var AssetPartitions []AssetPartition
 
 	query := "AssetPartitions" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -142,13 +145,14 @@ func (c *SafeguardClient) GetAssetPartitions(filter Filter) ([]AssetPartition, e
 // GetAssetPartition retrieves a single asset partition by its ID.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: Unique identifier of the asset partition
 //   - fields: Optional fields to include in the response
 //
 // Returns:
 //   - (AssetPartition): The requested asset partition
 //   - (error): An error if the API request fails
-func (c *SafeguardClient) GetAssetPartition(id int, fields Fields) (AssetPartition, error) {
+func (c *SafeguardClient) GetAssetPartition(ctx context.Context, id int, fields Fields) (AssetPartition, error) {
 	var AssetPartition AssetPartition
 
 	query := fmt.Sprintf("AssetPartitions/%d", id)
@@ -156,7 +160,7 @@ func (c *SafeguardClient) GetAssetPartition(id int, fields Fields) (AssetPartiti
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return AssetPartition, err
 	}
@@ -172,25 +176,26 @@ func (c *SafeguardClient) GetAssetPartition(id int, fields Fields) (AssetPartiti
 // Returns:
 //   - ([]AccountPasswordRule): Slice of password rules for this partition
 //   - (error): An error if the API request fails
-func (a AssetPartition) GetPasswordRules() ([]AccountPasswordRule, error) {
-	return a.apiClient.GetPasswordRules(a, Filter{})
+func (a AssetPartition) GetPasswordRules(ctx context.Context) ([]AccountPasswordRule, error) {
+	return a.apiClient.GetPasswordRules(ctx, a, Filter{})
 }
 
 // GetPasswordRules retrieves password rules for the specified asset partition.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - assetPartition: The partition to get rules for
 //   - filter: Query parameters to filter the results
 //
 // Returns:
 //   - ([]AccountPasswordRule): Slice of matching password rules
 //   - (error): An error if the API request fails or no rules are found
-func (c *SafeguardClient) GetPasswordRules(assetPartition AssetPartition, filter Filter) ([]AccountPasswordRule, error) {
+func (c *SafeguardClient) GetPasswordRules(ctx context.Context, assetPartition AssetPartition, filter Filter) ([]AccountPasswordRule, error) {
 	var PasswordRules []AccountPasswordRule
 
 	query := fmt.Sprintf("AssetPartitions/%d/Profiles", assetPartition.Id) + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -209,14 +214,15 @@ func (c *SafeguardClient) GetPasswordRules(assetPartition AssetPartition, filter
 // DeleteAssetPartition removes an asset partition from the system.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: Unique identifier of the asset partition to delete
 //
 // Returns:
 //   - (error): An error if the deletion fails
-func (c *SafeguardClient) DeleteAssetPartition(id int) error {
+func (c *SafeguardClient) DeleteAssetPartition(ctx context.Context, id int) error {
 	query := fmt.Sprintf("AssetPartitions/%d", id)
 
-	_, err := c.DeleteRequest(query)
+	_, err := c.DeleteRequest(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -228,6 +234,6 @@ func (c *SafeguardClient) DeleteAssetPartition(id int) error {
 //
 // Returns:
 //   - (error): An error if the deletion fails
-func (a AssetPartition) Delete() error {
-	return a.apiClient.DeleteAssetPartition(a.Id)
+func (a AssetPartition) Delete(ctx context.Context) error {
+	return a.apiClient.DeleteAssetPartition(ctx, a.Id)
 }
@@ -1,8 +1,10 @@
 package safeguard
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"strings"
 	"time"
 )
@@ -306,17 +308,18 @@ func (a Asset) ToJson() (string, error) {
 // GetAssets retrieves all assets matching the specified filter criteria.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Query parameters to filter the results
 //
 // Returns:
 //   - ([]Asset): Slice of matching assets
 //   - (error): An error if the API request fails
-func (c *SafeguardClient) GetAssets(fields Filter) ([]Asset, error) {
+func (c *SafeguardClient) GetAssets(ctx context.Context, fields Filter) ([]Asset, error) {
 	var assets []Asset
 
 	query := "Assets" + fields.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -329,16 +332,53 @@ func (c *SafeguardClient) GetAssets(fields Filter) ([]Asset, error) {
 	return addClientToSlice(c, assets), nil
 }
 
+// Assets returns an iterator over all assets matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.Assets(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[Asset, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) Assets(ctx context.Context, filter Filter) iter.Seq2[Asset, error] {
+	return paginate[Asset](ctx, c, "Assets", filter)
+}
+
+// CountAssets returns the total number of assets matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching assets
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountAssets(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "Assets", filter)
+}
+
 // GetAsset retrieves a single asset by its ID.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: Unique identifier of the asset
 //   - fields: Optional fields to include in the response
 //
 // Returns:
 //   - (Asset): The requested asset
 //   - (error): An error if the API request fails
-func (c *SafeguardClient) GetAsset(id int, fields Fields) (Asset, error) {
+func (c *SafeguardClient) GetAsset(ctx context.Context, id int, fields Fields) (Asset, error) {
 	var asset Asset
 
 	query := fmt.Sprintf("Assets/%d", id)
@@ -346,7 +386,7 @@ func (c *SafeguardClient) GetAsset(id int, fields Fields) (Asset, error) {
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return asset, err
 	}
@@ -362,18 +402,19 @@ func (c *SafeguardClient) GetAsset(id int, fields Fields) (Asset, error) {
 // GetAssetDirectoryAccounts retrieves all directory accounts associated with the specified asset.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - assetId: Unique identifier of the asset
 //   - filter: Query parameters to filter the results
 //
 // Returns:
 //   - ([]AssetAccount): Slice of matching directory accounts
 //   - (error): An error if the API request fails
-func (c *SafeguardClient) GetAssetDirectoryAccounts(assetId int, filter Filter) ([]AssetAccount, error) {
+func (c *SafeguardClient) GetAssetDirectoryAccounts(ctx context.Context, assetId int, filter Filter) ([]AssetAccount, error) {
 	var accounts []AssetAccount
 
 	query := fmt.Sprintf("Assets/%d/DirectoryAccounts%s", assetId, filter.ToQueryString())
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -389,25 +430,27 @@ func (c *SafeguardClient) GetAssetDirectoryAccounts(assetId int, filter Filter)
 // GetDirectoryAccounts retrieves all directory accounts associated with this asset.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Query parameters to filter the results
 //
 // Returns:
 //   - ([]AssetAccount): Slice of matching directory accounts
 //   - (error): An error if the API request fails
-func (a Asset) GetDirectoryAccounts(filter Filter) ([]AssetAccount, error) {
-	return a.apiClient.GetAssetDirectoryAccounts(a.Id, filter)
+func (a Asset) GetDirectoryAccounts(ctx context.Context, filter Filter) ([]AssetAccount, error) {
+	return a.apiClient.GetAssetDirectoryAccounts(ctx, a.Id, filter)
 }
 
 // GetAssetDirectoryAssets retrieves all directory assets associated with the specified asset.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - assetId: Unique identifier of the asset
 //   - filter: Query parameters to filter the results
 //
 // Returns:
 //   - ([]Asset): Slice of matching directory assets
 //   - (error): An error if the API request fails
-func (c *SafeguardClient) GetAssetDirectoryAssets(assetId int, filter Filter) ([]Asset, error) {
+func (c *SafeguardClient) GetAssetDirectoryAssets(ctx context.Context, assetId int, filter Filter) ([]Asset, error) {
 	var assets []Asset
 
 	query := fmt.Sprintf("Assets/%d/DirectoryAssets", assetId)
@@ -415,7 +458,7 @@ func (c *SafeguardClient) GetAssetDirectoryAssets(assetId int, filter Filter) ([
 		query += filter.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -431,25 +474,27 @@ func (c *SafeguardClient) GetAssetDirectoryAssets(assetId int, filter Filter) ([
 // GetDirectoryAssets retrieves all directory assets associated with this asset.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Query parameters to filter the results
 //
 // Returns:
 //   - ([]Asset): Slice of matching directory assets
 //   - (error): An error if the API request fails
-func (a Asset) GetDirectoryAssets(filter Filter) ([]Asset, error) {
-	return a.apiClient.GetAssetDirectoryAssets(a.Id, filter)
+func (a Asset) GetDirectoryAssets(ctx context.Context, filter Filter) ([]Asset, error) {
+	return a.apiClient.GetAssetDirectoryAssets(ctx, a.Id, filter)
 }
 
 // GetAssetDirectoryServiceEntries retrieves all directory service entries associated with the specified asset.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - assetId: Unique identifier of the asset
 //   - filter: Query parameters to filter the results
 //
 // Returns:
 //   - ([]DirectoryServiceEntry): Slice of matching directory service entries
 //   - (error): An error if the API request fails
-func (c *SafeguardClient) GetAssetDirectoryServiceEntries(assetId int, filter Filter) ([]DirectoryServiceEntry, error) {
+func (c *SafeguardClient) GetAssetDirectoryServiceEntries(ctx context.Context, assetId int, filter Filter) ([]DirectoryServiceEntry, error) {
 	var entries []DirectoryServiceEntry
 
 	query := fmt.Sprintf("Assets/%d/DirectoryServiceEntries", assetId)
@@ -457,7 +502,7 @@ func (c *SafeguardClient) GetAssetDirectoryServiceEntries(assetId int, filter Fi
 		query += filter.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -473,22 +518,23 @@ func (c *SafeguardClient) GetAssetDirectoryServiceEntries(assetId int, filter Fi
 // GetDirectoryServiceEntries retrieves all directory service entries associated with this asset.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Query parameters to filter the results
 //
 // Returns:
 //   - ([]DirectoryServiceEntry): Slice of matching directory service entries
 //   - (error): An error if the API request fails
-func (a Asset) GetDirectoryServiceEntries(filter Filter) ([]DirectoryServiceEntry, error) {
-	return a.apiClient.GetAssetDirectoryServiceEntries(a.Id, filter)
+func (a Asset) GetDirectoryServiceEntries(ctx context.Context, filter Filter) ([]DirectoryServiceEntry, error) {
+	return a.apiClient.GetAssetDirectoryServiceEntries(ctx, a.Id, filter)
 }
 
 // Delete removes the asset identified by its ID from the system.
 // It constructs a query string using the asset's ID and sends a DELETE request
 // to the API client. If the request fails, it returns an error; otherwise, it returns nil.
-func (a Asset) Delete() error {
+func (a Asset) Delete(ctx context.Context) error {
 	query := fmt.Sprintf("Assets/%d", a.Id)
 
-	_, err := a.apiClient.DeleteRequest(query)
+	_, err := a.apiClient.DeleteRequest(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -502,13 +548,14 @@ func (a Asset) Delete() error {
 // If there is an error during the process, it returns an empty asset and the error.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The ID of the asset to be updated.
 //   - updatedAsset: The Asset struct containing the updated asset details.
 //
 // Returns:
 //   - Asset: The updated Asset struct.
 //   - error: An error if the update process fails, otherwise nil.
-func (c *SafeguardClient) UpdateAsset(id int, updatedAsset Asset) (Asset, error) {
+func (c *SafeguardClient) UpdateAsset(ctx context.Context, id int, updatedAsset Asset) (Asset, error) {
 	query := fmt.Sprintf("Assets/%d", id)
 
 	assetJSON, err := updatedAsset.ToJson()
@@ -516,7 +563,7 @@ func (c *SafeguardClient) UpdateAsset(id int, updatedAsset Asset) (Asset, error)
 		return Asset{}, err
 	}
 
-	response, err := c.PutRequest(query, strings.NewReader(assetJSON))
+	response, err := c.PutRequest(ctx, query, strings.NewReader(assetJSON))
 	if err != nil {
 		return Asset{}, err
 	}
@@ -533,8 +580,8 @@ func (c *SafeguardClient) UpdateAsset(id int, updatedAsset Asset) (Asset, error)
 // Update updates the current Asset with the provided updatedAsset and returns the updated Asset.
 // It uses the apiClient to perform the update operation based on the Asset's Id.
 // Returns the updated Asset and an error if the update operation fails.
-func (a Asset) Update(updatedAsset Asset) (Asset, error) {
-	return a.apiClient.UpdateAsset(a.Id, updatedAsset)
+func (a Asset) Update(ctx context.Context, updatedAsset Asset) (Asset, error) {
+	return a.apiClient.UpdateAsset(ctx, a.Id, updatedAsset)
 }
 
 // GetAccounts retrieves a list of accounts associated with the asset.
@@ -542,17 +589,18 @@ func (a Asset) Update(updatedAsset Asset) (Asset, error) {
 // The function returns a slice of AssetAccount and an error if any occurs during the process.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: A Filter object containing fields to filter the accounts.
 //
 // Returns:
 //   - []AssetAccount: A slice of AssetAccount objects.
 //   - error: An error object if an error occurs, otherwise nil.
-func (a Asset) GetAccounts(filter Filter) ([]AssetAccount, error) {
+func (a Asset) GetAccounts(ctx context.Context, filter Filter) ([]AssetAccount, error) {
 	var accounts []AssetAccount
 
 	query := fmt.Sprintf("Assets/%d/Accounts%s", a.Id, filter.ToQueryString())
 
-	response, err := a.apiClient.GetRequest(query)
+	response, err := a.apiClient.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
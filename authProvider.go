@@ -1,6 +1,7 @@
 package safeguard
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -81,12 +82,12 @@ func (a AuthenticationProvider) ToJson() (string, error) {
 // Returns:
 //   - []AuthenticationProvider: A slice containing all configured authentication providers
 //   - error: An error if the API request fails or the response cannot be parsed
-func (c *SafeguardClient) GetAuthenticationProviders() ([]AuthenticationProvider, error) {
+func (c *SafeguardClient) GetAuthenticationProviders(ctx context.Context) ([]AuthenticationProvider, error) {
 	var authProviders []AuthenticationProvider
 
 	query := "AuthenticationProviders"
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return []AuthenticationProvider{}, err
 	}
@@ -102,17 +103,18 @@ func (c *SafeguardClient) GetAuthenticationProviders() ([]AuthenticationProvider
 // Use this to get detailed information about a single provider configuration.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the authentication provider to retrieve
 //
 // Returns:
 //   - AuthenticationProvider: The requested authentication provider's configuration
 //   - error: An error if the provider cannot be found or the request fails
-func (c *SafeguardClient) GetAuthenticationProvider(id int) (AuthenticationProvider, error) {
+func (c *SafeguardClient) GetAuthenticationProvider(ctx context.Context, id int) (AuthenticationProvider, error) {
 	var authProvider AuthenticationProvider
 
 	query := fmt.Sprintf("AuthenticationProviders/%d", id)
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return AuthenticationProvider{}, err
 	}
@@ -128,10 +130,10 @@ func (c *SafeguardClient) GetAuthenticationProvider(id int) (AuthenticationProvi
 //
 // Returns:
 //   - error: An error if the operation fails or the API request is unsuccessful
-func (c *SafeguardClient) ClearDefaultAuthProvider() error {
+func (c *SafeguardClient) ClearDefaultAuthProvider(ctx context.Context) error {
 	query := "AuthenticationProviders/ClearDefault"
 
-	_, err := c.PostRequest(query, nil)
+	_, err := c.PostRequest(ctx, query, nil)
 	if err != nil {
 		return err
 	}
@@ -143,16 +145,17 @@ func (c *SafeguardClient) ClearDefaultAuthProvider() error {
 // Only one provider can be the default at any time.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the authentication provider to set as default
 //
 // Returns:
 //   - AuthenticationProvider: The updated authentication provider configuration
 //   - error: An error if the operation fails or the provider cannot be found
-func (c *SafeguardClient) ForceAsDefaultAuthProvider(id int) (AuthenticationProvider, error) {
+func (c *SafeguardClient) ForceAsDefaultAuthProvider(ctx context.Context, id int) (AuthenticationProvider, error) {
 	var authProvider AuthenticationProvider
 	query := fmt.Sprintf("AuthenticationProviders/%d/ForceAsDefault", id)
 
-	response, err := c.PostRequest(query, nil)
+	response, err := c.PostRequest(ctx, query, nil)
 	if err != nil {
 		return AuthenticationProvider{}, err
 	}
@@ -171,6 +174,6 @@ func (c *SafeguardClient) ForceAsDefaultAuthProvider(id int) (AuthenticationProv
 // Returns:
 //   - AuthenticationProvider: The updated authentication provider configuration
 //   - error: An error if the operation fails or the API request is unsuccessful
-func (a AuthenticationProvider) ForceAsDefault() (AuthenticationProvider, error) {
-	return a.apiClient.ForceAsDefaultAuthProvider(a.Id)
+func (a AuthenticationProvider) ForceAsDefault(ctx context.Context) (AuthenticationProvider, error) {
+	return a.apiClient.ForceAsDefaultAuthProvider(ctx, a.Id)
 }
@@ -2,6 +2,7 @@ package safeguard
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/tls"
@@ -9,11 +10,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -75,6 +74,123 @@ func (a *RSTSAuthResponse) setUserToken(userToken string) {
 	a.UserToken = userToken
 }
 
+// setRSTSToken safely stores the token of an RSTS token response.
+//
+// Parameters:
+//   - token: The decoded token response
+func (a *RSTSAuthResponse) setRSTSToken(token *RSTSAuthResponse) {
+	a.RWMutex.Lock()
+	defer a.RWMutex.Unlock()
+	a.AccessToken = token.AccessToken
+	a.TokenType = token.TokenType
+	a.ExpiresIn = token.ExpiresIn
+	a.RefreshToken = token.RefreshToken
+	a.Scope = token.Scope
+	a.AuthProvider = AuthProvider(token.Scope)
+}
+
+// setToken safely stores a token returned by a TokenSource.
+//
+// Parameters:
+//   - token: The token to store
+//   - authTime: The time the token was received
+func (a *RSTSAuthResponse) setToken(token *Token, authTime time.Time) {
+	a.RWMutex.Lock()
+	defer a.RWMutex.Unlock()
+	a.UserToken = token.UserToken
+	a.RefreshToken = token.RefreshToken
+	a.AuthTime = authTime
+	if token.AuthProvider != "" {
+		a.AuthProvider = token.AuthProvider
+	}
+	a.ExpiresIn = 0
+	if !token.Expiry.IsZero() {
+		a.ExpiresIn = int(token.Expiry.Sub(authTime) / time.Second)
+	}
+}
+
+// token safely returns the user token and its renewal state as a Token.
+func (a *RSTSAuthResponse) token() *Token {
+	a.RWMutex.RLock()
+	defer a.RWMutex.RUnlock()
+	token := &Token{UserToken: a.UserToken, RefreshToken: a.RefreshToken, AuthProvider: a.AuthProvider}
+	if a.ExpiresIn > 0 {
+		token.Expiry = a.AuthTime.Add(time.Duration(a.ExpiresIn) * time.Second)
+	}
+	return token
+}
+
+// setAuthProvider safely sets the authentication provider of the next token.
+//
+// Parameters:
+//   - provider: The authentication provider
+func (a *RSTSAuthResponse) setAuthProvider(provider AuthProvider) {
+	a.RWMutex.Lock()
+	defer a.RWMutex.Unlock()
+	a.AuthProvider = provider
+}
+
+// getAuthProvider safely retrieves the authentication provider of the token.
+func (a *RSTSAuthResponse) getAuthProvider() AuthProvider {
+	a.RWMutex.RLock()
+	defer a.RWMutex.RUnlock()
+	return a.AuthProvider
+}
+
+// getRefreshToken safely retrieves the RSTS refresh token.
+func (a *RSTSAuthResponse) getRefreshToken() string {
+	a.RWMutex.RLock()
+	defer a.RWMutex.RUnlock()
+	return a.RefreshToken
+}
+
+// keepRenewalState restores the refresh token and authentication provider of the
+// previous token if a refresh token response did not contain new ones.
+//
+// Parameters:
+//   - refreshToken: The refresh token that was redeemed
+//   - authProvider: The authentication provider of the previous token
+func (a *RSTSAuthResponse) keepRenewalState(refreshToken string, authProvider AuthProvider) {
+	a.RWMutex.Lock()
+	defer a.RWMutex.Unlock()
+	if a.RefreshToken == "" {
+		a.RefreshToken = refreshToken
+	}
+	if a.AuthProvider == "" {
+		a.AuthProvider = authProvider
+	}
+}
+
+// setLogin safely stores the Safeguard user token of a login and the time it was received.
+//
+// Parameters:
+//   - userToken: The new user token to store
+//   - authTime: The time the token was received
+func (a *RSTSAuthResponse) setLogin(userToken string, authTime time.Time) {
+	a.RWMutex.Lock()
+	defer a.RWMutex.Unlock()
+	a.UserToken = userToken
+	a.AuthTime = authTime
+}
+
+// getAuthTime safely retrieves the time the user token was received.
+func (a *RSTSAuthResponse) getAuthTime() time.Time {
+	a.RWMutex.RLock()
+	defer a.RWMutex.RUnlock()
+	return a.AuthTime
+}
+
+// getLifetime safely retrieves the time the token was received and how long it is valid.
+//
+// Returns:
+//   - time.Time: The time the token was received
+//   - time.Duration: The lifetime of the token
+func (a *RSTSAuthResponse) getLifetime() (time.Time, time.Duration) {
+	a.RWMutex.RLock()
+	defer a.RWMutex.RUnlock()
+	return a.AuthTime, time.Duration(a.ExpiresIn) * time.Second
+}
+
 // setUserNamePassword safely stores username and password credentials.
 //
 // Parameters:
@@ -121,7 +237,8 @@ func (a *RSTSAuthResponse) getCertificate() (string, string) {
 	return a.credentials.certPath, a.credentials.certPassword
 }
 
-// Credentials stores various authentication credentials securely.
+// Credentials stores the credentials of the last login. They are only kept if
+// the client replays logins to renew its token, see WithCredentialReplay.
 type Credentials struct {
 	username     string
 	password     string
@@ -130,50 +247,108 @@ type Credentials struct {
 }
 
 // LoginWithOauth initiates the OAuth2.0 authorization code flow to obtain an access token.
-// It generates a code challenge and starts a TCP listener to receive the authorization code.
-// The user is prompted to log in using their browser, and upon successful login, the authorization code
-// is exchanged for an access token.
+// It generates a code challenge and starts a local HTTP listener on port 8400 to receive
+// the authorization code. The user is prompted to log in using their browser, and upon
+// successful login, the authorization code is exchanged for an access token.
+// The login fails if no callback is received within five minutes; use
+// LoginWithOauthContext to control the timeout and the listener.
 // Returns an error if the authentication or token exchange process fails.
 func (c *SafeguardClient) LoginWithOauth() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultOAuthTimeout)
+	defer cancel()
+	return c.LoginWithOauthContext(ctx)
+}
+
+// LoginWithOauthContext performs the OAuth2.0 authorization code flow with PKCE.
+// It opens the RSTS login page in the browser and receives the authorization code
+// with a local HTTP listener. Only the callback carrying the state of the login
+// ends it; forged callbacks are rejected and the login keeps waiting.
+//
+// Parameters:
+//   - ctx: Context bounding the whole login, including the time the user takes.
+//   - opts: Options, e.g. WithOAuthCallbackAddress("127.0.0.1:0") for a free port.
+//
+// Returns:
+//   - error: An *OAuthError if RSTS reports an error such as denied consent, or
+//     an error if ctx ends first or the token exchange fails.
+func (c *SafeguardClient) LoginWithOauthContext(ctx context.Context, opts ...OAuthOption) (err error) {
+	ctx, end := c.traceLogin(ctx, LoginMethodOAuth)
+	defer end(&err)
+
+	o := oauthOptions{
+		address: fmt.Sprintf("localhost:%d", c.redirectPort),
+		openBrowser: func(authURL string) error {
+			openBrowser(c.logger(), authURL)
+			return nil
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return c.login(ctx, LoginMethodOAuth, func(ctx context.Context) error {
+		return c.loginWithOauth(ctx, o)
+	}, nil)
+}
+
+// loginWithOauth performs the OAuth login of LoginWithOauthContext.
+func (c *SafeguardClient) loginWithOauth(ctx context.Context, o oauthOptions) error {
 	if c.AccessToken == nil {
 		c.AccessToken = &RSTSAuthResponse{}
 	}
 
 	codeVerifier, codeChallenge := generateCodeChallenge()
 
-	authCodeChan := make(chan string)
-	errorChan := make(chan error)
-	listener := startTCPListener(authCodeChan, errorChan)
+	listener, err := c.newCallbackListener(o.address)
+	if err != nil {
+		return err
+	}
 	defer listener.Close()
 
 	redirectURI := "urn:InstalledApplicationTcpListener"
-	authURL := fmt.Sprintf("%s/RSTS/Login?response_type=code&code_challenge_method=S256&code_challenge=%s&redirect_uri=%s&port=%d",
-		c.Appliance.getUrl(), codeChallenge, url.QueryEscape(redirectURI), c.redirectPort)
+	authURL := fmt.Sprintf("%s/RSTS/Login?response_type=code&code_challenge_method=S256&code_challenge=%s&redirect_uri=%s&port=%d&state=%s",
+		c.Appliance.getUrl(), codeChallenge, url.QueryEscape(redirectURI), listener.port(), listener.state)
 
-	openBrowser(authURL)
-	logger.Info("Please log in using your browser...")
+	if err := o.openBrowser(authURL); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+	c.logger().Info("Please log in using your browser...")
 
-	select {
-	case authCode := <-authCodeChan:
-		c.AccessToken.AuthorizationCode = authCode
-		logger.Info("Authorization Code received")
-	case err := <-errorChan:
-		return fmt.Errorf("authentication failed: %v", err)
+	authCode, err := listener.wait(ctx)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
 	}
+	c.AccessToken.AuthorizationCode = authCode
+	c.logger().Info("Authorization Code received")
 
+	return c.redeemAuthorizationCode(ctx, c.AccessToken.AuthorizationCode, redirectURI, codeVerifier)
+}
+
+// redeemAuthorizationCode exchanges an OAuth authorization code for an RSTS
+// token and the RSTS token for a Safeguard token.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the requests.
+//   - code: The authorization code returned by RSTS.
+//   - redirectURI: The redirect URI the code was requested with.
+//   - codeVerifier: The PKCE code verifier of the code challenge.
+//
+// Returns:
+//   - error: An error if a request fails.
+func (c *SafeguardClient) redeemAuthorizationCode(ctx context.Context, code, redirectURI, codeVerifier string) error {
 	data := url.Values{}
 	data.Set("grant_type", "authorization_code")
-	data.Set("code", c.AccessToken.AuthorizationCode)
+	data.Set("code", code)
 	data.Set("redirect_uri", redirectURI)
 	data.Set("code_verifier", codeVerifier)
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/RSTS/oauth2/token", c.Appliance.getUrl()), strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/RSTS/oauth2/token", c.Appliance.getUrl()), strings.NewReader(data.Encode()))
 	if err != nil {
 		return fmt.Errorf("error creating request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := c.HttpClient.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("RSTS token request failed: %v", err)
 	}
@@ -184,14 +359,12 @@ func (c *SafeguardClient) LoginWithOauth() error {
 		return fmt.Errorf("error retrieving token: %v", err)
 	}
 
-	err = c.exchangeRSTSTokenForSafeguard(c.HttpClient)
+	err = c.exchangeRSTSTokenForSafeguard(ctx, c.httpClient())
 	if err != nil {
 		return fmt.Errorf("acquire Safeguard token failed: %v", err)
 	}
 
-	logger.Info("Access Token received")
-	c.authDone <- "Done"
-
+	c.logger().Info("Access Token received")
 	return nil
 }
 
@@ -199,7 +372,6 @@ func generateCodeChallenge() (string, string) {
 	verifier := make([]byte, 32)
 	_, err := rand.Read(verifier)
 	if err != nil {
-		logger.Error("Error generating Code Verifier", "error", err)
 		panic(fmt.Sprintf("Error generating Code Verifier: %v", err))
 	}
 	codeVerifier := base64.RawURLEncoding.EncodeToString(verifier)
@@ -210,70 +382,6 @@ func generateCodeChallenge() (string, string) {
 	return codeVerifier, codeChallenge
 }
 
-func startTCPListener(authCodeChan chan string, errorChan chan error) net.Listener {
-	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", redirectPort))
-	if err != nil {
-		logger.Error("Error starting TCP listener", "error", err)
-		errorChan <- err
-		return nil
-	}
-
-	go func() {
-		conn, err := listener.Accept()
-		if err != nil {
-			errorChan <- err
-			return
-		}
-		defer conn.Close()
-
-		buffer := make([]byte, 4096)
-		n, err := conn.Read(buffer)
-		if err != nil {
-			errorChan <- err
-			return
-		}
-
-		request := string(buffer[:n])
-		logger.Debug("Received request", "request", request)
-
-		authCode := extractAuthCode(request)
-		if authCode != "" {
-			// Simple HTTP success response
-			response := "HTTP/1.1 200 OK\r\nConnection: close\r\n\r\nAuthentication successful"
-			conn.Write([]byte(response))
-			authCodeChan <- authCode
-		} else {
-			errorChan <- fmt.Errorf("no authorization code received in request: %s", request)
-		}
-	}()
-
-	return listener
-}
-
-func extractAuthCode(request string) string {
-	re := regexp.MustCompile(`GET /\?(.+) HTTP`)
-	match := re.FindStringSubmatch(request)
-	if len(match) < 2 {
-		logger.Error("No URL parameters found in request")
-		return ""
-	}
-
-	params, err := url.ParseQuery(match[1])
-	if err != nil {
-		logger.Error("Failed to parse query parameters", "error", err)
-		return ""
-	}
-
-	// Look specifically for the 'oauth' parameter
-	if code := params.Get("oauth"); code != "" {
-		logger.Debug("Found oauth code", "code", code[:30]+"...") // Log first 30 chars
-		return code
-	}
-
-	logger.Error("No oauth parameter found in query", "params", params)
-	return ""
-}
-
 // LoginWithPassword authenticates a user using their username and password.
 // It first obtains an RSTS token and then exchanges it for a Safeguard token.
 // The obtained token is stored in the SafeguardClient's AccessToken field.
@@ -291,27 +399,75 @@ func extractAuthCode(request string) string {
 //   - Token storage and management
 //   - Error handling and logging
 func (c *SafeguardClient) LoginWithPassword(username, password string) error {
+	return c.LoginWithPasswordContext(context.Background(), username, password)
+}
+
+// LoginWithPasswordContext is LoginWithPassword with a context, which bounds the
+// login requests and carries the trace the login belongs to.
+//
+// Parameters:
+//   - ctx: Context for cancellation, deadlines and tracing of the login.
+//   - username: The username of the user.
+//   - password: The password of the user.
+//
+// Returns:
+//   - error: An error if the login process fails, otherwise nil.
+func (c *SafeguardClient) LoginWithPasswordContext(ctx context.Context, username, password string) (err error) {
+	ctx, end := c.traceLogin(ctx, LoginMethodPassword)
+	defer end(&err)
+
+	return c.login(ctx, LoginMethodPassword, func(ctx context.Context) error {
+		if err := c.loginWithPassword(ctx, username, password); err != nil {
+			return err
+		}
+		if c.replay {
+			c.AccessToken.setUserNamePassword(username, password)
+		}
+		return nil
+	}, c.credentialReplay(func(ctx context.Context) error {
+		username, password := c.AccessToken.getUserNamePassword()
+		return c.loginWithPassword(ctx, username, password)
+	}))
+}
+
+// loginWithPassword performs the password login of LoginWithPassword.
+func (c *SafeguardClient) loginWithPassword(ctx context.Context, username, password string) error {
+	return c.loginWithResourceOwner(ctx, AuthProviderLocal, username, password)
+}
+
+// loginWithResourceOwner logs in with the OAuth resource owner password grant
+// against an authentication provider.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the requests.
+//   - provider: The authentication provider of the user.
+//   - username: The username of the user.
+//   - password: The password of the user.
+//
+// Returns:
+//   - error: An error if the login fails.
+func (c *SafeguardClient) loginWithResourceOwner(ctx context.Context, provider AuthProvider, username, password string) error {
 	if c.AccessToken == nil {
 		c.AccessToken = &RSTSAuthResponse{
-			AuthProvider: AuthProviderLocal,
+			AuthProvider: provider,
 		}
 	} else {
-		c.AccessToken.AuthProvider = AuthProviderLocal
+		c.AccessToken.setAuthProvider(provider)
 	}
 
 	data := url.Values{}
 	data.Set("grant_type", "password")
 	data.Set("username", username)
 	data.Set("password", password)
-	data.Set("scope", AuthProviderLocal.String())
+	data.Set("scope", provider.String())
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/RSTS/oauth2/token", c.Appliance.getUrl()), strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/RSTS/oauth2/token", c.Appliance.getUrl()), strings.NewReader(data.Encode()))
 	if err != nil {
 		return fmt.Errorf("error creating request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := c.HttpClient.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("RSTS login request failed: %v", err)
 	}
@@ -322,14 +478,12 @@ func (c *SafeguardClient) LoginWithPassword(username, password string) error {
 		return fmt.Errorf("RSTS login failed: %v", err)
 	}
 
-	err = c.exchangeRSTSTokenForSafeguard(c.HttpClient)
+	err = c.exchangeRSTSTokenForSafeguard(ctx, c.httpClient())
 	if err != nil {
 		return fmt.Errorf("token exchange failed: %v", err)
 	}
 
-	c.AccessToken.setUserNamePassword(username, password)
-	logger.Info("Login successful")
-	c.authDone <- "Done"
+	c.logger().Info("Login successful")
 	return nil
 }
 
@@ -340,54 +494,188 @@ func (c *SafeguardClient) LoginWithPassword(username, password string) error {
 // - authProvider: The authentication provider to use (e.g. "certificate")
 // Returns an error if the authentication fails.
 func (c *SafeguardClient) LoginWithCertificate(certPath, certPassword string) error {
-	if c.AccessToken == nil {
-		c.AccessToken = &RSTSAuthResponse{
-			AuthProvider: AuthProviderCertificate,
+	return c.LoginWithCertificateContext(context.Background(), certPath, certPassword)
+}
+
+// LoginWithCertificateContext is LoginWithCertificate with a context, which
+// bounds the login requests and carries the trace the login belongs to.
+//
+// Parameters:
+//   - ctx: Context for cancellation, deadlines and tracing of the login.
+//   - certPath: Path to the PKCS12 certificate file.
+//   - certPassword: Password for the certificate.
+//
+// Returns:
+//   - error: An error if the certificate cannot be read or the login fails.
+func (c *SafeguardClient) LoginWithCertificateContext(ctx context.Context, certPath, certPassword string) (err error) {
+	ctx, end := c.traceLogin(ctx, LoginMethodCertificate)
+	defer end(&err)
+
+	return c.login(ctx, LoginMethodCertificate, func(ctx context.Context) error {
+		if err := c.loginWithCertificate(ctx, certPath, certPassword); err != nil {
+			return err
 		}
-	} else {
-		c.AccessToken.AuthProvider = AuthProviderCertificate
-	}
+		if c.replay {
+			c.AccessToken.setCertificate(certPath, certPassword)
+		}
+		return nil
+	}, c.credentialReplay(func(ctx context.Context) error {
+		certPath, certPassword := c.AccessToken.getCertificate()
+		return c.loginWithCertificate(ctx, certPath, certPassword)
+	}))
+}
 
+// loginWithCertificate performs the certificate login of LoginWithCertificate.
+func (c *SafeguardClient) loginWithCertificate(ctx context.Context, certPath, certPassword string) error {
 	// Read client certificate
 	certData, err := os.ReadFile(certPath)
 	if err != nil {
 		return fmt.Errorf("read certificate file failed: %v", err)
 	}
 
-	// Create TLS config with PKCS12 cert
-	tlsConfig, err := tLSConfigForPKCS12(certData, certPassword)
+	return c.loginWithPKCS12(ctx, certData, certPassword)
+}
+
+// loginWithPKCS12 logs in with a PKCS12 encoded client certificate.
+func (c *SafeguardClient) loginWithPKCS12(ctx context.Context, certData []byte, certPassword string) error {
+	cert, err := loadPKCS12(certData, certPassword)
 	if err != nil {
 		return fmt.Errorf("create tls config failed: %v", err)
 	}
 
-	if c.HttpClient.Transport == nil {
-		c.HttpClient.Transport = &http.Transport{
-			TLSClientConfig: tlsConfig,
+	return c.loginWithTLSCertificate(ctx, cert)
+}
+
+// loginWithTLSCertificate logs in with a client certificate whose private key
+// is held in memory or behind a crypto.Signer.
+func (c *SafeguardClient) loginWithTLSCertificate(ctx context.Context, cert tls.Certificate) error {
+	if c.AccessToken == nil {
+		c.AccessToken = &RSTSAuthResponse{
+			AuthProvider: AuthProviderCertificate,
 		}
 	} else {
-		if transport, ok := c.HttpClient.Transport.(*http.Transport); ok {
-			transport.TLSClientConfig.Certificates = tlsConfig.Certificates
-			transport.TLSClientConfig.Renegotiation = tls.RenegotiateFreelyAsClient
-		} else {
-			return fmt.Errorf("existing transport is not an *http.Transport")
-		}
+		c.AccessToken.setAuthProvider(AuthProviderCertificate)
+	}
+
+	client, err := c.certificateClient(cert)
+	if err != nil {
+		return err
 	}
 
 	// Get RSTS token
-	err = c.getRSTSTokenWithCert(c.HttpClient, AuthProviderCertificate)
+	err = c.getRSTSTokenWithCert(ctx, client, AuthProviderCertificate)
 	if err != nil {
 		return fmt.Errorf("acquire RSTS token failed: %v", err)
 	}
 
 	// Exchange for Safeguard token
-	err = c.exchangeRSTSTokenForSafeguard(c.HttpClient)
+	err = c.exchangeRSTSTokenForSafeguard(ctx, client)
 	if err != nil {
 		return fmt.Errorf("acquire Safeguard token failed: %v", err)
 	}
+	c.useCertificateClient(client)
 
-	c.AccessToken.setCertificate(certPath, certPassword)
-	logger.Info("Certificate authentication successful")
-	c.authDone <- "Done"
+	c.logger().Info("Certificate authentication successful")
+	return nil
+}
+
+// certificateClient returns a copy of HttpClient that presents cert in the TLS
+// handshakes. The transport is cloned, because it may belong to the caller, see
+// WithHTTPClient and WithTransport; without one, http.DefaultTransport is
+// cloned. The client of the last certificate login is reused if it presents the
+// same certificate, e.g. when the login is replayed.
+func (c *SafeguardClient) certificateClient(cert tls.Certificate) (*http.Client, error) {
+	if current := c.certClient.Load(); current != nil {
+		certs := current.Transport.(*http.Transport).TLSClientConfig.Certificates
+		if bytes.Equal(certs[0].Certificate[0], cert.Certificate[0]) {
+			return current, nil
+		}
+	}
+
+	base := c.HttpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	previous, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("existing transport is not an *http.Transport")
+	}
+	transport := previous.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	if transport.TLSClientConfig.MinVersion == 0 {
+		transport.TLSClientConfig.MinVersion = tls.VersionTLS12
+	}
+	transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	transport.TLSClientConfig.Renegotiation = tls.RenegotiateFreelyAsClient
+
+	client := *c.HttpClient
+	client.Transport = transport
+	return &client, nil
+}
+
+// useCertificateClient makes client serve all further requests. Requests that
+// are in flight finish on the client they started with.
+func (c *SafeguardClient) useCertificateClient(client *http.Client) {
+	if previous := c.certClient.Swap(client); previous != nil && previous != client {
+		// The client of an earlier certificate login is no longer used
+		previous.CloseIdleConnections()
+	}
+}
+
+// httpClient returns the HTTP client for requests: the client of the last
+// certificate login if there is one and HttpClient otherwise.
+func (c *SafeguardClient) httpClient() *http.Client {
+	if client := c.certClient.Load(); client != nil {
+		return client
+	}
+	return c.HttpClient
+}
+
+// loginWithRefreshToken renews the token with the OAuth refresh_token grant of
+// RSTS and exchanges the new RSTS token for a Safeguard token. The refresh token
+// is kept if RSTS does not rotate it.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the requests.
+//
+// Returns:
+//   - error: errNoRefreshToken if RSTS issued no refresh token, or an error if a
+//     request fails.
+func (c *SafeguardClient) loginWithRefreshToken(ctx context.Context) error {
+	refreshToken := c.AccessToken.getRefreshToken()
+	if refreshToken == "" {
+		return errNoRefreshToken
+	}
+	authProvider := c.AccessToken.getAuthProvider()
+
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", refreshToken)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/RSTS/oauth2/token", c.Appliance.getUrl()), strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("RSTS refresh request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := c.handleTokenResponse(resp); err != nil {
+		return fmt.Errorf("RSTS refresh failed: %w", err)
+	}
+	c.AccessToken.keepRenewalState(refreshToken, authProvider)
+
+	if err := c.exchangeRSTSTokenForSafeguard(ctx, c.httpClient()); err != nil {
+		return fmt.Errorf("token exchange failed: %w", err)
+	}
+
+	c.logger().Debug("Token renewed with refresh token")
 	return nil
 }
 
@@ -396,13 +684,14 @@ func (c *SafeguardClient) LoginWithCertificate(certPath, certPassword string) er
 // The function returns the access token as a string or an error if the request fails.
 //
 // Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
 //   - client: An HTTP client to send the request.
 //   - authProvider: An AuthProvider instance that provides the scope for the request.
 //
 // Returns:
 //   - A string containing the access token.
 //   - An error if the request fails or if there is an issue with the response.
-func (c *SafeguardClient) getRSTSTokenWithCert(client *http.Client, authProvider AuthProvider) error {
+func (c *SafeguardClient) getRSTSTokenWithCert(ctx context.Context, client *http.Client, authProvider AuthProvider) error {
 	requestBody := struct {
 		GrantType string `json:"grant_type"`
 		Scope     string `json:"scope"`
@@ -416,7 +705,7 @@ func (c *SafeguardClient) getRSTSTokenWithCert(client *http.Client, authProvider
 		return fmt.Errorf("failed to marshal request body: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/RSTS/oauth2/token", c.Appliance.getUrl()), bytes.NewBuffer(bodyBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/RSTS/oauth2/token", c.Appliance.getUrl()), bytes.NewBuffer(bodyBytes))
 	if err != nil {
 		return err
 	}
@@ -442,12 +731,13 @@ func (c *SafeguardClient) getRSTSTokenWithCert(client *http.Client, authProvider
 // for a Safeguard token. It constructs the request payload, sends the request, and processes the response.
 //
 // Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
 //   - client: The HTTP client used to send the request.
 //
 // Returns:
 //   - A pointer to an RSTSAuthResponse containing the Safeguard token information.
 //   - An error if the request fails or the response cannot be processed.
-func (c *SafeguardClient) exchangeRSTSTokenForSafeguard(client *http.Client) error {
+func (c *SafeguardClient) exchangeRSTSTokenForSafeguard(ctx context.Context, client *http.Client) error {
 	// tokenReq required because LoginResponse need AccessToken as StsAccessToken
 	tokenReq := struct {
 		StsAccessToken string `json:"StsAccessToken"`
@@ -460,7 +750,7 @@ func (c *SafeguardClient) exchangeRSTSTokenForSafeguard(client *http.Client) err
 		return fmt.Errorf("error marshaling token request: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/service/core/v4/Token/LoginResponse", c.Appliance.getUrl()), bytes.NewBuffer(tokenData))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/service/core/v4/Token/LoginResponse", c.Appliance.getUrl()), bytes.NewBuffer(tokenData))
 	if err != nil {
 		return err
 	}
@@ -482,8 +772,7 @@ func (c *SafeguardClient) exchangeRSTSTokenForSafeguard(client *http.Client) err
 		return err
 	}
 
-	c.AccessToken.setUserToken(safeguardResponse.UserToken)
-	c.AccessToken.AuthTime = time.Now()
+	c.AccessToken.setLogin(safeguardResponse.UserToken, time.Now())
 
 	return nil
 }
@@ -506,30 +795,68 @@ func (c *SafeguardClient) handleTokenResponse(resp *http.Response) error {
 		return fmt.Errorf("token request failed: %s", string(body))
 	}
 
-	var tokenResp *RSTSAuthResponse
+	var tokenResp RSTSAuthResponse
 	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&tokenResp); err != nil {
 		return err
 	}
 
-	c.AccessToken = tokenResp
-	c.AccessToken.AuthProvider = AuthProvider(tokenResp.Scope)
+	// Update the token in place, requests may read it concurrently
+	c.AccessToken.setRSTSToken(&tokenResp)
 	return nil
 }
 
-// SaveAccessTokenToEnv saves the current access token to an environment variable.
-// This allows persistence of the token across sessions.
+// Logout revokes the user token of the client on the appliance and removes the
+// tokens from the client. Requests sent afterwards fail until the client logs in
+// again.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
+//
+// Returns:
+//   - error: An error if the request fails, or an *APIError if the appliance
+//     rejects the logout.
+func (c *SafeguardClient) Logout(ctx context.Context) error {
+	url := fmt.Sprintf("%s/service/core/%s/Token/Logout", c.Appliance.getUrl(), c.ApiVersion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header = c.getAuthorizationHeader()
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("logout failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if !isSuccessStatus(resp.StatusCode) && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(req, resp.StatusCode, body)
+	}
+
+	c.AccessToken.setUserToken("")
+	c.AccessToken.setAccessToken("")
+	c.logger().Info("Logged out")
+	return c.deleteCachedToken(ctx)
+}
+
+// SaveAccessTokenToEnv saves the current user token to the SAFEGUARD_ACCESS_TOKEN
+// environment variable of the current process. The variable is lost when the
+// process exits.
+//
+// Deprecated: Use WithTokenCache to keep the token across process restarts.
 //
 // Returns:
 //   - error: An error if saving the token fails.
 func (c *SafeguardClient) SaveAccessTokenToEnv() error {
 	envVar := "SAFEGUARD_ACCESS_TOKEN"
-	err := os.Setenv(envVar, c.AccessToken.getAccessToken())
+	err := os.Setenv(envVar, c.AccessToken.getUserToken())
 	if err != nil {
-		logger.Error("Error saving access token to environment variable", "error", err)
+		c.logger().Error("Error saving access token to environment variable", "error", err)
 		return err
 	}
 
-	logger.Info("Access token saved to environment variable", "envVar", envVar)
+	c.logger().Info("Access token saved to environment variable", "envVar", envVar)
 	return nil
 }
 
@@ -538,21 +865,25 @@ func (c *SafeguardClient) SaveAccessTokenToEnv() error {
 //
 // Returns:
 //   - error: An error if the token is invalid or validation fails.
-func (c *SafeguardClient) ValidateAccessToken() error {
+func (c *SafeguardClient) ValidateAccessToken(ctx context.Context) error {
+	if err := c.ensureToken(ctx); err != nil {
+		c.AccessToken.isValid = false
+		return err
+	}
 	if c.AccessToken.getUserToken() == "" {
 		c.AccessToken.isValid = false
 		return fmt.Errorf("access token is empty")
 	}
 
-	logger.Debug("Token validation",
+	c.logger().Debug("Token validation",
 		"length", len(c.AccessToken.getUserToken()),
 		"formatCheck", strings.HasPrefix(c.AccessToken.getUserToken(), "ey"))
 
 	fields := []string{"id"}
-	err := c.testAccessToken(fields...)
+	err := c.testAccessToken(ctx, fields...)
 	if err != nil {
 		c.AccessToken.isValid = false
-		return fmt.Errorf("invalid access token: %v", err)
+		return fmt.Errorf("invalid access token: %w", err)
 	}
 	c.AccessToken.isValid = true
 	return nil
@@ -563,12 +894,13 @@ func (c *SafeguardClient) ValidateAccessToken() error {
 //
 // Parameters:
 //
+//	ctx - Context for cancellation and timeouts.
 //	fields - Optional list of fields to include in the query.
 //
 // Returns:
 //
 //	error - An error if the request fails, otherwise nil.
-func (c *SafeguardClient) testAccessToken(fields ...string) error {
+func (c *SafeguardClient) testAccessToken(ctx context.Context, fields ...string) error {
 	query := "me"
 	if len(fields) > 0 {
 		query += "?fields=" + fields[0]
@@ -576,7 +908,7 @@ func (c *SafeguardClient) testAccessToken(fields ...string) error {
 			query += "," + field
 		}
 	}
-	_, err := c.GetRequest(query)
+	_, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,398 @@
+package safeguard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CassetteMode selects whether a CassetteTransport records or replays requests.
+type CassetteMode int
+
+const (
+	// CassetteReplay serves the responses stored in the cassette file without
+	// contacting a server.
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord sends requests to the server and stores every request and
+	// response in the cassette file.
+	CassetteRecord
+)
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the redacted form of a request stored in a cassette.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the redacted form of a response stored in a cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// cassetteFile is the on-disk format of a cassette.
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// secretFields are JSON properties and form fields whose values are never
+// written to a cassette. Names are compared case-insensitively.
+var secretFields = []string{
+	"password",
+	"newpassword",
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"code",
+	"code_verifier",
+	"client_secret",
+	"accesstoken",
+	"usertoken",
+	"stsaccesstoken",
+	"privatekey",
+	"passphrase",
+}
+
+// secretHeaders are headers whose values are never written to a cassette.
+var secretHeaders = []string{"Cookie", "Set-Cookie"}
+
+// CassetteTransport is an http.RoundTripper that records API traffic to a
+// cassette file and replays it later, so that code using the client can be
+// tested without an appliance. Secrets are redacted before anything is written:
+// Authorization headers are masked like SafeHeaders, password responses like
+// SafeResponseBody, and token and password fields in JSON and form bodies are
+// replaced entirely.
+//
+// In replay mode, each request is answered with the first unused interaction
+// that has the same method, path, query and body and sends the headers stored
+// with the recorded request. Recorded values are compared with the redacted and
+// with the original request, so hand-written cassettes can use non-secret
+// fixture values that must then match exactly. Use Redact to drop headers that
+// change on every request, such as request IDs. The scheme and host are
+// ignored, so a cassette recorded against one appliance can be replayed with
+// any appliance URL.
+//
+// Example:
+//
+//	transport, err := safeguard.NewCassetteTransport("testdata/assets.json", safeguard.CassetteReplay)
+//	if err != nil {
+//	    return err
+//	}
+//	client.HttpClient = &http.Client{Transport: transport}
+type CassetteTransport struct {
+	// Transport sends requests in record mode. If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// Redact is applied to every interaction after the built-in redaction, both
+	// when recording and when matching requests during replay.
+	Redact func(*Interaction)
+
+	mu           sync.Mutex
+	path         string
+	mode         CassetteMode
+	interactions []Interaction
+	used         []bool
+}
+
+// NewCassetteTransport creates a transport that records to or replays from a cassette file.
+//
+// Parameters:
+//   - path: The cassette file. In record mode it is created or overwritten on the
+//     first request; in replay mode it must exist.
+//   - mode: CassetteRecord or CassetteReplay.
+//
+// Returns:
+//   - *CassetteTransport: The transport, ready to be used in an http.Client.
+//   - error: An error if the cassette cannot be read in replay mode.
+func NewCassetteTransport(path string, mode CassetteMode) (*CassetteTransport, error) {
+	t := &CassetteTransport{path: path, mode: mode}
+	if mode != CassetteReplay {
+		return t, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	t.interactions = file.Interactions
+	t.used = make([]bool, len(file.Interactions))
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.mode == CassetteRecord {
+		return t.record(req, reqBody)
+	}
+	return t.replay(req, reqBody)
+}
+
+// record sends the request and appends the redacted interaction to the cassette.
+func (t *CassetteTransport) record(req *http.Request, reqBody []byte) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	// The length of a redacted body differs, it is set again on replay
+	header := resp.Header.Clone()
+	header.Del("Content-Length")
+
+	interaction := t.redact(Interaction{
+		Request: newRecordedRequest(req, reqBody),
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       string(respBody),
+		},
+	}, req.URL.Path)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.interactions = append(t.interactions, interaction)
+	if err := t.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replay answers the request with the first matching unused interaction.
+func (t *CassetteTransport) replay(req *http.Request, reqBody []byte) (*http.Response, error) {
+	sent := newRecordedRequest(req, reqBody)
+	want := t.redact(Interaction{Request: newRecordedRequest(req, reqBody)}, req.URL.Path).Request
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.interactions {
+		if t.used[i] || !matchesRequest(interaction.Request, want, sent) {
+			continue
+		}
+		t.used[i] = true
+
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette %s has no recorded interaction for %s %s", t.path, want.Method, want.URL)
+}
+
+// matchesRequest reports whether a request matches a recorded one. URL, body
+// and headers may match either the redacted or the original request.
+//
+// Parameters:
+//   - recorded: The request stored in the cassette.
+//   - redacted: The redacted form of the request being sent.
+//   - sent: The original form of the request being sent.
+//
+// Returns:
+//   - bool: true if the recorded interaction answers the request.
+func matchesRequest(recorded, redacted, sent RecordedRequest) bool {
+	if recorded.Method != redacted.Method || recorded.URL != redacted.URL && recorded.URL != sent.URL {
+		return false
+	}
+	if recorded.Body != redacted.Body && recorded.Body != sent.Body {
+		return false
+	}
+	for key, values := range recorded.Header {
+		if !slices.Equal(values, redacted.Header.Values(key)) && !slices.Equal(values, sent.Header.Values(key)) {
+			return false
+		}
+	}
+	return true
+}
+
+// save writes all interactions to the cassette file. The caller must hold t.mu.
+func (t *CassetteTransport) save() error {
+	data, err := json.MarshalIndent(cassetteFile{Interactions: t.interactions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(t.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// redact removes secrets from an interaction.
+//
+// Parameters:
+//   - i: The interaction to redact.
+//   - path: The URL path of the request, used to recognise password responses.
+//
+// Returns:
+//   - Interaction: The redacted interaction.
+func (t *CassetteTransport) redact(i Interaction, path string) Interaction {
+	i.Request.Header = redactHeaders(i.Request.Header)
+	i.Request.Body = redactBody(i.Request.Body)
+	i.Response.Header = redactHeaders(i.Response.Header)
+	i.Response.Body = NewSafeResponseBody([]byte(redactBody(i.Response.Body)), path).LogValue().String()
+
+	if t.Redact != nil {
+		t.Redact(&i)
+	}
+	return i
+}
+
+// newRecordedRequest captures the parts of a request that are stored in a cassette.
+// Only the path and query of the URL are kept.
+func newRecordedRequest(req *http.Request, body []byte) RecordedRequest {
+	return RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+		Header: req.Header.Clone(),
+		Body:   string(body),
+	}
+}
+
+// readRequestBody reads the request body and replaces it so it can be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// redactHeaders masks credentials in headers.
+func redactHeaders(header http.Header) http.Header {
+	for key, values := range header {
+		switch {
+		case strings.EqualFold(key, "Authorization"):
+			for i, value := range values {
+				values[i] = maskAuthorization(value)
+			}
+		case slices.ContainsFunc(secretHeaders, func(h string) bool { return strings.EqualFold(h, key) }):
+			for i := range values {
+				values[i] = "***"
+			}
+		}
+	}
+	return header
+}
+
+// redactBody replaces the values of secret fields in JSON and form encoded bodies.
+// Other bodies are returned unchanged.
+func redactBody(body string) string {
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" {
+		return body
+	}
+
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		var v any
+		if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+			return body
+		}
+		if !redactJSON(v) {
+			return body
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+		return string(data)
+	}
+
+	values, err := url.ParseQuery(trimmed)
+	if err != nil || strings.ContainsAny(trimmed, " \n\"") {
+		return body
+	}
+	redacted := false
+	for key := range values {
+		if isSecretField(key) {
+			values[key] = []string{"***"}
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+	return values.Encode()
+}
+
+// redactJSON replaces secret string values in a decoded JSON document in place.
+//
+// Returns:
+//   - bool: true if anything was replaced.
+func redactJSON(v any) bool {
+	redacted := false
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if _, ok := value.(string); ok && isSecretField(key) {
+				v[key] = "***"
+				redacted = true
+				continue
+			}
+			redacted = redactJSON(value) || redacted
+		}
+	case []any:
+		for _, value := range v {
+			redacted = redactJSON(value) || redacted
+		}
+	}
+	return redacted
+}
+
+// isSecretField reports whether a field holds a secret.
+func isSecretField(name string) bool {
+	return slices.ContainsFunc(secretFields, func(f string) bool { return strings.EqualFold(f, name) })
+}
@@ -0,0 +1,238 @@
+package safeguard
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LoginWithPKCS12 logs in with a PKCS12 certificate held in memory, e.g. one
+// loaded from a secret store. Unlike LoginWithCertificate nothing is read from
+// disk, also not when the login is repeated.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the login.
+//   - pfx: The PKCS12 encoded certificate and private key.
+//   - password: Password for the certificate.
+//
+// Returns:
+//   - error: An error if the certificate cannot be loaded or the login fails.
+func (c *SafeguardClient) LoginWithPKCS12(ctx context.Context, pfx []byte, password string) (err error) {
+	ctx, end := c.traceLogin(ctx, LoginMethodCertificate)
+	defer end(&err)
+
+	cert, err := loadPKCS12(pfx, password)
+	if err != nil {
+		return fmt.Errorf("create tls config failed: %w", err)
+	}
+	return c.loginWithClientCertificate(ctx, cert)
+}
+
+// LoginWithPEM logs in with a PEM encoded certificate and private key, e.g. the
+// tls.crt and tls.key of a Kubernetes TLS secret. See PEMCertificate for the
+// supported formats.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the login.
+//   - certPEM: The certificate, followed by its intermediate certificates.
+//   - keyPEM: The private key; nil if certPEM also contains the key.
+//   - keyPassword: Password of an encrypted private key; empty otherwise.
+//
+// Returns:
+//   - error: An error if the certificate cannot be loaded or the login fails.
+func (c *SafeguardClient) LoginWithPEM(ctx context.Context, certPEM, keyPEM []byte, keyPassword string) (err error) {
+	ctx, end := c.traceLogin(ctx, LoginMethodCertificate)
+	defer end(&err)
+
+	cert, err := PEMCertificate(certPEM, keyPEM, keyPassword)
+	if err != nil {
+		return fmt.Errorf("create tls config failed: %w", err)
+	}
+	return c.loginWithClientCertificate(ctx, cert)
+}
+
+// LoginWithSigner logs in with a certificate whose private key never leaves
+// signer, e.g. a key in a TPM, an HSM or a cloud KMS.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the login.
+//   - chain: The certificate, followed by its intermediate certificates.
+//   - signer: The private key of the certificate. It must support the signature
+//     schemes of TLS, e.g. RSA-PSS for RSA keys.
+//
+// Returns:
+//   - error: An error if signer does not match the certificate or the login fails.
+func (c *SafeguardClient) LoginWithSigner(ctx context.Context, chain []*x509.Certificate, signer crypto.Signer) (err error) {
+	ctx, end := c.traceLogin(ctx, LoginMethodCertificate)
+	defer end(&err)
+
+	cert, err := SignerCertificate(chain, signer)
+	if err != nil {
+		return fmt.Errorf("create tls config failed: %w", err)
+	}
+	return c.loginWithClientCertificate(ctx, cert)
+}
+
+// LoginWithTLSCertificate logs in with a client certificate, e.g. one from
+// tls.LoadX509KeyPair, PEMCertificate or SignerCertificate.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the login.
+//   - cert: The certificate chain and its private key, which must implement
+//     crypto.Signer.
+//
+// Returns:
+//   - error: An error if the private key does not match the certificate or the
+//     login fails.
+func (c *SafeguardClient) LoginWithTLSCertificate(ctx context.Context, cert tls.Certificate) (err error) {
+	ctx, end := c.traceLogin(ctx, LoginMethodCertificate)
+	defer end(&err)
+
+	cert, err = checkClientCertificate(cert)
+	if err != nil {
+		return fmt.Errorf("create tls config failed: %w", err)
+	}
+	return c.loginWithClientCertificate(ctx, cert)
+}
+
+// loginWithClientCertificate logs in with cert and repeats the login with the
+// same certificate if credentials are replayed.
+func (c *SafeguardClient) loginWithClientCertificate(ctx context.Context, cert tls.Certificate) error {
+	login := func(ctx context.Context) error {
+		return c.loginWithTLSCertificate(ctx, cert)
+	}
+	return c.login(ctx, LoginMethodCertificate, login, c.credentialReplay(login))
+}
+
+// PEMCertificate builds a client certificate from PEM encoded data. The
+// certificates are taken from "CERTIFICATE" blocks, the private key from a
+// PKCS8, PKCS1 or SEC1 block. Encrypted PKCS8 keys ("ENCRYPTED PRIVATE KEY")
+// with PBES2 and AES-CBC, the default of OpenSSL, are decrypted with
+// keyPassword; legacy encrypted PEM blocks are not supported.
+//
+// Parameters:
+//   - certPEM: The certificate, followed by its intermediate certificates.
+//   - keyPEM: The private key; nil if certPEM also contains the key.
+//   - keyPassword: Password of an encrypted private key; empty otherwise.
+//
+// Returns:
+//   - tls.Certificate: The certificate with its private key.
+//   - error: ErrKeyPassword if the password is wrong, or an error if no
+//     certificate or key is found or the key does not match the certificate.
+func PEMCertificate(certPEM, keyPEM []byte, keyPassword string) (tls.Certificate, error) {
+	var cert tls.Certificate
+
+	for _, data := range [][]byte{certPEM, keyPEM} {
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+
+			switch {
+			case block.Type == "CERTIFICATE":
+				cert.Certificate = append(cert.Certificate, block.Bytes)
+			case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+				if cert.PrivateKey != nil {
+					return tls.Certificate{}, errors.New("found multiple private keys in PEM data")
+				}
+				key, err := parsePEMPrivateKey(block, keyPassword)
+				if err != nil {
+					return tls.Certificate{}, fmt.Errorf("failed parsing private key: %w", err)
+				}
+				cert.PrivateKey = key
+			}
+		}
+	}
+
+	if len(cert.Certificate) == 0 {
+		return tls.Certificate{}, errors.New("no certificates found in PEM data")
+	}
+	if cert.PrivateKey == nil {
+		return tls.Certificate{}, errors.New("no private key found in PEM data")
+	}
+	return checkClientCertificate(cert)
+}
+
+// parsePEMPrivateKey parses the private key of a PEM block and decrypts it with
+// password if it is an encrypted PKCS8 key.
+func parsePEMPrivateKey(block *pem.Block, password string) (crypto.PrivateKey, error) {
+	if block.Headers["Proc-Type"] == "4,ENCRYPTED" {
+		return nil, errors.New("legacy encrypted PEM keys are not supported, convert the key to encrypted PKCS8")
+	}
+	if block.Type != "ENCRYPTED PRIVATE KEY" {
+		return parsePrivateKey(block.Bytes)
+	}
+
+	if password == "" {
+		return nil, errors.New("private key is encrypted but no password was given")
+	}
+	der, err := decryptPKCS8(block.Bytes, password)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		// Valid padding by chance, but still the wrong password
+		return nil, ErrKeyPassword
+	}
+	return key, nil
+}
+
+// SignerCertificate builds a client certificate whose private key operations
+// are performed by signer, so the key itself never has to be in memory or on
+// disk.
+//
+// Parameters:
+//   - chain: The certificate, followed by its intermediate certificates.
+//   - signer: The private key of the certificate.
+//
+// Returns:
+//   - tls.Certificate: The certificate using signer as its private key.
+//   - error: An error if chain is empty or signer does not match the certificate.
+func SignerCertificate(chain []*x509.Certificate, signer crypto.Signer) (tls.Certificate, error) {
+	if len(chain) == 0 {
+		return tls.Certificate{}, errors.New("no certificate given")
+	}
+	if signer == nil {
+		return tls.Certificate{}, errors.New("no signer given")
+	}
+
+	cert := tls.Certificate{PrivateKey: signer, Leaf: chain[0]}
+	for _, c := range chain {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+	return checkClientCertificate(cert)
+}
+
+// checkClientCertificate verifies that the private key of cert can sign and
+// belongs to its leaf certificate, and sets the parsed leaf.
+func checkClientCertificate(cert tls.Certificate) (tls.Certificate, error) {
+	if len(cert.Certificate) == 0 {
+		return tls.Certificate{}, errors.New("no certificates found")
+	}
+	signer, ok := cert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return tls.Certificate{}, errors.New("private key is missing or does not implement crypto.Signer")
+	}
+
+	if cert.Leaf == nil {
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed parsing certificate: %w", err)
+		}
+		cert.Leaf = leaf
+	}
+
+	public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !public.Equal(cert.Leaf.PublicKey) {
+		return tls.Certificate{}, fmt.Errorf("private key does not match the certificate of %s", cert.Leaf.Subject)
+	}
+	return cert, nil
+}
@@ -0,0 +1,370 @@
+package safeguard
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The certificate fixtures in testdata/certs belong to the user "svc-cert".
+// client-encrypted.key and client.pfx use the password "secret".
+func readCertFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "certs", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return data
+}
+
+// newCertTestClient returns a client for f served over TLS that requires a
+// client certificate, as the certificate login of RSTS does.
+func newCertTestClient(t *testing.T, f *fakeAuthServer, opts ...ClientOption) *SafeguardClient {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(f.mux)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	client, err := NewClientWithOptions(server.URL, append(opts, WithHTTPClient(server.Client()))...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.ClusterLeader.setUrl(server.URL, -1)
+	t.Cleanup(func() { client.Close(context.Background()) })
+	return client
+}
+
+// opaqueSigner hides the concrete key type, like the signer of an HSM.
+type opaqueSigner struct {
+	crypto.Signer
+}
+
+// fixtureSigner returns the certificate and key of the fixtures as a chain and
+// an opaque signer.
+func fixtureSigner(t *testing.T) ([]*x509.Certificate, crypto.Signer) {
+	t.Helper()
+
+	cert, err := PEMCertificate(readCertFixture(t, "client.crt"), readCertFixture(t, "client.key"), "")
+	if err != nil {
+		t.Fatalf("PEMCertificate() error = %v", err)
+	}
+	return []*x509.Certificate{cert.Leaf}, opaqueSigner{cert.PrivateKey.(crypto.Signer)}
+}
+
+func TestPEMCertificate(t *testing.T) {
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherDER, err := x509.MarshalPKCS8PrivateKey(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: otherDER})
+
+	certPEM := readCertFixture(t, "client.crt")
+	keyPEM := readCertFixture(t, "client.key")
+	encryptedPEM := readCertFixture(t, "client-encrypted.key")
+
+	tests := []struct {
+		name     string
+		certPEM  []byte
+		keyPEM   []byte
+		password string
+		wantErr  error // nil for any error if failing is set
+		failing  bool
+	}{
+		{name: "separate key", certPEM: certPEM, keyPEM: keyPEM},
+		{name: "key in certificate data", certPEM: append(append([]byte{}, certPEM...), keyPEM...)},
+		{name: "encrypted key", certPEM: certPEM, keyPEM: encryptedPEM, password: "secret"},
+		{name: "wrong password", certPEM: certPEM, keyPEM: encryptedPEM, password: "wrong", wantErr: ErrKeyPassword, failing: true},
+		{name: "missing password", certPEM: certPEM, keyPEM: encryptedPEM, failing: true},
+		{name: "missing key", certPEM: certPEM, failing: true},
+		{name: "missing certificate", keyPEM: keyPEM, failing: true},
+		{name: "key of another certificate", certPEM: certPEM, keyPEM: otherPEM, failing: true},
+		{name: "two keys", certPEM: append(append([]byte{}, certPEM...), keyPEM...), keyPEM: keyPEM, failing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert, err := PEMCertificate(tt.certPEM, tt.keyPEM, tt.password)
+			if (err != nil) != tt.failing {
+				t.Fatalf("PEMCertificate() error = %v, failing %v", err, tt.failing)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && cert.Leaf.Subject.CommonName != "svc-cert" {
+				t.Errorf("unexpected leaf %s", cert.Leaf.Subject)
+			}
+		})
+	}
+}
+
+func TestSignerCertificate(t *testing.T) {
+	chain, signer := fixtureSigner(t)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		chain   []*x509.Certificate
+		signer  crypto.Signer
+		wantErr bool
+	}{
+		{name: "matching signer", chain: chain, signer: signer},
+		{name: "signer of another key", chain: chain, signer: otherKey, wantErr: true},
+		{name: "no certificate", signer: signer, wantErr: true},
+		{name: "no signer", chain: chain, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert, err := SignerCertificate(tt.chain, tt.signer)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SignerCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cert.PrivateKey != tt.signer {
+				t.Errorf("expected the signer as private key")
+			}
+		})
+	}
+}
+
+func TestCertificateLogins(t *testing.T) {
+	tests := []struct {
+		name  string
+		login func(ctx context.Context, t *testing.T, c *SafeguardClient) error
+	}{
+		{
+			name: "PKCS12",
+			login: func(ctx context.Context, t *testing.T, c *SafeguardClient) error {
+				return c.LoginWithPKCS12(ctx, readCertFixture(t, "client.pfx"), "secret")
+			},
+		},
+		{
+			name: "PEM",
+			login: func(ctx context.Context, t *testing.T, c *SafeguardClient) error {
+				return c.LoginWithPEM(ctx, readCertFixture(t, "client.crt"), readCertFixture(t, "client-encrypted.key"), "secret")
+			},
+		},
+		{
+			name: "TLS certificate",
+			login: func(ctx context.Context, t *testing.T, c *SafeguardClient) error {
+				cert, err := tls.X509KeyPair(readCertFixture(t, "client.crt"), readCertFixture(t, "client.key"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return c.LoginWithTLSCertificate(ctx, cert)
+			},
+		},
+		{
+			name: "signer",
+			login: func(ctx context.Context, t *testing.T, c *SafeguardClient) error {
+				chain, signer := fixtureSigner(t)
+				return c.LoginWithSigner(ctx, chain, signer)
+			},
+		},
+		{
+			name: "token source",
+			login: func(ctx context.Context, t *testing.T, c *SafeguardClient) error {
+				chain, signer := fixtureSigner(t)
+				cert, err := SignerCertificate(chain, signer)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return c.LoginWithTokenSource(ctx, TLSCertificateTokenSource(cert))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeAuthServer(t, 3600, false)
+			client := newCertTestClient(t, f)
+			ctx := context.Background()
+
+			if err := tt.login(ctx, t, client); err != nil {
+				t.Fatalf("login error = %v", err)
+			}
+			if name, err := me(ctx, client); err != nil || name != "svc-cert" {
+				t.Errorf("expected svc-cert, got %q (%v)", name, err)
+			}
+		})
+	}
+}
+
+func TestLoginWithTLSCertificateWithoutKey(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, false)
+	client := newCertTestClient(t, f)
+
+	cert, err := PEMCertificate(readCertFixture(t, "client.crt"), readCertFixture(t, "client.key"), "")
+	if err != nil {
+		t.Fatalf("PEMCertificate() error = %v", err)
+	}
+	cert.PrivateKey = nil
+
+	if err := client.LoginWithTLSCertificate(context.Background(), cert); err == nil {
+		t.Error("expected an error for a certificate without private key")
+	}
+	if got := f.loginCount(); got != 0 {
+		t.Errorf("expected no login, got %d", got)
+	}
+}
+
+func TestCertificateLoginReplay(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, false)
+	client := newCertTestClient(t, f, WithCredentialReplay())
+	ctx := context.Background()
+
+	if err := client.LoginWithPEM(ctx, readCertFixture(t, "client.crt"), readCertFixture(t, "client.key"), ""); err != nil {
+		t.Fatalf("LoginWithPEM() error = %v", err)
+	}
+	f.revokeTokens()
+
+	if name, err := me(ctx, client); err != nil || name != "svc-cert" {
+		t.Errorf("expected svc-cert, got %q (%v)", name, err)
+	}
+	if got := f.loginCount(); got != 2 {
+		t.Errorf("expected the login to be replayed, got %d logins", got)
+	}
+}
+
+func TestCertificateLoginKeepsCallerTransport(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, false)
+	server := httptest.NewUnstartedServer(f.mux)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	callerClient := server.Client()
+	callerTransport := callerClient.Transport.(*http.Transport)
+	client, err := NewClientWithOptions(server.URL, WithHTTPClient(callerClient))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.ClusterLeader.setUrl(server.URL, -1)
+	t.Cleanup(func() { client.Close(context.Background()) })
+
+	ctx := context.Background()
+	if err := client.LoginWithPEM(ctx, readCertFixture(t, "client.crt"), readCertFixture(t, "client.key"), ""); err != nil {
+		t.Fatalf("LoginWithPEM() error = %v", err)
+	}
+	if name, err := me(ctx, client); err != nil || name != "svc-cert" {
+		t.Errorf("expected svc-cert, got %q (%v)", name, err)
+	}
+
+	if callerClient.Transport != callerTransport {
+		t.Error("the transport of the caller's client was replaced")
+	}
+	if len(callerTransport.TLSClientConfig.Certificates) != 0 || callerTransport.TLSClientConfig.Renegotiation != tls.RenegotiateNever {
+		t.Error("the TLS config of the caller's transport was modified")
+	}
+}
+
+// selfSignedCertificate returns a throwaway client certificate for the user cn.
+func selfSignedCertificate(t *testing.T, cn string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestCertificateReloginDuringRequests(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, false)
+	client := newCertTestClient(t, f)
+	ctx := context.Background()
+
+	// Alternating certificates make every login build a new transport
+	certs := []tls.Certificate{selfSignedCertificate(t, "svc-a"), selfSignedCertificate(t, "svc-b")}
+	if err := client.LoginWithTLSCertificate(ctx, certs[0]); err != nil {
+		t.Fatalf("LoginWithTLSCertificate() error = %v", err)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if _, err := me(ctx, client); err != nil {
+					t.Errorf("request during re-login failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := range 10 {
+		if err := client.LoginWithTLSCertificate(ctx, certs[(i+1)%2]); err != nil {
+			t.Errorf("re-login %d error = %v", i, err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	if name, err := me(ctx, client); err != nil || name != "svc-a" {
+		t.Errorf("expected svc-a after the last login, got %q (%v)", name, err)
+	}
+}
+
+func TestCertificateClientWithoutTransport(t *testing.T) {
+	client := &SafeguardClient{HttpClient: &http.Client{}}
+	cert := selfSignedCertificate(t, "svc-a")
+
+	certClient, err := client.certificateClient(cert)
+	if err != nil {
+		t.Fatalf("certificateClient() error = %v", err)
+	}
+	transport := certClient.Transport.(*http.Transport)
+	if transport.Proxy == nil {
+		t.Error("expected the proxy settings of http.DefaultTransport")
+	}
+	config := transport.TLSClientConfig
+	if config.MinVersion != tls.VersionTLS12 || config.Renegotiation != tls.RenegotiateFreelyAsClient || len(config.Certificates) != 1 {
+		t.Errorf("unexpected TLS config: MinVersion %x, Renegotiation %v, %d certificates", config.MinVersion, config.Renegotiation, len(config.Certificates))
+	}
+	if client.HttpClient.Transport != nil {
+		t.Error("HttpClient was modified")
+	}
+}
@@ -2,7 +2,6 @@ package safeguard
 
 import (
 	"context"
-	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
@@ -12,21 +11,21 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-var logger *slog.Logger // Declare global logger variable
+// logger is the package-wide fallback logger set by SetLogger. When nil,
+// clients without their own logger use slog.Default().
+var logger *slog.Logger
 
-func init() {
-	logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
-}
-
-// SetLogger configures the global logger for the safeguard package.
-// This allows other packages to set up logging consistently by providing
-// a fully configured logger instance.
+// SetLogger configures the fallback logger for clients that are created without
+// their own logger, such as clients created by NewClient or by
+// NewClientWithOptions without WithLogger. The logger is captured when a client
+// is created, and slog.Default() is never modified.
 //
 // Parameters:
-//   - l: The slog.Logger instance to use. If nil, creates a default logger with Info level.
+//   - l: The slog.Logger instance to use. If nil, slog.Default() is used.
 //
 // Example usage from another package:
 //
@@ -41,20 +40,27 @@ func init() {
 //	logger := slog.With("service", "my-app", "version", "1.0.0")
 //	safeguard.SetLogger(logger)
 func SetLogger(l *slog.Logger) {
-	if l == nil {
-		logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
-	} else {
-		logger = l
-	}
-	slog.SetDefault(logger) // Set as default logger for the entire application
+	logger = l
 }
 
-// GetLogger returns the current logger instance.
-// This allows other packages to access the configured logger.
+// GetLogger returns the fallback logger configured with SetLogger, or
+// slog.Default() if none was set.
 func GetLogger() *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
 	return logger
 }
 
+// logger returns the logger of the client, falling back to the package logger
+// for clients that were not created through a constructor.
+func (c *SafeguardClient) logger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return GetLogger()
+}
+
 const (
 	redirectPort = 8400 // Default redirect port for Authentication Callback
 )
@@ -62,17 +68,30 @@ const (
 // SafeguardClient represents the main client for interacting with the Safeguard API.
 // It handles authentication, request routing, and session management.
 type SafeguardClient struct {
-	AccessToken    *RSTSAuthResponse
-	Appliance      applianceURL
-	ClusterLeader  applianceURL
-	ApiVersion     string
-	HttpClient     *http.Client
-	tokenEndpoint  string
-	redirectPort   int
-	DefaultHeaders http.Header
-	authDone       chan string
-	Logger         *slog.Logger
-	SignalRClient  *EventHandler
+	AccessToken     *RSTSAuthResponse
+	Appliance       applianceURL
+	ClusterLeader   applianceURL
+	ApiVersion      string
+	HttpClient      *http.Client
+	certClient      atomic.Pointer[http.Client] // HttpClient with the certificate of the last certificate login
+	tokenEndpoint   string
+	redirectPort    int
+	DefaultHeaders  http.Header
+	RetryPolicy     *RetryPolicy    // Retry behaviour for transient failures; nil disables retries
+	Middlewares     []Middleware    // Middlewares wrapping every API request, see Use
+	Routing         RoutingPolicy   // Which node serves reads and writes
+	Instrumentation Instrumentation // Receives traces and metrics; nil disables instrumentation
+	readLimiter     *requestLimiter
+	writeLimiter    *requestLimiter
+	failover        *clusterFailover
+	authDone        chan struct{}
+	session         session
+	replay          bool        // Whether logins may be repeated with stored credentials
+	tokenSource     TokenSource // Logs in before the first request, see WithTokenSource
+	tokenCache      *clientTokenCache
+	lifecycle       clientLifecycle
+	Logger          *slog.Logger
+	SignalRClient   *EventHandler
 }
 
 // applianceURL represents a Safeguard appliance URL with thread-safe access
@@ -104,29 +123,39 @@ func (a *applianceURL) getUrl() string {
 // Parameters:
 //   - url: The complete URL string to set
 //   - cacheTime: Duration for which the URL should be cached. Use -1 for infinite cache.
-func (a *applianceURL) setUrl(url string, cacheTime time.Duration) {
+//
+// Returns:
+//   - error: An error if the URL cannot be parsed; the stored URL is left unchanged.
+func (a *applianceURL) setUrl(url string, cacheTime time.Duration) error {
 	a.RWMutex.Lock()
 	defer a.RWMutex.Unlock()
 
-	var err error
-	a.Protocol, a.Hostname, a.DomainName, a.Port, err = splitApplianceURL(url)
+	protocol, hostname, domainName, port, err := splitApplianceURL(url)
 	if err != nil {
-		logger.Error("Failed to split appliance URL", "error", err)
-		return
+		return fmt.Errorf("failed to split appliance URL: %w", err)
 	}
 
+	a.Protocol, a.Hostname, a.DomainName, a.Port = protocol, hostname, domainName, port
 	a.Url = url
 	a.lastUpdate = time.Now()
 	a.cacheTime = cacheTime
+	return nil
 }
 
 // Returns a pointer to a SafeguardClient instance.
-// NewClient creates a new instance of SafeguardClient. It initializes the logger with the specified
-// debug level and sets it as the default logger. If an existing client instance (sgclient)
-// already exists, it returns that instance. Otherwise, it creates a new SafeguardClient with
+// NewClient creates a new instance of SafeguardClient. Unless a logger was configured
+// with SetLogger, the client gets its own text logger with the specified debug level. It creates a new SafeguardClient with
 // the provided appliance URL, API version, and other necessary configurations. It also starts
 // a goroutine to refresh the token periodically.
 //
+// NewClient trusts the system roots only; to trust certificate files in the
+// current working directory, use NewClientWithOptions with
+// WithCurrentDirectoryCertificates. It does not keep login credentials in
+// memory: if RSTS issues no refresh token, the user has to log in again once
+// the token expires. Long-running services
+// should use NewClientWithOptions, which configures trust, transport and logging
+// explicitly and opts in to repeating logins with WithCredentialReplay.
+//
 // Parameters:
 //   - applianceUrl: The URL of the appliance to connect to.
 //   - apiVersion: The version of the API to use.
@@ -134,37 +163,79 @@ func (a *applianceURL) setUrl(url string, cacheTime time.Duration) {
 //
 // Returns:
 //
-//	A pointer to the newly created or existing SafeguardClient instance.
+//	A pointer to the newly created SafeguardClient instance.
 func NewClient(applianceUrl string, apiVersion string, debug bool) *SafeguardClient {
-	// Configure the logger if not already set up by SetLogger
-	if logger == nil || logger == slog.Default() {
+	o := defaultClientOptions()
+	o.apiVersion = apiVersion
+
+	// Use the logger set up by SetLogger, otherwise a client-scoped logger
+	o.logger = logger
+	if o.logger == nil {
 		var opts slog.HandlerOptions
 		if debug {
 			opts.Level = slog.LevelDebug
 		} else {
 			opts.Level = slog.LevelInfo
 		}
-		defaultLogger := slog.New(slog.NewTextHandler(os.Stdout, &opts))
-		SetLogger(defaultLogger)
+		o.logger = slog.New(slog.NewTextHandler(os.Stdout, &opts))
 	}
 
+	httpClient, err := o.newHTTPClient()
+	if err != nil {
+		o.logger.Error("Failed to configure HTTP client", "error", err)
+		httpClient = &http.Client{}
+	}
+
+	return newClient(applianceUrl, o, httpClient)
+}
+
+// newClient creates a SafeguardClient from resolved options and starts the
+// background token refresh unless it was disabled.
+//
+// Parameters:
+//   - applianceUrl: The URL of the appliance to connect to.
+//   - o: The resolved client options.
+//   - httpClient: The HTTP client used for all requests.
+//
+// Returns:
+//   - *SafeguardClient: The new client.
+func newClient(applianceUrl string, o *clientOptions, httpClient *http.Client) *SafeguardClient {
+	retryPolicy := DefaultRetryPolicy()
+
+	clientLogger := o.clientLogger()
+
 	sgclient := &SafeguardClient{
 		AccessToken:   &RSTSAuthResponse{},
-		ApiVersion:    apiVersion,
-		HttpClient:    createTLSClient(),
+		RetryPolicy:   &retryPolicy,
+		ApiVersion:    o.apiVersion,
+		HttpClient:    httpClient,
 		redirectPort:  redirectPort,
 		tokenEndpoint: applianceUrl + "/service/core/v4/Token/LoginResponse",
 
-		Logger: logger,
+		Middlewares:     o.middlewares,
+		readLimiter:     newRequestLimiter(o.readLimit),
+		writeLimiter:    newRequestLimiter(o.writeLimit),
+		Routing:         o.routing,
+		Instrumentation: o.instrumentation,
+		replay:          o.replay,
+		tokenSource:     o.tokenSource,
+		tokenCache:      o.tokenCache,
+		Logger:          clientLogger,
+	}
 
-		// channel to signal when authentication is done
-		authDone: make(chan string),
+	if err := sgclient.Appliance.setUrl(applianceUrl, 3600*time.Second); err != nil {
+		clientLogger.Error("Failed to set appliance URL", "error", err)
 	}
 
-	sgclient.Appliance.setUrl(applianceUrl, 3600*time.Second)
+	if o.failover != nil {
+		sgclient.EnableFailover(*o.failover)
+	}
 
-	ctx := context.Background()
-	go sgclient.refreshToken(ctx)
+	if !o.disableRefresh {
+		// channel to signal when authentication is done
+		sgclient.authDone = make(chan struct{}, 1)
+		sgclient.startBackgroundRefresh()
+	}
 	return sgclient
 }
 
@@ -176,15 +247,22 @@ func (c *SafeguardClient) NewSignalRClient() *EventHandler {
 
 // getClusterLeaderUrl returns the URL of the cluster leader.
 // This URL is used to identify the leader node in a cluster setup.
-func (c *SafeguardClient) getClusterLeaderUrl() string {
+func (c *SafeguardClient) getClusterLeaderUrl(ctx context.Context) string {
 	// Update the cluster leader URL to ensure it's set correctly
 	if c.ClusterLeader.isExpired() {
-		c.updateClusterLeaderUrl()
+		c.updateClusterLeaderUrl(ctx)
 	}
 
 	return c.ClusterLeader.getUrl()
 }
 
+// expire marks the cached URL as expired so it is resolved again on next use.
+func (a *applianceURL) expire() {
+	a.RWMutex.Lock()
+	defer a.RWMutex.Unlock()
+	a.cacheTime = 0
+}
+
 // isExpired checks if the cached URL has exceeded its cache duration.
 // Returns true if the cache has expired or if cacheTime is 0.
 // Returns false if cacheTime is -1 (infinite cache).
@@ -213,35 +291,18 @@ func (a *applianceURL) getExpiryTime() time.Time {
 	return a.lastUpdate.Add(a.cacheTime) // Remove the * time.Second since cacheTime is already a Duration
 }
 
-// createTLSClient creates and returns a new HTTP client with TLS configuration.
-// It loads the CA and root certificates from the specified files and adds them
-// to the certificate pool. If any error occurs during this process, the function
-// logs the error and exits the program.
+// addCurrentDirectoryCertificates adds all certificate files from the current
+// directory to the given certificate pool. Files that cannot be read or parsed
+// are skipped.
 //
-// Returns:
-//
-//	*http.Client: A pointer to the configured HTTP client.
-func createTLSClient() *http.Client {
-	return &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs:    loadCertificates(),
-				MinVersion: tls.VersionTLS12,
-				MaxVersion: tls.VersionTLS13,
-			},
-		},
-	}
-}
-
-// loadCertificates attempts to load all .crt and .cer files from the current directory
-// and adds them to a new certificate pool.
-func loadCertificates() *x509.CertPool {
-	caCertPool := x509.NewCertPool()
-
+// Parameters:
+//   - logger: The logger used to report skipped files.
+//   - pool: The certificate pool to add the certificates to.
+func addCurrentDirectoryCertificates(logger *slog.Logger, pool *x509.CertPool) {
 	certFiles, err := os.ReadDir(".")
 	if err != nil {
 		logger.Debug("Could not read current directory", "error", err)
-		return caCertPool
+		return
 	}
 
 	for _, file := range certFiles {
@@ -249,16 +310,14 @@ func loadCertificates() *x509.CertPool {
 			continue
 		}
 
-		if err := addCertToPool(caCertPool, file.Name()); err != nil {
+		if err := addCertToPool(logger, pool, file.Name()); err != nil {
 			logger.Debug("Error processing certificate", "file", file.Name(), "error", err)
 		}
 	}
-
-	return caCertPool
 }
 
 // addCertToPool reads a certificate file and adds it to the provided cert pool
-func addCertToPool(pool *x509.CertPool, filename string) error {
+func addCertToPool(logger *slog.Logger, pool *x509.CertPool, filename string) error {
 	cert, err := os.ReadFile(filename)
 	if err != nil {
 		return fmt.Errorf("error reading certificate: %w", err)
@@ -294,73 +353,6 @@ func isCertFile(name string) bool {
 	return certExtensions[ext]
 }
 
-// refreshToken refreshes the access token for the SafeguardClient.
-// It waits until the initial authentication is done if necessary,
-// then sets up a ticker to refresh the token periodically based on the remaining token time.
-// The function supports two types of authentication providers: local and certificate-based.
-// It will stop refreshing the token if the provided context is done.
-//
-// Parameters:
-// - ctx: The context to control the lifecycle of the token refresh process.
-func (c *SafeguardClient) refreshToken(ctx context.Context) {
-	<-c.authDone
-
-	if c.AccessToken.AuthProvider == "" {
-		logger.Debug("token refresh skipped: no auth provider")
-		return
-	}
-
-	logger.Debug("token refresh started")
-	remainingTokenTime := c.RemainingTokenTime()
-	ticker := time.NewTicker(remainingTokenTime - 1*time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-
-		case <-ticker.C:
-			if c.AccessToken.AuthProvider == AuthProviderLocal {
-				refreshTokenWithPassword(c)
-			} else if c.AccessToken.AuthProvider == AuthProviderCertificate {
-				refreshTokenWithCertificate(c)
-			}
-		}
-	}
-}
-
-// refreshTokenWithCertificate refreshes the access token of the SafeguardClient
-// using the provided certificate credentials.
-//
-// It calls the LoginWithCertificate method on the SafeguardClient instance,
-// passing the certificate path and password from the client's AccessToken credentials.
-//
-// Parameters:
-//
-//	c - A pointer to the SafeguardClient instance whose token needs to be refreshed.
-func refreshTokenWithCertificate(c *SafeguardClient) {
-	// Refresh the token using the certificate
-	if err := c.LoginWithCertificate(c.AccessToken.getCertificate()); err != nil {
-		logger.Error("Failed to refresh token using certificate", "error", err)
-	}
-}
-
-// refreshTokenWithPassword refreshes the authentication token for the SafeguardClient
-// using the stored username and password credentials.
-//
-// Parameters:
-// - c: A pointer to the SafeguardClient instance.
-//
-// This function calls the LoginWithPassword method on the SafeguardClient
-// to obtain a new access token using the current username and password.
-func refreshTokenWithPassword(c *SafeguardClient) {
-	// Refresh the token using the password
-	if err := c.LoginWithPassword(c.AccessToken.getUserNamePassword()); err != nil {
-		logger.Error("Failed to refresh token using password", "error", err)
-	}
-}
-
 // GetTokenExpirationTime returns the time when the current access token will expire
 // GetTokenExpirationTime returns the expiration time of the access token.
 // It calculates the expiration time by adding the token's lifespan (ExpiresIn)
@@ -370,7 +362,8 @@ func refreshTokenWithPassword(c *SafeguardClient) {
 //
 //	time.Time: The expiration time of the access token.
 func (c *SafeguardClient) GetTokenExpirationTime() time.Time {
-	return c.AccessToken.AuthTime.Add(time.Duration(c.AccessToken.ExpiresIn) * time.Second)
+	authTime, expiresIn := c.AccessToken.getLifetime()
+	return authTime.Add(expiresIn)
 }
 
 // IsTokenExpired checks if the current access token has expired
@@ -378,7 +371,7 @@ func (c *SafeguardClient) GetTokenExpirationTime() time.Time {
 // It returns true if the access token is nil, the authentication time is zero,
 // or the current time is after the token's expiration time.
 func (c *SafeguardClient) IsTokenExpired() bool {
-	if c.AccessToken == nil || c.AccessToken.AuthTime.IsZero() {
+	if c.AccessToken == nil || c.AccessToken.getAuthTime().IsZero() {
 		return true
 	}
 	return time.Now().After(c.GetTokenExpirationTime())
@@ -388,7 +381,7 @@ func (c *SafeguardClient) IsTokenExpired() bool {
 // RemainingTokenTime returns the remaining time until the access token expires.
 // If the access token is nil or the authentication time is zero, it returns a duration of zero.
 func (c *SafeguardClient) RemainingTokenTime() time.Duration {
-	if c.AccessToken == nil || c.AccessToken.AuthTime.IsZero() {
+	if c.AccessToken == nil || c.AccessToken.getAuthTime().IsZero() {
 		return 0
 	}
 	return time.Until(c.GetTokenExpirationTime())
@@ -398,10 +391,10 @@ func (c *SafeguardClient) RemainingTokenTime() time.Duration {
 // cluster leader URL for the SafeguardClient. If an error occurs while getting
 // the cluster leader host name, it logs the error and returns without updating
 // the cluster leader URL.
-func (c *SafeguardClient) updateClusterLeaderUrl() {
-	clusterLeaderHostName, err := c.getClusterLeaderHostName()
+func (c *SafeguardClient) updateClusterLeaderUrl(ctx context.Context) {
+	clusterLeaderHostName, err := c.getClusterLeaderHostName(ctx)
 	if err != nil {
-		logger.Error("Failed to get cluster leader host name", "error", err)
+		c.logger().Error("Failed to get cluster leader host name", "error", err)
 		return
 	}
 	c.setClusterLeader(clusterLeaderHostName)
@@ -423,26 +416,29 @@ func (c *SafeguardClient) updateClusterLeaderUrl() {
 //     and when updating the cluster leader URL.
 //   - Error: If there is an error generating the cluster leader URL.
 func (c *SafeguardClient) setClusterLeader(clusterLeaderHostName string) {
-	logger.Debug("Setting cluster leader", "hostname", clusterLeaderHostName)
+	c.logger().Debug("Setting cluster leader", "hostname", clusterLeaderHostName)
 	clusterLeaderUrl, err := c.generateClusterLeaderURL(clusterLeaderHostName)
 	if err != nil {
-		logger.Error("Failed to set cluster leader", "error", err)
+		c.logger().Error("Failed to set cluster leader", "error", err)
 		return
 	}
 
 	if c.ClusterLeader.getUrl() == clusterLeaderUrl {
-		logger.Debug("Cluster leader unchanged", "url", clusterLeaderUrl)
+		c.logger().Debug("Cluster leader unchanged", "url", clusterLeaderUrl)
 	}
 
 	if c.Appliance.getUrl() == clusterLeaderUrl {
-		logger.Debug("Cluster leader is same as appliance URL", "url", clusterLeaderUrl)
+		c.logger().Debug("Cluster leader is same as appliance URL", "url", clusterLeaderUrl)
 	}
 
-	logger.Debug("Updating cluster leader URL",
+	c.logger().Debug("Updating cluster leader URL",
 		"old", c.ClusterLeader.getUrl(),
 		"new", clusterLeaderUrl)
-	c.ClusterLeader.setUrl(clusterLeaderUrl, 3600*time.Second)
-	logger.Info("Cluster leader URL updated", "url", clusterLeaderUrl)
+	if err := c.ClusterLeader.setUrl(clusterLeaderUrl, 3600*time.Second); err != nil {
+		c.logger().Error("Failed to set cluster leader", "error", err)
+		return
+	}
+	c.logger().Info("Cluster leader URL updated", "url", clusterLeaderUrl)
 }
 
 // generateClusterLeaderURL generates the URL for the cluster leader based on the provided
@@ -458,10 +454,10 @@ func (c *SafeguardClient) setClusterLeader(clusterLeaderHostName string) {
 //   - string: The generated cluster leader URL.
 //   - error: An error if there was an issue generating the URL.
 func (c *SafeguardClient) generateClusterLeaderURL(clusterLeaderHostName string) (string, error) {
-	logger.Debug("Generating cluster leader URL", "hostname", clusterLeaderHostName)
+	c.logger().Debug("Generating cluster leader URL", "hostname", clusterLeaderHostName)
 	protocol, _, domainName, port, err := splitApplianceURL(c.Appliance.getUrl())
 	if err != nil {
-		logger.Error("Error splitting appliance URL", "error", err)
+		c.logger().Error("Error splitting appliance URL", "error", err)
 		return "", err
 	}
 
@@ -471,7 +467,7 @@ func (c *SafeguardClient) generateClusterLeaderURL(clusterLeaderHostName string)
 	} else {
 		clusterLeaderUrl = fmt.Sprintf("%s://%s.%s:%s", protocol, clusterLeaderHostName, domainName, port)
 	}
-	logger.Debug("Generated cluster leader URL", "url", clusterLeaderUrl)
+	c.logger().Debug("Generated cluster leader URL", "url", clusterLeaderUrl)
 	return clusterLeaderUrl, nil
 }
 
@@ -483,8 +479,8 @@ func (c *SafeguardClient) generateClusterLeaderURL(clusterLeaderHostName string)
 // Returns:
 //   - string: The hostname of the cluster leader.
 //   - error: An error if the request fails or no leader is found.
-func (c *SafeguardClient) getClusterLeaderHostName() (string, error) {
-	logger.Debug("Fetching cluster leader hostname")
+func (c *SafeguardClient) getClusterLeaderHostName(ctx context.Context) (string, error) {
+	c.logger().Debug("Fetching cluster leader hostname")
 
 	query := "Cluster/Members"
 	params := url.Values{}
@@ -493,11 +489,11 @@ func (c *SafeguardClient) getClusterLeaderHostName() (string, error) {
 	params.Add("fields", "Name")
 
 	fullPath := fmt.Sprintf("%s?%s", query, params.Encode())
-	logger.Debug("Sending request for cluster leader", "path", fullPath)
+	c.logger().Debug("Sending request for cluster leader", "path", fullPath)
 
-	response, err := c.GetRequest(fullPath)
+	response, err := c.GetRequest(ctx, fullPath)
 	if err != nil {
-		logger.Error("Failed to get cluster leader response", "error", err)
+		c.logger().Error("Failed to get cluster leader response", "error", err)
 		return "", err
 	}
 
@@ -505,15 +501,15 @@ func (c *SafeguardClient) getClusterLeaderHostName() (string, error) {
 		Name string `json:"Name"`
 	}
 	if err := json.Unmarshal(response, &leaderHostName); err != nil {
-		logger.Error("Failed to unmarshal cluster leader response", "error", err)
+		c.logger().Error("Failed to unmarshal cluster leader response", "error", err)
 		return "", err
 	}
 
 	if len(leaderHostName) == 0 {
-		logger.Error("No cluster leader found in response")
+		c.logger().Error("No cluster leader found in response")
 		return "", fmt.Errorf("no cluster leader found")
 	}
 
-	logger.Debug("Found cluster leader", "hostname", leaderHostName[0].Name)
+	c.logger().Debug("Found cluster leader", "hostname", leaderHostName[0].Name)
 	return leaderHostName[0].Name, nil
 }
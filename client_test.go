@@ -1,9 +1,14 @@
 package safeguard
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"math/rand"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -749,3 +754,67 @@ func TestAuthProviderString(t *testing.T) {
 		})
 	}
 }
+
+func TestClientsUseTheirOwnLogger(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { SetLogger(nil) })
+
+	var first, second bytes.Buffer
+	handler := func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`[]`)) }
+
+	client1 := newTestServerClient(t, handler)
+	client1.Logger = slog.New(slog.NewTextHandler(&first, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client2 := newTestServerClient(t, handler)
+	client2.Logger = slog.New(slog.NewTextHandler(&second, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	NewClient("https://appliance.example.com", "v4", true)
+
+	if _, err := client1.GetRequest(context.Background(), "Assets"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client2.GetRequest(context.Background(), "Assets"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(first.String(), "Preparing GET request") {
+		t.Errorf("expected debug output from first client, got %q", first.String())
+	}
+	if second.Len() != 0 {
+		t.Errorf("expected no output from second client, got %q", second.String())
+	}
+	if slog.Default() != defaultLogger {
+		t.Error("slog.Default() must not be modified")
+	}
+	if NewEventHandler(client1).logger != client1.Logger {
+		t.Error("expected event handler to use the client logger")
+	}
+}
+
+func TestNewClientLoggerSelection(t *testing.T) {
+	t.Cleanup(func() { SetLogger(nil) })
+
+	SetLogger(nil)
+	client := NewClient("https://appliance.example.com", "v4", true)
+	if !client.Logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected debug logging for client created with debug enabled")
+	}
+
+	custom := slog.New(slog.NewTextHandler(io.Discard, nil))
+	SetLogger(custom)
+	if got := NewClient("https://appliance.example.com", "v4", true).Logger; got != custom {
+		t.Error("expected client to use the logger configured with SetLogger")
+	}
+	if got := (&SafeguardClient{}).logger(); got != custom {
+		t.Error("expected bare client to fall back to the package logger")
+	}
+}
+
+func TestNewClientDoesNotReplayCredentials(t *testing.T) {
+	client := NewClient("https://appliance.example.com", "v4", false)
+	t.Cleanup(func() { client.Close(context.Background()) })
+
+	if client.replay {
+		t.Error("expected credential replay to be off unless WithCredentialReplay is used")
+	}
+}
@@ -1,6 +1,7 @@
 package safeguard
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -110,24 +111,25 @@ const (
 // Use filters to narrow down the results based on specific criteria.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: A Filter object containing query parameters to filter the results
 //
 // Returns:
 //   - []ClusterMember: A slice of cluster members matching the filter criteria
 //   - error: An error if the API request fails or the response cannot be parsed
-func (c *SafeguardClient) GetClusterMembers(filter Filter) ([]ClusterMember, error) {
+func (c *SafeguardClient) GetClusterMembers(ctx context.Context, filter Filter) ([]ClusterMember, error) {
 	var clusterMembers []ClusterMember
 
 	query := "Cluster/Members" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
-		logger.Error("Error occurred", "error", err)
+		c.logger().Error("Error occurred", "error", err)
 		return []ClusterMember{}, err
 	}
 
 	if err := json.Unmarshal(response, &clusterMembers); err != nil {
-		logger.Error("Error occurred", "error", err)
+		c.logger().Error("Error occurred", "error", err)
 		return nil, err
 	}
 
@@ -137,24 +139,25 @@ func (c *SafeguardClient) GetClusterMembers(filter Filter) ([]ClusterMember, err
 // GetClusterMember retrieves detailed information about a specific cluster member.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier (GUID) of the cluster member to retrieve
 //
 // Returns:
 //   - ClusterMember: The requested cluster member's configuration and status, or nil if not found
 //   - error: An error if the member cannot be found or the request fails
-func (c *SafeguardClient) GetClusterMember(id string) (ClusterMember, error) {
+func (c *SafeguardClient) GetClusterMember(ctx context.Context, id string) (ClusterMember, error) {
 	var clusterMember ClusterMember
 
 	query := fmt.Sprintf("Cluster/Members/%s", id)
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
-		logger.Error("Error occurred", "error", err)
+		c.logger().Error("Error occurred", "error", err)
 		return ClusterMember{}, err
 	}
 
 	if err := json.Unmarshal(response, &clusterMember); err != nil {
-		logger.Error("Error occurred", "error", err)
+		c.logger().Error("Error occurred", "error", err)
 		return ClusterMember{}, err
 	}
 
@@ -169,13 +172,13 @@ func (c *SafeguardClient) GetClusterMember(id string) (ClusterMember, error) {
 // Returns:
 //   - ClusterMember: The cluster member that is currently the leader, or nil if no leader is found
 //   - error: An error if no leader is found, multiple leaders are detected, or the request fails
-func (c *SafeguardClient) GetClusterLeader() (ClusterMember, error) {
+func (c *SafeguardClient) GetClusterLeader(ctx context.Context) (ClusterMember, error) {
 	filter := Filter{}
 	filter.AddFilter("IsLeader", "eq", "true")
 
-	clusterMembers, err := c.GetClusterMembers(filter)
+	clusterMembers, err := c.GetClusterMembers(ctx, filter)
 	if err != nil {
-		logger.Error("Error occurred", "error", err)
+		c.logger().Error("Error occurred", "error", err)
 		return ClusterMember{}, err
 	}
 
@@ -198,18 +201,18 @@ func (c *SafeguardClient) GetClusterLeader() (ClusterMember, error) {
 // Returns:
 //   - ClusterMember: The cluster member representing the current node with updated health status
 //   - error: An error if the health check fails to complete or the response cannot be parsed
-func (c *SafeguardClient) ForceClusterHealthCheck() (ClusterMember, error) {
+func (c *SafeguardClient) ForceClusterHealthCheck(ctx context.Context) (ClusterMember, error) {
 	var clusterMembers ClusterMember
 	query := "Cluster/Members/Self"
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
-		logger.Error("Error occurred", "error", err)
+		c.logger().Error("Error occurred", "error", err)
 		return ClusterMember{}, err
 	}
 
 	if err := json.Unmarshal(response, &clusterMembers); err != nil {
-		logger.Error("Error occurred", "error", err)
+		c.logger().Error("Error occurred", "error", err)
 		return ClusterMember{}, err
 	}
 
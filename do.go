@@ -0,0 +1,150 @@
+package safeguard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+)
+
+// Response describes the HTTP response of an API request.
+type Response struct {
+	StatusCode int         // HTTP status code, e.g. 200
+	Status     string      // HTTP status line, e.g. "200 OK"
+	Header     http.Header // Response headers
+	Body       []byte      // Raw response body, only set for successful responses
+}
+
+// newResponse wraps the result of a request attempt in a Response.
+//
+// Parameters:
+//   - resp: The HTTP response, or nil if none was received.
+//   - body: The response body.
+//   - err: The error of the attempt.
+//
+// Returns:
+//   - *Response: The response, or nil if resp is nil.
+//   - error: err unchanged.
+func newResponse(resp *http.Response, body []byte, err error) (*Response, error) {
+	if resp == nil {
+		return nil, err
+	}
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       body,
+	}, err
+}
+
+// responseBody returns the body of a successful response.
+func responseBody(resp *Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Do sends a request to any Safeguard API endpoint and decodes the JSON response
+// into T. It is intended for endpoints that have no dedicated wrapper yet.
+// Requests are routed, retried and rate limited like all other client requests.
+//
+// The request body is encoded as JSON unless it is nil, an io.Reader or a []byte,
+// which are sent as they are. If T is []byte, the raw response body is returned
+// without decoding. Values and slice elements that implement ClientHolder are
+// associated with the client, so their methods can be used directly.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
+//   - c: The client used to send the request.
+//   - method: The HTTP method, e.g. http.MethodGet.
+//   - path: The API path relative to the core service root, e.g. "Assets/1/Accounts".
+//   - query: Query parameters to append to the path, or nil.
+//   - body: The request body, or nil.
+//
+// Returns:
+//   - T: The decoded response body, or the zero value if the body is empty.
+//   - *Response: The status and headers of the response, or nil if no response was received.
+//   - error: An error if the request fails, the API returns a non-successful status
+//     code (as *APIError) or the response cannot be decoded.
+//
+// Example:
+//
+//	query := url.Values{"fields": {"Id,Name"}}
+//	assets, resp, err := safeguard.Do[[]safeguard.Asset](ctx, client, http.MethodGet, "Assets", query, nil)
+func Do[T any](ctx context.Context, c *SafeguardClient, method, path string, query url.Values, body any) (T, *Response, error) {
+	var result T
+
+	reqBody, err := encodeRequestBody(body)
+	if err != nil {
+		return result, nil, err
+	}
+
+	if len(query) > 0 {
+		path = path + "?" + query.Encode()
+	}
+
+	resp, err := c.routedRequest(ctx, method, path, reqBody, nil)
+	if err != nil {
+		return result, resp, err
+	}
+
+	if raw, ok := any(&result).(*[]byte); ok {
+		*raw = resp.Body
+		return result, resp, nil
+	}
+	if len(bytes.TrimSpace(resp.Body)) == 0 {
+		return result, resp, nil
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return result, resp, fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+
+	return attachClient(c, result), resp, nil
+}
+
+// encodeRequestBody converts a request body passed to Do into a reader.
+func encodeRequestBody(body any) (io.Reader, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, nil
+	case io.Reader:
+		return b, nil
+	case []byte:
+		return bytes.NewReader(b), nil
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		return bytes.NewReader(data), nil
+	}
+}
+
+// attachClient associates the client with v, or with each element if v is a
+// slice, using ClientHolder. Other values are returned unchanged.
+func attachClient[T any](c *SafeguardClient, v T) T {
+	if holder, ok := any(v).(ClientHolder); ok {
+		if ret, ok := holder.SetClient(c).(T); ok {
+			return ret
+		}
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || !rv.Type().Elem().Implements(reflect.TypeFor[ClientHolder]()) {
+		return v
+	}
+	for i := range rv.Len() {
+		elem := rv.Index(i)
+		ret := reflect.ValueOf(elem.Interface().(ClientHolder).SetClient(c))
+		if ret.IsValid() && ret.Type().AssignableTo(elem.Type()) {
+			elem.Set(ret)
+		}
+	}
+	return v
+}
@@ -0,0 +1,114 @@
+package safeguard
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestDoDecodesResponse(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/service/core/v4/Assets" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("filter"); got != "Name eq 'web 1'" {
+			t.Errorf("unexpected filter %q", got)
+		}
+		w.Header().Set("X-Total-Count", "2")
+		w.Write([]byte(`[{"Id":1,"Name":"web 1"},{"Id":2,"Name":"web 2"}]`))
+	})
+
+	query := url.Values{"filter": {"Name eq 'web 1'"}}
+	assets, resp, err := Do[[]Asset](context.Background(), client, http.MethodGet, "Assets", query, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(assets) != 2 || assets[1].Name != "web 2" {
+		t.Fatalf("unexpected assets: %+v", assets)
+	}
+	for _, a := range assets {
+		if a.apiClient != client {
+			t.Errorf("asset %d has no client attached", a.Id)
+		}
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Total-Count") != "2" {
+		t.Errorf("unexpected response: %d %v", resp.StatusCode, resp.Header)
+	}
+}
+
+func TestDoEncodesRequestBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body any
+		want string
+	}{
+		{name: "struct", body: struct{ Name string }{Name: "web"}, want: `{"Name":"web"}`},
+		{name: "bytes", body: []byte(`{"raw":true}`), want: `{"raw":true}`},
+		{name: "nil", body: nil, want: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				got = string(b)
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"Id":7}`))
+			})
+
+			asset, resp, err := Do[Asset](context.Background(), client, http.MethodPost, "Assets", nil, tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("request body = %q, want %q", got, tt.want)
+			}
+			if asset.Id != 7 || asset.apiClient != client {
+				t.Errorf("unexpected asset: %+v", asset)
+			}
+			if resp.StatusCode != http.StatusCreated {
+				t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusCreated)
+			}
+		})
+	}
+}
+
+func TestDoReturnsRawAndEmptyBodies(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Write([]byte(`"secret"`))
+	})
+	ctx := context.Background()
+
+	raw, _, err := Do[[]byte](ctx, client, http.MethodGet, "AccessRequests/1/RetrievePassword", nil, nil)
+	if err != nil || string(raw) != `"secret"` {
+		t.Errorf("Do[[]byte] = %q, %v", raw, err)
+	}
+
+	_, resp, err := Do[struct{}](ctx, client, http.MethodDelete, "Assets/1", nil, nil)
+	if err != nil || resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Do[struct{}] = %v, %v", resp, err)
+	}
+}
+
+func TestDoReturnsAPIErrorWithResponse(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "abc")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"Code":60108,"Message":"Asset not found"}`))
+	})
+
+	_, resp, err := Do[Asset](context.Background(), client, http.MethodGet, "Assets/99", nil, nil)
+	if !IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound || resp.Header.Get("X-Request-ID") != "abc" {
+		t.Errorf("expected the error response to be returned, got %+v", resp)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -9,23 +10,24 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
 	// Initialize the Safeguard client
-	sgc, err := common.InitClient()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		log.Fatalf("Failed to initialize client: %v", err)
 	}
 
 	// Example 1: List all access policies
 	fmt.Println("\n=== Example 1: Listing all access policies ===")
-	listAccessPolicies(sgc)
+	listAccessPolicies(ctx, sgc)
 
 	// Example 2: Get a specific access policy
 	fmt.Println("\n=== Example 2: Get specific access policy ===")
-	getSpecificPolicy(sgc, 7) // Replace 1 with an actual policy ID
+	getSpecificPolicy(ctx, sgc, 7) // Replace 1 with an actual policy ID
 
 	// Example 3: Working with reason codes
 	fmt.Println("\n=== Example 3: Working with reason codes ===")
-	workWithReasonCodes(sgc, 7) // Replace 1 with an actual policy ID
+	workWithReasonCodes(ctx, sgc, 7) // Replace 1 with an actual policy ID
 
 	// Example 4: Delete an access policy
 	fmt.Println("\n=== Example 4: Delete access policy ===")
@@ -33,12 +35,12 @@ func main() {
 
 	// Example: Dump reason codes for all access policies
 	fmt.Println("\n=== Dumping reason codes for all access policies ===")
-	dumpAllReasonCodes(sgc)
+	dumpAllReasonCodes(ctx, sgc)
 }
 
-func listAccessPolicies(sgc *safeguard.SafeguardClient) {
+func listAccessPolicies(ctx context.Context, sgc *safeguard.SafeguardClient) {
 	filter := safeguard.Filter{}
-	policies, err := sgc.GetAccessPolicies(filter)
+	policies, err := sgc.GetAccessPolicies(ctx, filter)
 	if err != nil {
 		log.Printf("Failed to get access policies: %v", err)
 		return
@@ -50,9 +52,9 @@ func listAccessPolicies(sgc *safeguard.SafeguardClient) {
 	}
 }
 
-func getSpecificPolicy(sgc *safeguard.SafeguardClient, policyID int) {
+func getSpecificPolicy(ctx context.Context, sgc *safeguard.SafeguardClient, policyID int) {
 	fields := safeguard.Fields{}
-	policy, err := sgc.GetAccessPolicy(policyID, fields)
+	policy, err := sgc.GetAccessPolicy(ctx, policyID, fields)
 	if err != nil {
 		log.Printf("Failed to get access policy %d: %v", policyID, err)
 		return
@@ -66,9 +68,9 @@ func getSpecificPolicy(sgc *safeguard.SafeguardClient, policyID int) {
 	fmt.Printf("- Asset Count: %d\n", policy.AssetCount)
 }
 
-func workWithReasonCodes(sgc *safeguard.SafeguardClient, policyID int) {
+func workWithReasonCodes(ctx context.Context, sgc *safeguard.SafeguardClient, policyID int) {
 	fields := safeguard.Fields{}
-	policy, err := sgc.GetAccessPolicy(policyID, fields)
+	policy, err := sgc.GetAccessPolicy(ctx, policyID, fields)
 	if err != nil {
 		log.Printf("Failed to get access policy %d: %v", policyID, err)
 		return
@@ -87,25 +89,25 @@ func workWithReasonCodes(sgc *safeguard.SafeguardClient, policyID int) {
 	}
 }
 
-func deletePolicy(sgc *safeguard.SafeguardClient, policyID int) {
+func deletePolicy(ctx context.Context, sgc *safeguard.SafeguardClient, policyID int) {
 	fields := safeguard.Fields{}
-	policy, err := sgc.GetAccessPolicy(policyID, fields)
+	policy, err := sgc.GetAccessPolicy(ctx, policyID, fields)
 	if err != nil {
 		log.Printf("Failed to get access policy %d: %v", policyID, err)
 		return
 	}
 
 	fmt.Printf("Attempting to delete policy: %s (ID: %d)\n", policy.Name, policy.Id)
-	if err := policy.Delete(); err != nil {
+	if err := policy.Delete(ctx); err != nil {
 		log.Printf("Failed to delete policy: %v", err)
 		return
 	}
 	fmt.Printf("Successfully deleted policy %s\n", policy.Name)
 }
 
-func dumpAllReasonCodes(sgc *safeguard.SafeguardClient) {
+func dumpAllReasonCodes(ctx context.Context, sgc *safeguard.SafeguardClient) {
 	filter := safeguard.Filter{}
-	policies, err := sgc.GetAccessPolicies(filter)
+	policies, err := sgc.GetAccessPolicies(ctx, filter)
 	if err != nil {
 		log.Printf("Failed to get access policies: %v", err)
 		return
@@ -10,7 +10,8 @@ import (
 )
 
 func main() {
-	sgc, err := common.InitClient()
+	ctx := context.Background()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -18,7 +19,7 @@ func main() {
 	// Example 1: Get information about the current user
 	fmt.Println("Example 1: Getting current user information")
 
-	me, err := sgc.GetMe(safeguard.Filter{})
+	me, err := sgc.GetMe(ctx, safeguard.Filter{})
 	if err != nil {
 		fmt.Printf("Error getting current user: %s\n", err)
 	}
@@ -28,7 +29,7 @@ func main() {
 
 	// Example 2: Get all Entitlements
 	fmt.Println("Example 2: Getting all entitlements")
-	entitlements, err := sgc.GetMeAccountEntitlements(safeguard.AccessRequestTypePassword, false, false, safeguard.Filter{})
+	entitlements, err := sgc.GetMeAccountEntitlements(ctx, safeguard.AccessRequestTypePassword, false, false, safeguard.Filter{})
 	if err != nil {
 		fmt.Printf("Error getting entitlements: %s\n", err)
 		panic(err)
@@ -41,7 +42,7 @@ func main() {
 		fmt.Printf("(%d) AccountName: %s (AccountDomain: %s)\n", entitlement.Account.Id, entitlement.Account.Name, entitlement.Account.DomainName)
 		fmt.Println("Get Access Request for Account")
 
-		accessRequest, err := sgc.GetAccessRequests(entitlement.GetFilter())
+		accessRequest, err := sgc.GetAccessRequests(ctx, entitlement.GetFilter())
 		if err != nil {
 			fmt.Printf("Error getting access request: %s\n", err)
 			panic(err)
@@ -60,7 +61,7 @@ func main() {
 
 	// Example 3: New Access Request
 	fmt.Println("Example 3: Creating a new access request")
-	accessRequest, err := sgc.NewAccessRequests(entitlements, 1200*time.Second)
+	accessRequest, err := sgc.NewAccessRequests(ctx, entitlements, 1200*time.Second)
 	if err != nil {
 		fmt.Printf("Error creating access request: %s\n", err)
 		//panic(err)
@@ -78,7 +79,7 @@ func main() {
 		filter.AddFilter("State", safeguard.OpNotEqual, string(safeguard.StateCompleted))
 		filter.AddFilter("State", safeguard.OpNotEqual, string(safeguard.StatePendingReview))
 		filter.AddFilter("State", safeguard.OpNotEqual, string(safeguard.StatePendingAcknowledgment))
-		accessRequest, err := sgc.GetAccessRequests(filter) // Updated from entitlement.GetFilter() to filter
+		accessRequest, err := sgc.GetAccessRequests(ctx, filter) // Updated from entitlement.GetFilter() to filter
 		if err != nil {
 			fmt.Printf("Error getting access request: %s\n", err)
 			panic(err)
@@ -115,7 +116,7 @@ func main() {
 		fmt.Printf("(%d) AccountName: %s (AccountDomain: %s)\n", entitlement.Account.Id, entitlement.Account.Name, entitlement.Account.DomainName)
 		fmt.Println("Get Access Request for Account")
 
-		accessRequest, err := sgc.GetAccessRequests(entitlement.GetFilter())
+		accessRequest, err := sgc.GetAccessRequests(ctx, entitlement.GetFilter())
 		if err != nil {
 			fmt.Printf("Error getting access request: %s\n", err)
 			panic(err)
@@ -130,7 +131,7 @@ func main() {
 			fmt.Println("  Asset Name:   ", request.AccountAssetName)
 			fmt.Println("  State:        ", request.State)
 
-			_, err := request.Close()
+			_, err := request.Close(ctx)
 			if err != nil {
 				fmt.Printf("Error checkin access request: %s\n", err)
 				//panic(err)
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sthayduk/safeguard-go"
@@ -8,13 +9,14 @@ import (
 )
 
 func main() {
-	sgc, err := common.InitClient()
+	ctx := context.Background()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		panic(err)
 	}
 
 	// Get all asset groups
-	assetGroups, err := sgc.GetAssetGroups(safeguard.Filter{})
+	assetGroups, err := sgc.GetAssetGroups(ctx, safeguard.Filter{})
 	if err != nil {
 		panic(err)
 	}
@@ -28,7 +30,7 @@ func main() {
 	// Get a specific asset group with additional fields
 	if len(assetGroups) > 0 {
 		fields := safeguard.Fields{"Name", "Description", "CreatedDate"}
-		assetGroup, err := sgc.GetAssetGroup(assetGroups[0].Id, fields)
+		assetGroup, err := sgc.GetAssetGroup(ctx, assetGroups[0].Id, fields)
 		if err != nil {
 			panic(err)
 		}
@@ -13,6 +13,7 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
 	start := time.Now()
 
 	// Initialize colored output
@@ -25,7 +26,7 @@ func main() {
 
 	// Initialize the Safeguard client
 	logger.Println("Initializing Safeguard client...")
-	sgc, err := common.InitClient()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		logger.Fatalf("%s Failed to initialize client: %v", warning("ERROR:"), err)
 	}
@@ -34,7 +35,7 @@ func main() {
 	// Retrieve asset account
 	assetAccountId := 17
 	logger.Printf("Retrieving asset account with ID: %d...", assetAccountId)
-	assetAccount, err := sgc.GetAssetAccount(assetAccountId, safeguard.Fields{})
+	assetAccount, err := sgc.GetAssetAccount(ctx, assetAccountId, safeguard.Fields{})
 	if err != nil {
 		logger.Fatalf("%s Failed to retrieve asset account: %v", warning("ERROR:"), err)
 	}
@@ -48,7 +49,7 @@ func main() {
 
 	// Initiate password change task
 	logger.Println("Initiating password change task...")
-	changePasswordTask, err := assetAccount.ChangePassword()
+	changePasswordTask, err := assetAccount.ChangePassword(ctx)
 	if err != nil {
 		logger.Fatalf("%s Failed to initiate password change task: %v", warning("ERROR:"), err)
 	}
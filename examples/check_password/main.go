@@ -10,17 +10,18 @@ import (
 )
 
 func main() {
-	sgc, err := common.InitClient()
+	ctx := context.Background()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		panic(err)
 	}
 
-	assetAccount, err := sgc.GetAssetAccount(18, safeguard.Fields{})
+	assetAccount, err := sgc.GetAssetAccount(ctx, 18, safeguard.Fields{})
 	if err != nil {
 		panic(err)
 	}
 
-	checkPasswordTask, err := assetAccount.CheckPassword()
+	checkPasswordTask, err := assetAccount.CheckPassword(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sthayduk/safeguard-go"
@@ -8,14 +9,15 @@ import (
 )
 
 func main() {
-	sgc, err := common.InitClient()
+	ctx := context.Background()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		panic(err)
 	}
 
 	// Example: GetClusterMembers
 	filter := safeguard.Filter{}
-	clusterMembers, err := sgc.GetClusterMembers(filter)
+	clusterMembers, err := sgc.GetClusterMembers(ctx, filter)
 	if err != nil {
 		fmt.Printf("Error getting cluster members: %v\n", err)
 	} else {
@@ -24,7 +26,7 @@ func main() {
 
 	// Example: GetClusterMember
 	memberID := "46995a16b0b7482899cc6c60f4a0d86d" // Replace with actual member ID
-	clusterMember, err := sgc.GetClusterMember(memberID)
+	clusterMember, err := sgc.GetClusterMember(ctx, memberID)
 	if err != nil {
 		fmt.Printf("Error getting cluster member: %v\n", err)
 	} else {
@@ -32,7 +34,7 @@ func main() {
 	}
 
 	// Example: GetClusterLeader
-	clusterLeader, err := sgc.GetClusterLeader()
+	clusterLeader, err := sgc.GetClusterLeader(ctx)
 	if err != nil {
 		fmt.Printf("Error getting cluster leader: %v\n", err)
 	} else {
@@ -40,7 +42,7 @@ func main() {
 	}
 
 	// Example: ForceClusterHealthCheck
-	clusterHealth, err := sgc.ForceClusterHealthCheck()
+	clusterHealth, err := sgc.ForceClusterHealthCheck(ctx)
 	if err != nil {
 		fmt.Printf("Error forcing cluster health check: %v\n", err)
 	} else {
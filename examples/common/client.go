@@ -1,35 +1,31 @@
 package common
 
 import (
+	"context"
 	"os"
 
 	"github.com/sthayduk/safeguard-go"
 )
 
 // InitClient creates and initializes a SafeguardClient using environment variables
-func InitClient() (*safeguard.SafeguardClient, error) {
+func InitClient(ctx context.Context) (*safeguard.SafeguardClient, error) {
 	userToken := os.Getenv("SAFEGUARD_USER_TOKEN")
 	applianceUrl := os.Getenv("SAFEGUARD_HOST_URL")
 	apiVersion := os.Getenv("SAFEGUARD_API_VERSION")
 	pfxPassword := os.Getenv("SAFEGUARD_PFX_PASSWORD")
 	pfxPath := os.Getenv("SAFEGUARD_PFX_PATH")
 
-	var sgc *safeguard.SafeguardClient
+	source := safeguard.CertificateFileTokenSource(pfxPath, pfxPassword)
+	if userToken != "" {
+		source = safeguard.StaticTokenSource(userToken)
+	}
 
-	if userToken == "" {
-		sgc = safeguard.NewClient(applianceUrl, apiVersion, false)
-		err := sgc.LoginWithCertificate(pfxPath, pfxPassword)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		sgc = safeguard.NewClient(applianceUrl, apiVersion, false)
-		sgc.AccessToken = &safeguard.RSTSAuthResponse{
-			UserToken: userToken,
-		}
+	sgc := safeguard.NewClient(applianceUrl, apiVersion, false)
+	if err := sgc.LoginWithTokenSource(ctx, source); err != nil {
+		return nil, err
 	}
 
-	err := sgc.ValidateAccessToken()
+	err := sgc.ValidateAccessToken(ctx)
 	if err != nil {
 		return nil, err
 	}
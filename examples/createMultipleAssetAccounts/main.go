@@ -15,6 +15,7 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
 	start := time.Now()
 	adId := 464
 	samAccountName := "muster"
@@ -29,14 +30,14 @@ func main() {
 
 	// Initialize the Safeguard client
 	logger.Println("Initializing Safeguard client...")
-	sgc, err := common.InitClient()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		logger.Fatalf("%s Failed to initialize client: %v", warning("ERROR:"), err)
 	}
 
 	// Get the Active Directory
 	logger.Printf("Getting Active Directory with ID: %d", adId)
-	ad, err := sgc.GetAsset(adId, safeguard.Fields{"Id", "Name"})
+	ad, err := sgc.GetAsset(ctx, adId, safeguard.Fields{"Id", "Name"})
 	if err != nil {
 		logger.Fatalf("Failed to get Active Directory: %v", err)
 	}
@@ -45,7 +46,7 @@ func main() {
 	logger.Printf("Searching for user: %s", samAccountName)
 	filter := safeguard.Filter{}
 	filter.AddFilter("Name", "contains", samAccountName)
-	users, err := ad.GetDirectoryAccounts(filter)
+	users, err := ad.GetDirectoryAccounts(ctx, filter)
 	if err != nil {
 		logger.Fatalf("Failed to get directory users: %v", err)
 	}
@@ -54,7 +55,7 @@ func main() {
 		logger.Printf("Found user: %s", user.Name)
 	}
 
-	createdUsers, err := sgc.CreateAssetAccounts(users)
+	createdUsers, err := sgc.CreateAssetAccounts(ctx, users)
 	if err != nil {
 		logger.Fatalf("Failed to create asset accounts: %s", err)
 	}
@@ -78,7 +79,7 @@ func main() {
 		// Suspend User
 		go func() {
 			logger.Println("Suspending user...")
-			task, err := createdUser.Suspend()
+			task, err := createdUser.Suspend(ctx)
 			if err != nil {
 				logger.Fatalf("Failed to suspend user: %v", err)
 			}
@@ -109,19 +110,19 @@ func updateAndCheckPassword(sgc *safeguard.SafeguardClient, ctx context.Context,
 
 	// Update Password Profile
 	logger.Println("Updating password profile...")
-	assetPartition, err := sgc.GetAssetPartition(1, safeguard.Fields{"Id", "Name"})
+	assetPartition, err := sgc.GetAssetPartition(ctx, 1, safeguard.Fields{"Id", "Name"})
 	if err != nil {
 		logger.Fatalf("Failed to get asset partition: %v", err)
 	}
 
 	filter := safeguard.Filter{}
 	filter.AddFilter("Name", "eq", "ITdesign Profile Suspend")
-	passwordProfile, err := sgc.GetPasswordRules(assetPartition, filter)
+	passwordProfile, err := sgc.GetPasswordRules(ctx, assetPartition, filter)
 	if err != nil {
 		logger.Fatalf("Failed to get password profile: %v", err)
 	}
 
-	updatedUser, err := createdUser.UpdatePasswordProfile(passwordProfile[0])
+	updatedUser, err := createdUser.UpdatePasswordProfile(ctx, passwordProfile[0])
 	if err != nil {
 		logger.Fatalf("Failed to update user password profile: %v", err)
 	}
@@ -141,7 +142,7 @@ func updateAndCheckPassword(sgc *safeguard.SafeguardClient, ctx context.Context,
 
 	// Update User Password
 	logger.Println("Updating user password...")
-	task, err := updatedUser.ChangePassword()
+	task, err := updatedUser.ChangePassword(ctx)
 	if err != nil {
 		logger.Fatalf("Failed to change user password: %v", err)
 	}
@@ -153,7 +154,7 @@ func updateAndCheckPassword(sgc *safeguard.SafeguardClient, ctx context.Context,
 
 	// Check Users Password
 	logger.Println("Checking user password...")
-	passwordStatus, err := updatedUser.CheckPassword()
+	passwordStatus, err := updatedUser.CheckPassword(ctx)
 	if err != nil {
 		logger.Fatalf("Failed to check user password: %v", err)
 	}
@@ -14,6 +14,7 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
 	start := time.Now()
 	adId := 464
 	samAccountName := "mustermann"
@@ -28,14 +29,14 @@ func main() {
 
 	// Initialize the Safeguard client
 	logger.Println("Initializing Safeguard client...")
-	sgc, err := common.InitClient()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		logger.Fatalf("%s Failed to initialize client: %v", warning("ERROR:"), err)
 	}
 
 	// Get the Active Directory
 	logger.Printf("Getting Active Directory with ID: %d", adId)
-	ad, err := sgc.GetAsset(adId, safeguard.Fields{"Id", "Name"})
+	ad, err := sgc.GetAsset(ctx, adId, safeguard.Fields{"Id", "Name"})
 	if err != nil {
 		logger.Fatalf("Failed to get Active Directory: %v", err)
 	}
@@ -44,7 +45,7 @@ func main() {
 	logger.Printf("Searching for user: %s", samAccountName)
 	filter := safeguard.Filter{}
 	filter.AddFilter("Name", "eq", samAccountName)
-	users, err := ad.GetDirectoryAccounts(filter)
+	users, err := ad.GetDirectoryAccounts(ctx, filter)
 	if err != nil {
 		logger.Fatalf("Failed to get directory users: %v", err)
 	}
@@ -59,7 +60,7 @@ func main() {
 
 	// Create the user
 	logger.Println("Creating user in Safeguard...")
-	createdUser, err := users[0].Create()
+	createdUser, err := users[0].Create(ctx)
 	if err != nil {
 		logger.Fatalf("%s Failed to create user: %v", warning("ERROR:"), err)
 	}
@@ -74,7 +75,7 @@ func main() {
 
 	// Suspend User
 	logger.Println("Suspending user...")
-	task, err := createdUser.Suspend()
+	task, err := createdUser.Suspend(ctx)
 	if err != nil {
 		logger.Fatalf("Failed to suspend user: %v", err)
 	}
@@ -89,19 +90,19 @@ func main() {
 
 	// Update Password Profile
 	logger.Println("Updating password profile...")
-	assetPartition, err := sgc.GetAssetPartition(1, safeguard.Fields{"Id", "Name"})
+	assetPartition, err := sgc.GetAssetPartition(ctx, 1, safeguard.Fields{"Id", "Name"})
 	if err != nil {
 		logger.Fatalf("Failed to get asset partition: %v", err)
 	}
 
 	filter = safeguard.Filter{}
 	filter.AddFilter("Name", "eq", "ITdesign Profile Suspend")
-	passwordProfile, err := sgc.GetPasswordRules(assetPartition, filter)
+	passwordProfile, err := sgc.GetPasswordRules(ctx, assetPartition, filter)
 	if err != nil {
 		logger.Fatalf("Failed to get password profile: %v", err)
 	}
 
-	updatedUser, err := createdUser.UpdatePasswordProfile(passwordProfile[0])
+	updatedUser, err := createdUser.UpdatePasswordProfile(ctx, passwordProfile[0])
 	if err != nil {
 		logger.Fatalf("Failed to update user password profile: %v", err)
 	}
@@ -121,7 +122,7 @@ func main() {
 
 	// Update User Password
 	logger.Println("Updating user password...")
-	taskState, err := updatedUser.ChangePassword()
+	taskState, err := updatedUser.ChangePassword(ctx)
 	if err != nil {
 		logger.Fatalf("Failed to change user password: %v", err)
 	}
@@ -137,7 +138,7 @@ func main() {
 
 	// Check Users Password
 	logger.Println("Checking user password...")
-	passwordStatus, err := updatedUser.CheckPassword()
+	passwordStatus, err := updatedUser.CheckPassword(ctx)
 	if err != nil {
 		logger.Fatalf("Failed to check user password: %v", err)
 	}
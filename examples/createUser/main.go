@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -10,6 +11,7 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
 	idpID := 449
 	authProviderId := 448
 	username := "pam.test"
@@ -19,14 +21,14 @@ func main() {
 
 	// Initialize the Safeguard client
 	logger.Println("Initializing Safeguard client...")
-	sgc, err := common.InitClient()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		logger.Fatalf("Failed to initialize client: %v", err)
 	}
 
 	// Get the Identity Provider
 	logger.Printf("Getting Identity Provider with ID: %d", idpID)
-	idp, err := sgc.GetIdentityProvider(idpID)
+	idp, err := sgc.GetIdentityProvider(ctx, idpID)
 	if err != nil {
 		logger.Fatalf("Failed to get Identity Provider: %v", err)
 	}
@@ -35,7 +37,7 @@ func main() {
 	logger.Printf("Searching for user: %s", username)
 	filter := safeguard.Filter{}
 	filter.AddFilter("Name", "eq", username)
-	users, err := idp.GetDirectoryUsers(filter)
+	users, err := idp.GetDirectoryUsers(ctx, filter)
 	if err != nil {
 		logger.Fatalf("Failed to get directory users: %v", err)
 	}
@@ -50,7 +52,7 @@ func main() {
 
 	// Create the user
 	logger.Println("Creating user in Safeguard...")
-	response, err := sgc.CreateUser(users[0])
+	response, err := sgc.CreateUser(ctx, users[0])
 	if err != nil {
 		logger.Fatalf("Failed to create user: %v", err)
 	}
@@ -65,11 +67,11 @@ func main() {
 		response.Id, response.Name, response.DisplayName, response.EmailAddress)
 
 	// Update Authentication Provider
-	authProvider, err := sgc.GetAuthenticationProvider(authProviderId)
+	authProvider, err := sgc.GetAuthenticationProvider(ctx, authProviderId)
 	if err != nil {
 		logger.Fatalf("Failed to get Authentication Provider: %v", err)
 	}
-	response, err = response.SetAuthenticationProvider(authProvider)
+	response, err = response.SetAuthenticationProvider(ctx, authProvider)
 	if err != nil {
 		logger.Fatalf("Failed to set Authentication Provider: %v", err)
 	}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -9,8 +10,9 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
 	// Initialize the Safeguard client
-	sgc, err := common.InitClient()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		log.Fatalf("Failed to initialize client: %v", err)
 	}
@@ -29,11 +31,11 @@ func main() {
 
 	// Example 4: Using filters with the API
 	fmt.Println("\n=== Example 4: Using filters with the API ===")
-	filterWithAPI(sgc)
+	filterWithAPI(ctx, sgc)
 
 	// Example 5: Filtering assets by name
 	fmt.Println("\n=== Example 5: Filtering assets by name ===")
-	filterAssetsByName(sgc, "ITd-Active-Directory")
+	filterAssetsByName(ctx, sgc, "ITd-Active-Directory")
 }
 
 // basicFilter demonstrates how to create a basic filter with fields and ordering
@@ -113,7 +115,7 @@ func complexSearchFilter() {
 }
 
 // filterWithAPI demonstrates using filters with the Safeguard API
-func filterWithAPI(sgc *safeguard.SafeguardClient) {
+func filterWithAPI(ctx context.Context, sgc *safeguard.SafeguardClient) {
 	// Create a filter to get assets that are Windows machines
 	filter := safeguard.Filter{}
 	filter.AddField("Name")
@@ -123,7 +125,7 @@ func filterWithAPI(sgc *safeguard.SafeguardClient) {
 	filter.AddOrderBy("Name")
 
 	// Use the filter with the API
-	assets, err := sgc.GetMeAccessRequestAssets(filter)
+	assets, err := sgc.GetMeAccessRequestAssets(ctx, filter)
 	if err != nil {
 		fmt.Printf("Error getting Active Directory assets: %s\n", err)
 		return
@@ -140,7 +142,7 @@ func filterWithAPI(sgc *safeguard.SafeguardClient) {
 }
 
 // filterAssetsByName demonstrates filtering assets by a name pattern
-func filterAssetsByName(sgc *safeguard.SafeguardClient, searchTerm string) {
+func filterAssetsByName(ctx context.Context, sgc *safeguard.SafeguardClient, searchTerm string) {
 	// Create a filter to search for assets by name
 	filter := safeguard.Filter{}
 	filter.AddField("Name")
@@ -158,7 +160,7 @@ func filterAssetsByName(sgc *safeguard.SafeguardClient, searchTerm string) {
 	filter.AddOrderBy("Name")
 
 	// Use the filter with the API
-	assets, err := sgc.GetMeAccessRequestAssets(filter)
+	assets, err := sgc.GetMeAccessRequestAssets(ctx, filter)
 	if err != nil {
 		fmt.Printf("Error searching for assets with '%s': %s\n", searchTerm, err)
 		return
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -9,9 +10,10 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
 	// Initialize Safeguard client
 	// Replace with your Safeguard appliance address
-	sgc, err := common.InitClient()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -20,7 +22,7 @@ func main() {
 
 	// Example 1: Get all identities
 	fmt.Println("\n=== Example 1: Get all identities ===")
-	identities, err := sgc.GetIdentities(safeguard.Filter{})
+	identities, err := sgc.GetIdentities(ctx, safeguard.Filter{})
 	if err != nil {
 		log.Fatalf("Failed to get identities: %v", err)
 	}
@@ -44,7 +46,7 @@ func main() {
 	filter.AddFilter("PrincipalKind", safeguard.OpEqual, "User")
 	filter.AddOrderBy("DisplayName")
 
-	userIdentities, err := sgc.GetIdentities(filter)
+	userIdentities, err := sgc.GetIdentities(ctx, filter)
 	if err != nil {
 		log.Fatalf("Failed to get filtered identities: %v", err)
 	}
@@ -65,7 +67,7 @@ func main() {
 
 	fmt.Println("\n=== Example 3: Get a specific identity by ID ===")
 	targetID := userIdentities[51].Id
-	identity, err := sgc.GetIdentity(targetID, safeguard.Fields{})
+	identity, err := sgc.GetIdentity(ctx, targetID, safeguard.Fields{})
 	if err != nil {
 		log.Fatalf("Failed to get identity with ID %d: %v", targetID, err)
 	}
@@ -76,7 +78,7 @@ func main() {
 
 	// Example 4: Get identity provider for an identity
 	fmt.Println("\n=== Example 4: Get identity provider for an identity ===")
-	provider, err := identity.GetIdentityProvider(safeguard.Fields{})
+	provider, err := identity.GetIdentityProvider(ctx, safeguard.Fields{})
 	if err != nil {
 		log.Printf("Failed to get identity provider: %v", err)
 	} else {
@@ -86,7 +88,7 @@ func main() {
 
 	// Example 5: Get user associated with an identity
 	fmt.Println("\n=== Example 5: Get user associated with an identity ===")
-	user, err := identity.GetUser(safeguard.Fields{})
+	user, err := identity.GetUser(ctx, safeguard.Fields{})
 	if err != nil {
 		log.Printf("Failed to get user: %v", err)
 	} else {
@@ -96,7 +98,7 @@ func main() {
 
 	// Example 6: Get user group associated with an identity
 	fmt.Println("\n=== Example 6: Get user group associated with an identity ===")
-	userGroup, err := identity.GetUserGroup(safeguard.Fields{})
+	userGroup, err := identity.GetUserGroup(ctx, safeguard.Fields{})
 	if err != nil {
 		log.Printf("Failed to get user group: %v", err)
 	} else {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -9,13 +10,14 @@ import (
 )
 
 func main() {
-	sgc, err := common.InitClient()
+	ctx := context.Background()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// Get user with ID 76 ("Stefan Hayduk")
-	user, err := sgc.GetUser(76, nil)
+	user, err := sgc.GetUser(ctx, 76, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -23,7 +25,7 @@ func main() {
 	// Get both policy accounts at once (133: "da-andresen", 134: "sa-andresen")
 	accounts := make([]safeguard.PolicyAccount, 0, 2)
 	for _, id := range []int{133, 134} {
-		account, err := sgc.GetPolicyAccount(id, nil)
+		account, err := sgc.GetPolicyAccount(ctx, id, nil)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -31,7 +33,7 @@ func main() {
 	}
 
 	// Link all accounts at once
-	linkedAccounts, err := sgc.AddLinkedAccounts(user, accounts)
+	linkedAccounts, err := sgc.AddLinkedAccounts(ctx, user, accounts)
 	if err != nil {
 		log.Fatal(err)
 	}
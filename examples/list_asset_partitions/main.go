@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sthayduk/safeguard-go"
@@ -8,12 +9,13 @@ import (
 )
 
 func main() {
-	sgc, err := common.InitClient()
+	ctx := context.Background()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		panic(err)
 	}
 
-	partitions, err := sgc.GetAssetPartitions(safeguard.Filter{})
+	partitions, err := sgc.GetAssetPartitions(ctx, safeguard.Filter{})
 	if err != nil {
 		panic(err)
 	}
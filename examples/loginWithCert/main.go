@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -10,6 +11,7 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
 	start := time.Now()
 
 	// Initialize colored output
@@ -36,7 +38,7 @@ func main() {
 		}
 	}
 
-	err := sgc.ValidateAccessToken()
+	err := sgc.ValidateAccessToken(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sthayduk/safeguard-go"
@@ -8,7 +9,8 @@ import (
 )
 
 func main() {
-	sgc, err := common.InitClient()
+	ctx := context.Background()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -20,7 +22,7 @@ func main() {
 	filter.AddField("EmailAddress")
 	filter.AddField("AdminRoles")
 
-	me, err := sgc.GetMe(filter)
+	me, err := sgc.GetMe(ctx, filter)
 	if err != nil {
 		fmt.Printf("Error getting current user: %s\n", err)
 	} else {
@@ -38,7 +40,7 @@ func main() {
 	assetFilter.AddField("Platform.DisplayName")
 	assetFilter.AddOrderBy("Name")
 
-	assets, err := sgc.GetMeAccessRequestAssets(assetFilter)
+	assets, err := sgc.GetMeAccessRequestAssets(ctx, assetFilter)
 	if err != nil {
 		fmt.Printf("Error getting accessible assets: %s\n", err)
 	} else {
@@ -55,7 +57,7 @@ func main() {
 		if len(assets) > 0 {
 			fmt.Println("\nExample 3: Getting a specific asset by ID")
 			assetId := fmt.Sprintf("%d", assets[0].Id)
-			asset, err := sgc.GetMeAccessRequestAsset(assetId)
+			asset, err := sgc.GetMeAccessRequestAsset(ctx, assetId)
 			if err != nil {
 				fmt.Printf("Error getting asset by ID: %s\n", err)
 			} else {
@@ -72,7 +74,7 @@ func main() {
 	// Example 4: Get actionable requests with detailed information
 	fmt.Println("Example 4: Getting actionable requests with details")
 	requestFilter := safeguard.Filter{}
-	actionableRequests, err := sgc.GetMeActionableRequestsDetailed(requestFilter)
+	actionableRequests, err := sgc.GetMeActionableRequestsDetailed(ctx, requestFilter)
 	if err != nil {
 		fmt.Printf("Error getting actionable requests: %s\n", err)
 	} else {
@@ -120,7 +122,7 @@ func main() {
 
 	// Example 5: Get actionable requests by role
 	fmt.Println("Example 5: Getting actionable requests by role (Admin)")
-	approverRequests, err := sgc.GetMeActionableRequestsByRole(safeguard.AdminRole, requestFilter)
+	approverRequests, err := sgc.GetMeActionableRequestsByRole(ctx, safeguard.AdminRole, requestFilter)
 	if err != nil {
 		fmt.Printf("Error getting approver requests: %s\n", err)
 	} else {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -9,7 +10,8 @@ import (
 )
 
 func main() {
-	sgc, err := common.InitClient()
+	ctx := context.Background()
+	sgc, err := common.InitClient(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -20,7 +22,7 @@ func main() {
 		Fields:  []string{"Id", "Name", "AssetType", "AssetPartitionName"},
 	}
 
-	policyAssets, err := sgc.GetPolicyAssets(filter)
+	policyAssets, err := sgc.GetPolicyAssets(ctx, filter)
 	if err != nil {
 		log.Fatalf("Failed to get policy assets: %v", err)
 	}
@@ -33,7 +35,7 @@ func main() {
 	// Example 2: Get a specific policy asset by ID
 	if len(policyAssets) > 0 {
 		fields := safeguard.Fields{"Id", "Name", "NetworkAddress", "Platform"}
-		policyAsset, err := sgc.GetPolicyAsset(policyAssets[3].Id, fields)
+		policyAsset, err := sgc.GetPolicyAsset(ctx, policyAssets[3].Id, fields)
 		if err != nil {
 			log.Fatalf("Failed to get policy asset: %v", err)
 		}
@@ -55,7 +57,7 @@ func main() {
 		assetGroupFilter := safeguard.Filter{
 			Fields: []string{"Id", "Name", "Description"},
 		}
-		assetGroups, err := policyAsset.GetAssetGroups(assetGroupFilter)
+		assetGroups, err := policyAsset.GetAssetGroups(ctx, assetGroupFilter)
 		if err != nil {
 			log.Fatalf("Failed to get asset groups: %v", err)
 		}
@@ -69,7 +71,7 @@ func main() {
 		dseFilter := safeguard.Filter{
 			Fields: []string{"Name", "DirectoryProperties"},
 		}
-		entries, err := policyAsset.GetDirectoryServiceEntries(dseFilter)
+		entries, err := policyAsset.GetDirectoryServiceEntries(ctx, dseFilter)
 		if err != nil {
 			log.Fatalf("Failed to get directory service entries: %v", err)
 		}
@@ -83,7 +85,7 @@ func main() {
 		policiesFilter := safeguard.Filter{
 			Fields: []string{"PolicyId", "PolicyName"},
 		}
-		policies, err := policyAsset.GetPolicies(policiesFilter)
+		policies, err := policyAsset.GetPolicies(ctx, policiesFilter)
 		if err != nil {
 			log.Fatalf("Failed to get policies: %v", err)
 		}
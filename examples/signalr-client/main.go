@@ -11,6 +11,7 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
 	applianceUrl := os.Getenv("SAFEGUARD_HOST_URL")
 	apiVersion := os.Getenv("SAFEGUARD_API_VERSION")
 	pfxPassword := os.Getenv("SAFEGUARD_PFX_PASSWORD")
@@ -23,7 +24,7 @@ func main() {
 		panic(err)
 	}
 
-	err = sgc.ValidateAccessToken()
+	err = sgc.ValidateAccessToken(ctx)
 	if err != nil {
 		fmt.Println(err)
 		panic(err)
@@ -0,0 +1,333 @@
+package safeguard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FailoverPolicy configures failover of reads across the members of a Safeguard cluster.
+type FailoverPolicy struct {
+	RefreshInterval time.Duration // How long the discovered list of cluster members is used before it is refreshed
+	Cooldown        time.Duration // How long a member or the appliance URL that failed is skipped
+}
+
+// DefaultFailoverPolicy returns the failover policy used by WithClusterFailover.
+// The member list is refreshed every five minutes and failed members are
+// skipped for 30 seconds.
+func DefaultFailoverPolicy() FailoverPolicy {
+	return FailoverPolicy{
+		RefreshInterval: 5 * time.Minute,
+		Cooldown:        30 * time.Second,
+	}
+}
+
+// clusterFailover keeps the ranked list of cluster member URLs used for failover.
+type clusterFailover struct {
+	sync.Mutex
+
+	policy      FailoverPolicy
+	endpoints   []string
+	nextRefresh time.Time
+	failedUntil map[string]time.Time
+	active      string // Member serving reads while the appliance URL cools down; empty if none
+}
+
+// healthRank orders health states from most to least preferred.
+// Unavailable members are never used.
+var healthRank = map[HealthStatus]int{
+	HealthStatusHealthy: 0,
+	HealthStatusWarning: 1,
+	HealthStatusUnknown: 2,
+	HealthStatusError:   3,
+}
+
+// rankClusterMembers returns the enrolled, available members ordered by health.
+// Members with the same health keep the order returned by the appliance.
+//
+// Parameters:
+//   - members: The cluster members as returned by Cluster/Members.
+//
+// Returns:
+//   - []ClusterMember: The usable members, healthiest first.
+func rankClusterMembers(members []ClusterMember) []ClusterMember {
+	ranked := make([]ClusterMember, 0, len(members))
+	for _, m := range members {
+		if _, ok := healthRank[m.Health.Status]; ok && m.IsEnrolled {
+			ranked = append(ranked, m)
+		}
+	}
+
+	slices.SortStableFunc(ranked, func(a, b ClusterMember) int {
+		return healthRank[a.Health.Status] - healthRank[b.Health.Status]
+	})
+	return ranked
+}
+
+// EnableFailover turns on failover of reads across cluster members.
+// The member list is discovered lazily with the client's credentials, so
+// failover becomes effective after the first successful login.
+// It must not be called concurrently with requests.
+//
+// Parameters:
+//   - policy: The failover settings.
+func (c *SafeguardClient) EnableFailover(policy FailoverPolicy) {
+	c.failover = &clusterFailover{
+		policy:      policy,
+		failedUntil: make(map[string]time.Time),
+	}
+}
+
+// ClusterEndpoints returns the URLs of the known cluster members, ranked by health.
+// It returns nil if failover is not enabled or no members were discovered yet.
+func (c *SafeguardClient) ClusterEndpoints() []string {
+	if c.failover == nil {
+		return nil
+	}
+	c.failover.Lock()
+	defer c.failover.Unlock()
+	return slices.Clone(c.failover.endpoints)
+}
+
+// RefreshClusterEndpoints discovers the cluster members and their health and
+// updates the ranked endpoint list and the cluster leader. The members are
+// queried from the current appliance first, then from the known members.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//
+// Returns:
+//   - error: An error if failover is not enabled or no member could be queried.
+func (c *SafeguardClient) RefreshClusterEndpoints(ctx context.Context) error {
+	if c.failover == nil {
+		return errors.New("cluster failover is not enabled")
+	}
+
+	f := c.failover
+	f.Lock()
+	// Concurrent callers keep using the current list while one refreshes
+	f.nextRefresh = time.Now().Add(f.policy.Cooldown)
+	f.Unlock()
+
+	nodes := append([]string{c.Appliance.getUrl()}, f.candidates(c.Appliance.getUrl())...)
+
+	var errs []error
+	for _, node := range nodes {
+		members, err := c.getClusterMembersFromNode(ctx, node)
+		if err != nil {
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		endpoints := make([]string, 0, len(members))
+		for _, m := range rankClusterMembers(members) {
+			nodeUrl, err := c.generateClusterLeaderURL(m.Name)
+			if err != nil {
+				continue
+			}
+			endpoints = append(endpoints, nodeUrl)
+			if m.IsLeader {
+				c.setClusterLeader(m.Name)
+			}
+		}
+
+		f.Lock()
+		f.endpoints = endpoints
+		f.nextRefresh = time.Now().Add(f.policy.RefreshInterval)
+		f.Unlock()
+
+		c.logger().Debug("Cluster endpoints refreshed", "endpoints", endpoints)
+		return nil
+	}
+
+	return fmt.Errorf("failed to discover cluster members: %w", errors.Join(errs...))
+}
+
+// getClusterMembersFromNode queries the cluster members from a specific node
+// without failing over to other nodes.
+func (c *SafeguardClient) getClusterMembersFromNode(ctx context.Context, nodeUrl string) ([]ClusterMember, error) {
+	path := "Cluster/Members?fields=Name,IsLeader,IsEnrolled,Health"
+	response, err := c.getFromNode(ctx, nodeUrl, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []ClusterMember
+	if err := json.Unmarshal(response.Body, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// getFromNode sends a GET request for path to the given node.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - nodeUrl: The base URL of the node, e.g. "https://node2.example.com:443".
+//   - path: The API path relative to the core service root.
+//   - read: Consumes the body of a successful response, or nil to buffer it.
+//
+// Returns:
+//   - *Response: The response, or nil if no response was received.
+//   - error: An error if the request fails.
+func (c *SafeguardClient) getFromNode(ctx context.Context, nodeUrl, path string, read bodyReader) (*Response, error) {
+	url := fmt.Sprintf("%s/service/core/%s/%s", nodeUrl, c.ApiVersion, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.sendRequest(req, read)
+}
+
+// refreshClusterEndpointsIfStale refreshes the endpoint list when it has expired.
+// Failures are logged and retried after the cooldown.
+func (c *SafeguardClient) refreshClusterEndpointsIfStale(ctx context.Context) {
+	f := c.failover
+	f.Lock()
+	stale := time.Now().After(f.nextRefresh)
+	f.Unlock()
+
+	if !stale {
+		return
+	}
+	if err := c.RefreshClusterEndpoints(ctx); err != nil {
+		c.logger().Debug("Cluster endpoint discovery failed", "error", err)
+	}
+}
+
+// readNode returns the base URL that serves reads: the appliance URL, or the
+// cluster member that answered the last failed over read while the appliance
+// URL cools down. Once the cooldown has passed, the appliance URL is tried again.
+func (c *SafeguardClient) readNode() string {
+	primary := c.Appliance.getUrl()
+	if c.failover == nil {
+		return primary
+	}
+	return c.failover.readNode(primary)
+}
+
+// failoverRead repeats a failed GET request on the other cluster members in
+// order of their health. The first member that answers serves subsequent reads
+// until the cooldown of the appliance URL has passed; the configured appliance
+// URL itself is never changed.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - failed: The base URL of the node the request failed on.
+//   - path: The API path of the failed request.
+//   - cause: The error of the failed request.
+//   - read: Consumes the body of a successful response, or nil to buffer it.
+//
+// Returns:
+//   - *Response: The response from the member that answered.
+//   - error: cause if no other member could serve the request.
+func (c *SafeguardClient) failoverRead(ctx context.Context, failed, path string, cause error, read bodyReader) (*Response, error) {
+	c.failover.markFailed(failed)
+
+	for _, node := range c.failover.candidates(failed) {
+		resp, err := c.getFromNode(ctx, node, path, read)
+		if err == nil {
+			c.logger().Warn("Failed over reads to cluster member",
+				"from", failed,
+				"to", node,
+				"error", cause,
+			)
+			c.failover.setActive(node)
+			return resp, nil
+		}
+		if !isFailoverError(err) {
+			return resp, err
+		}
+		c.failover.markFailed(node)
+	}
+
+	return nil, cause
+}
+
+// handleWriteFailure forces the cluster leader to be resolved again after a
+// write failed in a way that suggests the leader moved or went down. The failed
+// write itself is not repeated.
+func (c *SafeguardClient) handleWriteFailure(err error) {
+	if c.failover == nil || !isFailoverError(err) {
+		return
+	}
+	c.logger().Warn("Write request failed, re-resolving cluster leader", "error", err)
+	c.ClusterLeader.expire()
+}
+
+// markFailed skips the given endpoint until the cooldown has passed.
+func (f *clusterFailover) markFailed(url string) {
+	f.Lock()
+	defer f.Unlock()
+	f.failedUntil[url] = time.Now().Add(f.policy.Cooldown)
+}
+
+// setActive makes the given member serve reads while the appliance URL cools down.
+func (f *clusterFailover) setActive(url string) {
+	f.Lock()
+	defer f.Unlock()
+	f.active = url
+}
+
+// readNode returns the active member while primary cools down and primary
+// otherwise.
+func (f *clusterFailover) readNode(primary string) string {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.active == "" {
+		return primary
+	}
+	now := time.Now()
+	if now.Before(f.failedUntil[primary]) && !now.Before(f.failedUntil[f.active]) {
+		return f.active
+	}
+	f.active = ""
+	return primary
+}
+
+// candidates returns the ranked endpoints that are not cooling down,
+// excluding the given URL.
+func (f *clusterFailover) candidates(exclude string) []string {
+	f.Lock()
+	defer f.Unlock()
+
+	now := time.Now()
+	candidates := make([]string, 0, len(f.endpoints))
+	for _, url := range f.endpoints {
+		if url == exclude || now.Before(f.failedUntil[url]) {
+			continue
+		}
+		candidates = append(candidates, url)
+	}
+	return candidates
+}
+
+// isFailoverError reports whether an error indicates that the node could not
+// serve the request: a connection error or an HTTP 5xx response.
+// Cancellations by the caller and failures while a streamed response body was
+// consumed never trigger a failover.
+func isFailoverError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var bodyErr *bodyError
+	if errors.As(err, &bodyErr) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
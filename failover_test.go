@@ -0,0 +1,203 @@
+package safeguard
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// routingTransport sends requests for node host names to local test servers.
+type routingTransport map[string]*httptest.Server
+
+func (rt routingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	server, ok := rt[req.URL.Hostname()]
+	if !ok {
+		return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: http.ErrServerClosed}
+	}
+	target, _ := url.Parse(server.URL)
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// testNode is a fake cluster member that can be switched off.
+type testNode struct {
+	down     atomic.Bool
+	requests atomic.Int32
+}
+
+func newFailoverTestClient(t *testing.T, members []ClusterMember) (*SafeguardClient, map[string]*testNode) {
+	t.Helper()
+
+	nodes := map[string]*testNode{}
+	transport := routingTransport{}
+	for _, m := range members {
+		node := &testNode{}
+		nodes[m.Name] = node
+		name := m.Name
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			node.requests.Add(1)
+			if node.down.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if strings.HasSuffix(r.URL.Path, "/Missing") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if strings.HasSuffix(r.URL.Path, "/Cluster/Members") {
+				json.NewEncoder(w).Encode(members)
+				return
+			}
+			w.Write([]byte(`"` + name + `"`))
+		}))
+		t.Cleanup(server.Close)
+		transport[name+".example.com"] = server
+	}
+
+	client := &SafeguardClient{
+		AccessToken: &RSTSAuthResponse{UserToken: "test-user-token"},
+		HttpClient:  &http.Client{Transport: transport},
+		ApiVersion:  "v4",
+	}
+	client.Appliance.setUrl("http://node1.example.com:80", -1)
+	client.ClusterLeader.setUrl("http://node1.example.com:80", -1)
+	client.EnableFailover(DefaultFailoverPolicy())
+	return client, nodes
+}
+
+func testMember(name string, leader bool, status HealthStatus) ClusterMember {
+	return ClusterMember{Name: name, IsLeader: leader, IsEnrolled: true, Health: NodeHealth{Status: status}}
+}
+
+func TestRankClusterMembers(t *testing.T) {
+	members := []ClusterMember{
+		testMember("error", false, HealthStatusError),
+		testMember("warning", false, HealthStatusWarning),
+		testMember("unavailable", false, HealthStatusUnavailable),
+		testMember("healthy", true, HealthStatusHealthy),
+		{Name: "unenrolled", Health: NodeHealth{Status: HealthStatusHealthy}},
+	}
+
+	var names []string
+	for _, m := range rankClusterMembers(members) {
+		names = append(names, m.Name)
+	}
+
+	if got, want := strings.Join(names, ","), "healthy,warning,error"; got != want {
+		t.Errorf("ranked members = %s, want %s", got, want)
+	}
+}
+
+func TestFailoverReadsToHealthyMember(t *testing.T) {
+	client, nodes := newFailoverTestClient(t, []ClusterMember{
+		testMember("node1", true, HealthStatusHealthy),
+		testMember("node3", false, HealthStatusUnavailable),
+		testMember("node2", false, HealthStatusWarning),
+	})
+	ctx := context.Background()
+
+	if _, err := client.GetRequest(ctx, "Assets"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"http://node1.example.com:80", "http://node2.example.com:80"}
+	if got := client.ClusterEndpoints(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("ClusterEndpoints() = %v, want %v", got, want)
+	}
+
+	nodes["node1"].down.Store(true)
+
+	body, err := client.GetRequest(ctx, "Assets")
+	if err != nil {
+		t.Fatalf("expected failover to succeed, got %v", err)
+	}
+	if string(body) != `"node2"` {
+		t.Errorf("expected response from node2, got %s", body)
+	}
+	if got := client.Appliance.getUrl(); got != "http://node1.example.com:80" {
+		t.Errorf("expected the configured appliance URL to be kept, got %s", got)
+	}
+	if nodes["node3"].requests.Load() != 0 {
+		t.Error("unavailable member must not be used")
+	}
+}
+
+func TestFailoverReturnsToApplianceURL(t *testing.T) {
+	client, nodes := newFailoverTestClient(t, []ClusterMember{
+		testMember("node1", true, HealthStatusHealthy),
+		testMember("node2", false, HealthStatusHealthy),
+	})
+	client.failover.policy.Cooldown = 100 * time.Millisecond
+	ctx := context.Background()
+
+	if err := client.RefreshClusterEndpoints(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nodes["node1"].down.Store(true)
+	if body, err := client.GetRequest(ctx, "Assets"); err != nil || string(body) != `"node2"` {
+		t.Fatalf("expected failover to node2, got %s (%v)", body, err)
+	}
+
+	// While the appliance URL cools down, reads stay on node2
+	before := nodes["node1"].requests.Load()
+	if body, err := client.GetRequest(ctx, "Assets"); err != nil || string(body) != `"node2"` {
+		t.Fatalf("expected node2 during the cooldown, got %s (%v)", body, err)
+	}
+	if nodes["node1"].requests.Load() != before {
+		t.Error("the appliance URL must not be tried during its cooldown")
+	}
+
+	// Afterwards the recovered appliance URL serves reads again
+	nodes["node1"].down.Store(false)
+	time.Sleep(150 * time.Millisecond)
+	if body, err := client.GetRequest(ctx, "Assets"); err != nil || string(body) != `"node1"` {
+		t.Errorf("expected the appliance URL to be used again, got %s (%v)", body, err)
+	}
+}
+
+func TestFailoverIgnoresClientErrors(t *testing.T) {
+	client, nodes := newFailoverTestClient(t, []ClusterMember{
+		testMember("node1", true, HealthStatusHealthy),
+		testMember("node2", false, HealthStatusHealthy),
+	})
+	if err := client.RefreshClusterEndpoints(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.GetRequest(context.Background(), "Missing"); !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if isFailoverError(context.Canceled) {
+		t.Error("cancellation must not trigger a failover")
+	}
+	if nodes["node2"].requests.Load() != 0 {
+		t.Error("expected no requests to node2")
+	}
+}
+
+func TestWriteFailureReResolvesLeader(t *testing.T) {
+	client, nodes := newFailoverTestClient(t, []ClusterMember{
+		testMember("node1", true, HealthStatusHealthy),
+		testMember("node2", false, HealthStatusHealthy),
+	})
+	ctx := context.Background()
+	client.ClusterLeader.setUrl("http://node2.example.com:80", time.Hour)
+
+	nodes["node2"].down.Store(true)
+	if _, err := client.PutRequest(ctx, "Assets/1", nil); !hasStatus(err, http.StatusServiceUnavailable) {
+		t.Fatalf("expected HTTP 503, got %v", err)
+	}
+	if !client.ClusterLeader.isExpired() {
+		t.Fatal("expected cluster leader to be resolved again after a failed write")
+	}
+
+	if got := client.getClusterLeaderUrl(ctx); got != "http://node1.example.com:80" {
+		t.Errorf("expected leader to be re-resolved to node1, got %s", got)
+	}
+}
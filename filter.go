@@ -5,6 +5,7 @@ package safeguard
 
 import (
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -126,12 +127,14 @@ func (o OrderBy) String() string {
 }
 
 // Filter represents a complete set of query parameters for filtering API results.
-// It combines field selection, filtering conditions, ordering, and count options.
+// It combines field selection, filtering conditions, ordering, count and paging options.
 type Filter struct {
 	Fields  Fields        `json:"fields,omitempty"`  // Fields to include in the response
 	Filter  []FilterQuery `json:"filter,omitempty"`  // Filter conditions to apply
 	Orderby OrderBy       `json:"orderby,omitempty"` // Fields to order the results by
 	Count   bool          `json:"count,omitempty"`   // Whether to include a count of total results
+	Page    int           `json:"page,omitempty"`    // Zero-based page to return (requires Limit)
+	Limit   int           `json:"limit,omitempty"`   // Maximum number of results per page (0 means no paging)
 }
 
 // AddField adds a field to the list of fields to be included in the query.
@@ -189,12 +192,12 @@ func (f *Filter) GetOrderBy() OrderBy {
 }
 
 // ToQueryString generates a complete URL query string based on all filter parameters.
-// The query string includes fields, filter conditions, ordering, and count options.
+// The query string includes fields, filter conditions, ordering, count and paging options.
 // Returns:
 //   - The fully formatted query string starting with "?".
 func (f *Filter) ToQueryString() string {
-	// Pre-allocate slice with capacity of 4 (max number of possible parameters)
-	queryParams := make([]string, 0, 4)
+	// Pre-allocate slice with capacity of 5 (max number of possible parameters)
+	queryParams := make([]string, 0, 5)
 
 	// Directly append the results of generate functions if they're not empty
 	if filter := f.generateFilterQuery(); filter != "" {
@@ -210,6 +213,9 @@ func (f *Filter) ToQueryString() string {
 	if orderBy := f.generateOrderByQuery(); orderBy != "" {
 		queryParams = append(queryParams, orderBy)
 	}
+	if paging := f.generatePageQuery(); paging != "" {
+		queryParams = append(queryParams, paging)
+	}
 
 	return "?" + strings.Join(queryParams, "&")
 }
@@ -236,6 +242,17 @@ func (f *Filter) generateOrderByQuery() string {
 	return "orderby=" + url.PathEscape(f.Orderby.String())
 }
 
+// generatePageQuery builds the page and limit portion of the query string.
+// Paging is only applied when a positive Limit is set.
+// Returns:
+//   - The page and limit query parameter string or empty string if no limit is specified.
+func (f *Filter) generatePageQuery() string {
+	if f.Limit <= 0 {
+		return ""
+	}
+	return "page=" + strconv.Itoa(max(f.Page, 0)) + "&limit=" + strconv.Itoa(f.Limit)
+}
+
 // generateFilterQuery builds the filter portion of the query string.
 // Returns an empty string if no filter conditions are specified.
 // Filter expressions with multiple conditions are wrapped in parentheses.
@@ -321,6 +321,29 @@ func TestToQueryString(t *testing.T) {
 			},
 			expected: `?filter=%28field1%20eq%20%27value1%27%29&fields=field1%2Cfield2&count=true&orderby=field1%2Cfield2`,
 		},
+		{
+			name: "Filter with page and limit",
+			filter: &Filter{
+				Page:  2,
+				Limit: 50,
+			},
+			expected: "?count=false&page=2&limit=50",
+		},
+		{
+			name: "Filter with page but no limit",
+			filter: &Filter{
+				Page: 2,
+			},
+			expected: "?count=false",
+		},
+		{
+			name: "Filter with orderby and limit",
+			filter: &Filter{
+				Orderby: OrderBy{"Id"},
+				Limit:   100,
+			},
+			expected: "?count=false&orderby=Id&page=0&limit=100",
+		},
 		{
 			name: "Filter with multiple filter queries",
 			filter: &Filter{
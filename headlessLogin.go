@@ -0,0 +1,161 @@
+package safeguard
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// installedApplicationRedirectURI makes RSTS show the authorization code in the
+// browser instead of redirecting to a local listener.
+const installedApplicationRedirectURI = "urn:InstalledApplication"
+
+// AuthorizationCodePrompt shows the RSTS authorization URL to the user and
+// returns what the user pasted back: the authorization code or the full URL the
+// browser was redirected to. It should return when ctx is done.
+type AuthorizationCodePrompt func(ctx context.Context, authURL string) (string, error)
+
+// TerminalPrompt returns an AuthorizationCodePrompt that writes the authorization
+// URL to out and reads the authorization code or redirect URL as a line from in.
+//
+// If ctx ends first, the prompt returns ctx.Err(), but the line already being
+// read from in is discarded in the background.
+//
+// Parameters:
+//   - in: The input to read from, e.g. os.Stdin.
+//   - out: The output to write the instructions to, e.g. os.Stderr.
+//
+// Returns:
+//   - AuthorizationCodePrompt: The prompt.
+func TerminalPrompt(in io.Reader, out io.Writer) AuthorizationCodePrompt {
+	return func(ctx context.Context, authURL string) (string, error) {
+		fmt.Fprintf(out, "Open the following URL in a browser and log in:\n\n    %s\n\n", authURL)
+		fmt.Fprint(out, "Paste the authorization code or the URL you were redirected to: ")
+
+		type result struct {
+			line string
+			err  error
+		}
+		lines := make(chan result, 1)
+		go func() {
+			line, err := bufio.NewReader(in).ReadString('\n')
+			if err == io.EOF && line != "" {
+				err = nil
+			}
+			lines <- result{line, err}
+		}()
+
+		select {
+		case r := <-lines:
+			if r.err != nil {
+				return "", fmt.Errorf("failed to read authorization code: %w", r.err)
+			}
+			return r.line, nil
+		case <-ctx.Done():
+			fmt.Fprintln(out)
+			return "", ctx.Err()
+		}
+	}
+}
+
+// LoginWithOauthHeadless logs in with the OAuth authorization code flow without
+// a browser or a local listener, e.g. over SSH or in a container. The user opens
+// the authorization URL on any device, logs in and pastes the authorization code
+// or the redirect URL back into the prompt. The flow uses PKCE like
+// LoginWithOauth.
+//
+// Parameters:
+//   - ctx: Context bounding the whole login, including the time the user takes.
+//   - prompt: Asks the user for the authorization code; nil prompts on the terminal
+//     with TerminalPrompt(os.Stdin, os.Stderr).
+//
+// Returns:
+//   - error: An error if the user does not answer before ctx ends, the answer
+//     contains no authorization code or the code cannot be redeemed.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+//	defer cancel()
+//	if err := client.LoginWithOauthHeadless(ctx, nil); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *SafeguardClient) LoginWithOauthHeadless(ctx context.Context, prompt AuthorizationCodePrompt) (err error) {
+	ctx, end := c.traceLogin(ctx, LoginMethodOAuth)
+	defer end(&err)
+
+	if prompt == nil {
+		prompt = TerminalPrompt(os.Stdin, os.Stderr)
+	}
+	return c.login(ctx, LoginMethodOAuth, func(ctx context.Context) error {
+		return c.loginWithOauthHeadless(ctx, prompt)
+	}, nil)
+}
+
+// loginWithOauthHeadless performs the login of LoginWithOauthHeadless.
+func (c *SafeguardClient) loginWithOauthHeadless(ctx context.Context, prompt AuthorizationCodePrompt) error {
+	if c.AccessToken == nil {
+		c.AccessToken = &RSTSAuthResponse{}
+	}
+
+	codeVerifier, codeChallenge := generateCodeChallenge()
+	authURL := fmt.Sprintf("%s/RSTS/Login?response_type=code&code_challenge_method=S256&code_challenge=%s&redirect_uri=%s",
+		c.Appliance.getUrl(), codeChallenge, url.QueryEscape(installedApplicationRedirectURI))
+
+	answer, err := prompt(ctx, authURL)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	code, err := parseAuthorizationCode(answer)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	c.logger().Info("Authorization Code received")
+
+	return c.redeemAuthorizationCode(ctx, code, installedApplicationRedirectURI, codeVerifier)
+}
+
+// parseAuthorizationCode extracts the authorization code from what the user
+// pasted: either the code itself or a redirect URL with an "oauth" or "code"
+// query parameter.
+//
+// Parameters:
+//   - input: The pasted text.
+//
+// Returns:
+//   - string: The authorization code.
+//   - error: An error if the input contains no code, or an *OAuthError if RSTS
+//     redirected with an error.
+func parseAuthorizationCode(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", errors.New("no authorization code entered")
+	}
+
+	if !strings.Contains(input, "?") {
+		if strings.ContainsAny(input, " \t/") {
+			return "", fmt.Errorf("%q is neither an authorization code nor a redirect URL", input)
+		}
+		return input, nil
+	}
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return "", fmt.Errorf("invalid redirect URL: %w", err)
+	}
+	params := u.Query()
+	if e := params.Get("error"); e != "" {
+		return "", &OAuthError{Code: e, Description: params.Get("error_description")}
+	}
+	for _, name := range []string{"oauth", "code"} {
+		if code := params.Get(name); code != "" {
+			return code, nil
+		}
+	}
+	return "", errors.New("no authorization code found in redirect URL")
+}
@@ -0,0 +1,107 @@
+package safeguard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAuthorizationCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "code", input: "abc123\n", want: "abc123"},
+		{name: "redirect URL", input: "http://localhost:8400/?oauth=abc123", want: "abc123"},
+		{name: "code parameter", input: "https://app.example.com/callback?code=abc123&state=x", want: "abc123"},
+		{name: "empty", input: " \n", wantErr: true},
+		{name: "sentence", input: "no code here", wantErr: true},
+		{name: "URL without code", input: "http://localhost:8400/?state=x", wantErr: true},
+		{name: "denied", input: "http://localhost:8400/?error=access_denied", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAuthorizationCode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAuthorizationCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseAuthorizationCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginWithOauthHeadless(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+	ctx := context.Background()
+
+	var challenge string
+	prompt := func(ctx context.Context, authURL string) (string, error) {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return "", err
+		}
+		if u.Query().Get("redirect_uri") != installedApplicationRedirectURI || u.Query().Get("code_challenge_method") != "S256" {
+			t.Errorf("unexpected authorization URL %s", authURL)
+		}
+		challenge = u.Query().Get("code_challenge")
+		return "urn:InstalledApplication?oauth=code-alice", nil
+	}
+
+	if err := client.LoginWithOauthHeadless(ctx, prompt); err != nil {
+		t.Fatalf("LoginWithOauthHeadless() error = %v", err)
+	}
+	if name, err := me(ctx, client); err != nil || name != "alice" {
+		t.Errorf("expected alice, got %q (%v)", name, err)
+	}
+
+	hash := sha256.Sum256([]byte(f.codeVerifier))
+	if base64.RawURLEncoding.EncodeToString(hash[:]) != challenge {
+		t.Error("code verifier does not match the code challenge")
+	}
+	if f.redirectURI != installedApplicationRedirectURI {
+		t.Errorf("unexpected redirect URI %q", f.redirectURI)
+	}
+}
+
+func TestTerminalPrompt(t *testing.T) {
+	var out strings.Builder
+	prompt := TerminalPrompt(strings.NewReader("code-alice\n"), &out)
+
+	got, err := prompt(context.Background(), "https://appliance/RSTS/Login?x=1")
+	if err != nil || got != "code-alice\n" {
+		t.Errorf("expected the pasted line, got %q (%v)", got, err)
+	}
+	if !strings.Contains(out.String(), "https://appliance/RSTS/Login?x=1") {
+		t.Errorf("expected the URL to be printed, got %q", out.String())
+	}
+}
+
+func TestLoginWithOauthHeadlessTimeout(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+
+	in, w := io.Pipe()
+	defer w.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := client.LoginWithOauthHeadless(ctx, TerminalPrompt(in, io.Discard))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if got := f.loginCount(); got != 0 {
+		t.Errorf("expected no login, got %d", got)
+	}
+}
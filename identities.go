@@ -1,8 +1,10 @@
 package safeguard
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 )
 
 // Identity represents the user or identity that manages an asset account,
@@ -28,13 +30,13 @@ func (a Identity) SetClient(c *SafeguardClient) any {
 	return a
 }
 
-func (c *SafeguardClient) GetIdentities(filter Filter) ([]Identity, error) {
+func (c *SafeguardClient) GetIdentities(ctx context.Context, filter Filter) ([]Identity, error) {
 
 	var identities []Identity
 
 	query := "Identities" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +49,43 @@ func (c *SafeguardClient) GetIdentities(filter Filter) ([]Identity, error) {
 
 }
 
-func (c *SafeguardClient) GetIdentity(id int, fields Fields) (Identity, error) {
+// Identities returns an iterator over all identities matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.Identities(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[Identity, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) Identities(ctx context.Context, filter Filter) iter.Seq2[Identity, error] {
+	return paginate[Identity](ctx, c, "Identities", filter)
+}
+
+// CountIdentities returns the total number of identities matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching identities
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountIdentities(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "Identities", filter)
+}
+
+func (c *SafeguardClient) GetIdentity(ctx context.Context, id int, fields Fields) (Identity, error) {
 	var identity Identity
 
 	query := fmt.Sprintf("Identities/%d", id)
@@ -56,7 +94,7 @@ func (c *SafeguardClient) GetIdentity(id int, fields Fields) (Identity, error) {
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return identity, err
 	}
@@ -75,12 +113,13 @@ func (c *SafeguardClient) GetIdentity(id int, fields Fields) (Identity, error) {
 // If successful, it returns the IdentityProvider object with the API client added; otherwise, it returns an error.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: Fields specifying additional fields to include in the query.
 //
 // Returns:
 //   - IdentityProvider: The identity provider associated with the identity.
 //   - error: An error if the request or unmarshalling fails.
-func (i Identity) GetIdentityProvider(fields Fields) (IdentityProvider, error) {
+func (i Identity) GetIdentityProvider(ctx context.Context, fields Fields) (IdentityProvider, error) {
 	var identityProviders IdentityProvider
 
 	query := fmt.Sprintf("Identities/%d/IdentityProvider", i.Id)
@@ -89,7 +128,7 @@ func (i Identity) GetIdentityProvider(fields Fields) (IdentityProvider, error) {
 		query += fields.ToQueryString()
 	}
 
-	response, err := i.apiClient.GetRequest(query)
+	response, err := i.apiClient.GetRequest(ctx, query)
 	if err != nil {
 		return identityProviders, err
 	}
@@ -109,12 +148,13 @@ func (i Identity) GetIdentityProvider(fields Fields) (IdentityProvider, error) {
 // If any error occurs during the request or unmarshalling, it returns the error.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: Fields specifying which fields to include in the query.
 //
 // Returns:
 //   - User: The User associated with the Identity.
 //   - error: An error if the request or unmarshalling fails.
-func (i Identity) GetUser(fields Fields) (User, error) {
+func (i Identity) GetUser(ctx context.Context, fields Fields) (User, error) {
 	var user User
 
 	query := fmt.Sprintf("Identities/%d/User", i.Id)
@@ -123,7 +163,7 @@ func (i Identity) GetUser(fields Fields) (User, error) {
 		query += fields.ToQueryString()
 	}
 
-	response, err := i.apiClient.GetRequest(query)
+	response, err := i.apiClient.GetRequest(ctx, query)
 	if err != nil {
 		return user, err
 	}
@@ -142,12 +182,13 @@ func (i Identity) GetUser(fields Fields) (User, error) {
 // It returns the UserGroup and an error if any occurred during the request or unmarshalling of the response.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: Fields specifying the fields to be included in the query.
 //
 // Returns:
 //   - UserGroup: The UserGroup associated with the Identity.
 //   - error: An error if any occurred during the request or unmarshalling of the response.
-func (i Identity) GetUserGroup(fields Fields) (UserGroup, error) {
+func (i Identity) GetUserGroup(ctx context.Context, fields Fields) (UserGroup, error) {
 	var userGroup UserGroup
 
 	query := fmt.Sprintf("Identities/%d/UserGroup", i.Id)
@@ -156,7 +197,7 @@ func (i Identity) GetUserGroup(fields Fields) (UserGroup, error) {
 		query += fields.ToQueryString()
 	}
 
-	response, err := i.apiClient.GetRequest(query)
+	response, err := i.apiClient.GetRequest(ctx, query)
 	if err != nil {
 		return userGroup, err
 	}
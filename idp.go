@@ -2,6 +2,7 @@ package safeguard
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -167,12 +168,12 @@ type GroupProperties struct {
 // Returns:
 //   - []IdentityProvider: A slice of all configured identity providers
 //   - error: An error if the API request fails or response cannot be parsed
-func (c *SafeguardClient) GetIdentityProviders() ([]IdentityProvider, error) {
+func (c *SafeguardClient) GetIdentityProviders(ctx context.Context) ([]IdentityProvider, error) {
 	var identityProviders []IdentityProvider
 
 	query := "IdentityProviders"
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return []IdentityProvider{}, err
 	}
@@ -190,17 +191,18 @@ func (c *SafeguardClient) GetIdentityProviders() ([]IdentityProvider, error) {
 // provider, including all its type-specific properties and settings.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the identity provider
 //
 // Returns:
 //   - IdentityProvider: The requested identity provider's complete configuration
 //   - error: An error if the provider cannot be found or the request fails
-func (c *SafeguardClient) GetIdentityProvider(id int) (IdentityProvider, error) {
+func (c *SafeguardClient) GetIdentityProvider(ctx context.Context, id int) (IdentityProvider, error) {
 	var identityProvider IdentityProvider
 
 	query := fmt.Sprintf("IdentityProviders/%d", id)
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return IdentityProvider{}, err
 	}
@@ -217,13 +219,14 @@ func (c *SafeguardClient) GetIdentityProvider(id int) (IdentityProvider, error)
 // If there is an error during the process, it returns an empty IdentityProvider object and the error.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The ID of the identity provider to update.
 //   - updatedIdp: The updated identity provider data.
 //
 // Returns:
 //   - IdentityProvider: The updated identity provider object.
 //   - error: An error object if there was an issue with the update process, otherwise nil.
-func (c *SafeguardClient) UpdateIdentityProvider(id int, updatedIdp IdentityProvider) (IdentityProvider, error) {
+func (c *SafeguardClient) UpdateIdentityProvider(ctx context.Context, id int, updatedIdp IdentityProvider) (IdentityProvider, error) {
 	var identityProvider IdentityProvider
 
 	query := fmt.Sprintf("IdentityProviders/%d", id)
@@ -233,7 +236,7 @@ func (c *SafeguardClient) UpdateIdentityProvider(id int, updatedIdp IdentityProv
 		return IdentityProvider{}, err
 	}
 
-	response, err := c.PutRequest(query, bytes.NewReader(jsonData))
+	response, err := c.PutRequest(ctx, query, bytes.NewReader(jsonData))
 	if err != nil {
 		return IdentityProvider{}, err
 	}
@@ -249,14 +252,15 @@ func (c *SafeguardClient) UpdateIdentityProvider(id int, updatedIdp IdentityProv
 //
 // Parameters:
 //
+//	ctx - Context for cancellation and timeouts.
 //	updatedIdp - The IdentityProvider containing the updated information.
 //
 // Returns:
 //
 //	IdentityProvider - The updated IdentityProvider.
 //	error - An error if the update operation fails, otherwise nil.
-func (idp IdentityProvider) Update(updatedIdp IdentityProvider) (IdentityProvider, error) {
-	return idp.apiClient.UpdateIdentityProvider(idp.Id, updatedIdp)
+func (idp IdentityProvider) Update(ctx context.Context, updatedIdp IdentityProvider) (IdentityProvider, error) {
+	return idp.apiClient.UpdateIdentityProvider(ctx, idp.Id, updatedIdp)
 }
 
 // AddIdentityProvider adds a new identity provider to the Safeguard system.
@@ -264,12 +268,13 @@ func (idp IdentityProvider) Update(updatedIdp IdentityProvider) (IdentityProvide
 // along with any error encountered during the process.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - idp: IdentityProvider object containing the details of the identity provider to be added.
 //
 // Returns:
 //   - IdentityProvider: The newly created IdentityProvider object.
 //   - error: An error object if an error occurred, otherwise nil.
-func (c *SafeguardClient) AddIdentityProvider(idp IdentityProvider) (IdentityProvider, error) {
+func (c *SafeguardClient) AddIdentityProvider(ctx context.Context, idp IdentityProvider) (IdentityProvider, error) {
 	var identityProvider IdentityProvider
 
 	query := "IdentityProviders"
@@ -279,7 +284,7 @@ func (c *SafeguardClient) AddIdentityProvider(idp IdentityProvider) (IdentityPro
 		return IdentityProvider{}, err
 	}
 
-	response, err := c.PostRequest(query, bytes.NewReader(jsonData))
+	response, err := c.PostRequest(ctx, query, bytes.NewReader(jsonData))
 	if err != nil {
 		return IdentityProvider{}, err
 	}
@@ -295,15 +300,16 @@ func (c *SafeguardClient) AddIdentityProvider(idp IdentityProvider) (IdentityPro
 // returns the resulting ActivityLog or an error if the request or unmarshalling fails.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The ID of the identity provider to synchronize.
 //
 // Returns:
 //   - ActivityLog: The activity log resulting from the synchronization.
 //   - error: An error if the request or unmarshalling fails.
-func (c *SafeguardClient) SynchronizeIdentityProvider(id int) (ActivityLog, error) {
+func (c *SafeguardClient) SynchronizeIdentityProvider(ctx context.Context, id int) (ActivityLog, error) {
 	query := fmt.Sprintf("IdentityProviders/%d/Synchronize", id)
 
-	response, err := c.PostRequest(query, nil)
+	response, err := c.PostRequest(ctx, query, nil)
 	if err != nil {
 		return ActivityLog{}, err
 	}
@@ -318,24 +324,25 @@ func (c *SafeguardClient) SynchronizeIdentityProvider(id int) (ActivityLog, erro
 // Synchronize synchronizes the identity provider with the external system.
 // It returns an ActivityLog containing details of the synchronization process,
 // or an error if the synchronization fails.
-func (idp IdentityProvider) Synchronize() (ActivityLog, error) {
-	return idp.apiClient.SynchronizeIdentityProvider(idp.Id)
+func (idp IdentityProvider) Synchronize(ctx context.Context) (ActivityLog, error) {
+	return idp.apiClient.SynchronizeIdentityProvider(ctx, idp.Id)
 }
 
 // DeleteIdentityProvider deletes an identity provider by its ID.
 //
 // Parameters:
 //
+//	ctx - Context for cancellation and timeouts.
 //	id - The ID of the identity provider to be deleted.
 //
 // Returns:
 //
 //	error - An error object if the deletion fails, otherwise nil.
-func (c *SafeguardClient) DeleteIdentityProvider(id int) error {
+func (c *SafeguardClient) DeleteIdentityProvider(ctx context.Context, id int) error {
 
 	query := fmt.Sprintf("IdentityProviders/%d", id)
 
-	_, err := c.DeleteRequest(query)
+	_, err := c.DeleteRequest(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -345,8 +352,8 @@ func (c *SafeguardClient) DeleteIdentityProvider(id int) error {
 // Delete removes the IdentityProvider from the system by calling the
 // apiClient's DeleteIdentityProvider method with the IdentityProvider's Id.
 // It returns an error if the deletion fails.
-func (idp IdentityProvider) Delete() error {
-	return idp.apiClient.DeleteIdentityProvider(idp.Id)
+func (idp IdentityProvider) Delete(ctx context.Context) error {
+	return idp.apiClient.DeleteIdentityProvider(ctx, idp.Id)
 }
 
 // GetDirectoryUsers retrieves users from a specific identity provider's directory.
@@ -355,18 +362,19 @@ func (idp IdentityProvider) Delete() error {
 // It supports pagination and filtering through the filter parameter.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - identityProviderId: The ID of the directory identity provider
 //   - filter: Query parameters to filter the results (e.g., search text, limit, offset)
 //
 // Returns:
 //   - []User: A slice of directory users matching the filter criteria
 //   - error: An error if the directory cannot be queried or the request fails
-func (c *SafeguardClient) GetDirectoryUsers(identityProviderId int, filter Filter) ([]User, error) {
+func (c *SafeguardClient) GetDirectoryUsers(ctx context.Context, identityProviderId int, filter Filter) ([]User, error) {
 	var directoryUsers []User
 
 	query := fmt.Sprintf("IdentityProviders/%d/DirectoryUsers%s", identityProviderId, filter.ToQueryString())
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return []User{}, err
 	}
@@ -384,13 +392,14 @@ func (c *SafeguardClient) GetDirectoryUsers(identityProviderId int, filter Filte
 // function, automatically using this identity provider's ID.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Query parameters to filter the results (e.g., search text, limit, offset)
 //
 // Returns:
 //   - []User: A slice of directory users matching the filter criteria
 //   - error: An error if the directory cannot be queried or the request fails
-func (idp IdentityProvider) GetDirectoryUsers(filter Filter) ([]User, error) {
-	return idp.apiClient.GetDirectoryUsers(idp.Id, filter)
+func (idp IdentityProvider) GetDirectoryUsers(ctx context.Context, filter Filter) ([]User, error) {
+	return idp.apiClient.GetDirectoryUsers(ctx, idp.Id, filter)
 }
 
 // GetDirectoryGroups retrieves groups from a specific identity provider's directory.
@@ -399,18 +408,19 @@ func (idp IdentityProvider) GetDirectoryUsers(filter Filter) ([]User, error) {
 // It supports pagination and filtering through the filter parameter.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The ID of the directory identity provider
 //   - filter: Query parameters to filter the results (e.g., search text, limit, offset)
 //
 // Returns:
 //   - []UserGroup: A slice of directory groups matching the filter criteria
 //   - error: An error if the directory cannot be queried or the request fails
-func (c *SafeguardClient) GetDirectoryGroups(id int, filter Filter) ([]UserGroup, error) {
+func (c *SafeguardClient) GetDirectoryGroups(ctx context.Context, id int, filter Filter) ([]UserGroup, error) {
 	var directoryGroups []UserGroup
 
 	query := fmt.Sprintf("IdentityProviders/%d/DirectoryGroups%s", id, filter.ToQueryString())
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return []UserGroup{}, err
 	}
@@ -428,11 +438,12 @@ func (c *SafeguardClient) GetDirectoryGroups(id int, filter Filter) ([]UserGroup
 // function, automatically using this identity provider's ID.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Query parameters to filter the results (e.g., search text, limit, offset)
 //
 // Returns:
 //   - []UserGroup: A slice of directory groups matching the filter criteria
 //   - error: An error if the directory cannot be queried or the request fails
-func (idp IdentityProvider) GetDirectoryGroups(filter Filter) ([]UserGroup, error) {
-	return idp.apiClient.GetDirectoryGroups(idp.Id, filter)
+func (idp IdentityProvider) GetDirectoryGroups(ctx context.Context, filter Filter) ([]UserGroup, error) {
+	return idp.apiClient.GetDirectoryGroups(ctx, idp.Id, filter)
 }
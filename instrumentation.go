@@ -0,0 +1,197 @@
+package safeguard
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoginMethod identifies how a client authenticates.
+type LoginMethod string
+
+const (
+	// LoginMethodPassword is a login with a username and password.
+	LoginMethodPassword LoginMethod = "password"
+	// LoginMethodCertificate is a login with a client certificate.
+	LoginMethodCertificate LoginMethod = "certificate"
+	// LoginMethodOAuth is an interactive OAuth login in the browser.
+	LoginMethodOAuth LoginMethod = "oauth"
+	// LoginMethodTokenSource is a login with a TokenSource.
+	LoginMethodTokenSource LoginMethod = "tokensource"
+	// LoginMethodTokenCache is a token restored from a TokenCache.
+	LoginMethodTokenCache LoginMethod = "tokencache"
+)
+
+// NodeRole describes which appliance served an API call.
+type NodeRole string
+
+const (
+	// NodeLeader is the cluster leader.
+	NodeLeader NodeRole = "leader"
+	// NodeReplica is a cluster member other than the leader.
+	NodeReplica NodeRole = "replica"
+	// NodeAppliance is the configured appliance URL, e.g. a load balancer, when
+	// it is not the cluster leader.
+	NodeAppliance NodeRole = "appliance"
+)
+
+// APICall describes an API call to a single node. Retries of the call are part
+// of the same APICall.
+type APICall struct {
+	// Method is the HTTP method, e.g. "GET".
+	Method string
+	// Route is the URL path with resource Ids replaced by "{id}", e.g.
+	// "/service/core/v4/AssetAccounts/{id}/ChangePassword". It has a low
+	// cardinality and is suitable as a span name or metric attribute.
+	Route string
+	// Host is the host and port of the node.
+	Host string
+	// Node is the role of the node.
+	Node NodeRole
+}
+
+// APICallResult is the outcome of an APICall.
+type APICallResult struct {
+	// StatusCode is the HTTP status of the last response, or 0 if no response was received.
+	StatusCode int
+	// Attempts is the number of requests sent, including retries.
+	Attempts int
+	// Duration is the time from the first attempt until the call finished.
+	Duration time.Duration
+	// Err is the error of the call, or nil if it succeeded.
+	Err error
+}
+
+// Instrumentation receives telemetry from a client, so that API calls can be
+// traced and measured. The package does not depend on a telemetry library; an
+// implementation maps the calls to spans and metrics, e.g. the OpenTelemetry
+// adapter in the safeguardotel package. Implementations must be safe for
+// concurrent use. Embed NopInstrumentation to implement only some of the methods.
+type Instrumentation interface {
+	// StartAPICall is called before an API call is sent. The returned context is
+	// used for the requests of the call, so middlewares can propagate a span from
+	// it. The returned function is called once with the result of the call.
+	StartAPICall(ctx context.Context, call APICall) (context.Context, func(APICallResult))
+
+	// StartLogin is called when a login starts. The returned function is called
+	// once with the error of the login, or nil if it succeeded.
+	StartLogin(ctx context.Context, method LoginMethod) (context.Context, func(error))
+
+	// TokenRefreshed is called after the client logged in again to renew its
+	// token, either in the background or after the token was rejected.
+	TokenRefreshed(ctx context.Context, method LoginMethod, err error)
+
+	// SignalRConnected is called whenever the SignalR connection of an
+	// EventHandler is created. reconnect is false for the first connection.
+	SignalRConnected(ctx context.Context, reconnect bool, err error)
+
+	// EventDropped is called when an EventHandler drops an event because its
+	// EventChannel is full.
+	EventDropped(ctx context.Context, event SignalREvent)
+}
+
+// NopInstrumentation is an Instrumentation that does nothing.
+type NopInstrumentation struct{}
+
+// StartAPICall implements Instrumentation.
+func (NopInstrumentation) StartAPICall(ctx context.Context, call APICall) (context.Context, func(APICallResult)) {
+	return ctx, func(APICallResult) {}
+}
+
+// StartLogin implements Instrumentation.
+func (NopInstrumentation) StartLogin(ctx context.Context, method LoginMethod) (context.Context, func(error)) {
+	return ctx, func(error) {}
+}
+
+// TokenRefreshed implements Instrumentation.
+func (NopInstrumentation) TokenRefreshed(ctx context.Context, method LoginMethod, err error) {}
+
+// SignalRConnected implements Instrumentation.
+func (NopInstrumentation) SignalRConnected(ctx context.Context, reconnect bool, err error) {}
+
+// EventDropped implements Instrumentation.
+func (NopInstrumentation) EventDropped(ctx context.Context, event SignalREvent) {}
+
+// instrumentation returns the client's instrumentation, or a no-op one.
+func (c *SafeguardClient) instrumentation() Instrumentation {
+	if c.Instrumentation == nil {
+		return NopInstrumentation{}
+	}
+	return c.Instrumentation
+}
+
+// traceLogin starts the instrumentation of a login. It returns the context for
+// the requests of the login, so they become children of the login span, and a
+// function that ends the login with the error it returned; use it as
+//
+//	ctx, end := c.traceLogin(ctx, method)
+//	defer end(&err)
+func (c *SafeguardClient) traceLogin(ctx context.Context, method LoginMethod) (context.Context, func(*error)) {
+	ctx, end := c.instrumentation().StartLogin(ctx, method)
+	return ctx, func(err *error) {
+		end(*err)
+	}
+}
+
+// newAPICall describes a request for the instrumentation.
+func (c *SafeguardClient) newAPICall(req *http.Request) APICall {
+	return APICall{
+		Method: req.Method,
+		Route:  routeTemplate(req.URL.Path),
+		Host:   req.URL.Host,
+		Node:   c.nodeRole(req.URL.Host),
+	}
+}
+
+// nodeRole returns the role of the node with the given host.
+func (c *SafeguardClient) nodeRole(host string) NodeRole {
+	if leader, err := url.Parse(c.ClusterLeader.getUrl()); err == nil && strings.EqualFold(leader.Host, host) {
+		return NodeLeader
+	}
+	if appliance, err := url.Parse(c.Appliance.getUrl()); err == nil && strings.EqualFold(appliance.Host, host) {
+		return NodeAppliance
+	}
+	return NodeReplica
+}
+
+// routeTemplate replaces the resource Ids in a URL path with "{id}".
+//
+// Parameters:
+//   - path: The URL path, e.g. "/service/core/v4/Assets/12/Accounts".
+//
+// Returns:
+//   - string: The route, e.g. "/service/core/v4/Assets/{id}/Accounts".
+func routeTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isResourceId(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isResourceId reports whether a path segment is a resource Id: a number, an
+// access request Id such as "12-1-1-4711-1", or a GUID.
+func isResourceId(segment string) bool {
+	if _, err := strconv.Atoi(segment); err == nil {
+		return true
+	}
+
+	hasDigit, onlyDigits := false, true
+	for _, r := range segment {
+		switch {
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		case r == '-':
+		case r >= 'a' && r <= 'f', r >= 'A' && r <= 'F':
+			onlyDigits = false
+		default:
+			return false
+		}
+	}
+	return hasDigit && (onlyDigits || len(segment) >= 16)
+}
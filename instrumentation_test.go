@@ -0,0 +1,208 @@
+package safeguard
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type spanKey struct{}
+
+type traceKey struct{}
+
+// recordingInstrumentation records the telemetry it receives.
+type recordingInstrumentation struct {
+	NopInstrumentation
+
+	mu       sync.Mutex
+	calls    []APICall
+	results  []APICallResult
+	logins   []LoginMethod
+	loginErr []error
+	traces   []any // Value of traceKey in the context of each login
+	dropped  []SignalREvent
+}
+
+func (i *recordingInstrumentation) StartAPICall(ctx context.Context, call APICall) (context.Context, func(APICallResult)) {
+	i.mu.Lock()
+	i.calls = append(i.calls, call)
+	i.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, call.Route), func(r APICallResult) {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		i.results = append(i.results, r)
+	}
+}
+
+func (i *recordingInstrumentation) StartLogin(ctx context.Context, method LoginMethod) (context.Context, func(error)) {
+	i.mu.Lock()
+	i.logins = append(i.logins, method)
+	i.traces = append(i.traces, ctx.Value(traceKey{}))
+	i.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, "login"), func(err error) {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		i.loginErr = append(i.loginErr, err)
+	}
+}
+
+func (i *recordingInstrumentation) EventDropped(ctx context.Context, event SignalREvent) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.dropped = append(i.dropped, event)
+}
+
+func TestRouteTemplate(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/service/core/v4/Assets", want: "/service/core/v4/Assets"},
+		{path: "/service/core/v4/Assets/12/Accounts", want: "/service/core/v4/Assets/{id}/Accounts"},
+		{path: "/service/core/v4/AssetAccounts/7/ChangePassword", want: "/service/core/v4/AssetAccounts/{id}/ChangePassword"},
+		{path: "/service/core/v4/AccessRequests/12-1-1-4711-1/CheckIn", want: "/service/core/v4/AccessRequests/{id}/CheckIn"},
+		{path: "/service/core/v4/Cluster/Members/46995a16b0b7482899cc6c60f4a0d86d", want: "/service/core/v4/Cluster/Members/{id}"},
+		{path: "/service/core/v4/Cluster/Members/Self", want: "/service/core/v4/Cluster/Members/Self"},
+		{path: "/service/core/v4/AssetAccounts/BatchCreate", want: "/service/core/v4/AssetAccounts/BatchCreate"},
+		{path: "/service/core/v4/Me/ActionableRequests/Admin", want: "/service/core/v4/Me/ActionableRequests/Admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := routeTemplate(tt.path); got != tt.want {
+				t.Errorf("routeTemplate(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstrumentationAPICall(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"Id":12}`))
+	})
+	client.RetryPolicy = testRetryPolicy()
+
+	instrumentation := &recordingInstrumentation{}
+	client.Instrumentation = instrumentation
+
+	var propagated any
+	client.Use(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			propagated = req.Context().Value(spanKey{})
+			return next.Do(req)
+		})
+	})
+
+	if _, err := client.GetRequest(context.Background(), "Assets/12"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(instrumentation.calls) != 1 || len(instrumentation.results) != 1 {
+		t.Fatalf("expected one API call, got %d calls and %d results", len(instrumentation.calls), len(instrumentation.results))
+	}
+	call, result := instrumentation.calls[0], instrumentation.results[0]
+	if call.Method != http.MethodGet || call.Route != "/service/core/v4/Assets/{id}" || call.Node != NodeLeader {
+		t.Errorf("unexpected call %+v", call)
+	}
+	if result.StatusCode != http.StatusOK || result.Attempts != 2 || result.Err != nil || result.Duration <= 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if propagated != call.Route {
+		t.Errorf("context of StartAPICall was not passed to the request, got %v", propagated)
+	}
+}
+
+func TestInstrumentationAPICallError(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	instrumentation := &recordingInstrumentation{}
+	client.Instrumentation = instrumentation
+
+	if _, err := client.DeleteRequest(context.Background(), "Assets/12"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if result := instrumentation.results[0]; result.StatusCode != http.StatusNotFound || result.Err == nil || result.Attempts != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestInstrumentationLogin(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	instrumentation := &recordingInstrumentation{}
+	client.Instrumentation = instrumentation
+
+	err := client.LoginWithPassword("admin", "wrong")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(instrumentation.logins) != 1 || instrumentation.logins[0] != LoginMethodPassword {
+		t.Fatalf("unexpected logins %v", instrumentation.logins)
+	}
+	if instrumentation.loginErr[0] != err {
+		t.Errorf("login span ended with %v, want %v", instrumentation.loginErr[0], err)
+	}
+}
+
+// contextTransport records the span of the context of every request.
+type contextTransport struct {
+	base  http.RoundTripper
+	mu    sync.Mutex
+	spans []any
+}
+
+func (rt *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.spans = append(rt.spans, req.Context().Value(spanKey{}))
+	rt.mu.Unlock()
+	return rt.base.RoundTrip(req)
+}
+
+func TestInstrumentationLoginContext(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, false)
+	client := newSessionTestClient(t, f)
+	instrumentation := &recordingInstrumentation{}
+	client.Instrumentation = instrumentation
+	transport := &contextTransport{base: client.HttpClient.Transport}
+	client.HttpClient.Transport = transport
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "caller")
+	if err := client.LoginWithPasswordContext(ctx, "alice", "alice-password"); err != nil {
+		t.Fatalf("LoginWithPasswordContext() error = %v", err)
+	}
+
+	if len(instrumentation.traces) != 1 || instrumentation.traces[0] != "caller" {
+		t.Errorf("expected the login span to be started with the caller's context, got %v", instrumentation.traces)
+	}
+	if len(transport.spans) == 0 {
+		t.Fatal("expected login requests")
+	}
+	for _, span := range transport.spans {
+		if span != "login" {
+			t.Errorf("expected the login requests to carry the login span, got %v", transport.spans)
+			break
+		}
+	}
+}
+
+func TestInstrumentationEventDropped(t *testing.T) {
+	instrumentation := &recordingInstrumentation{}
+	client := &SafeguardClient{Instrumentation: instrumentation}
+	handler := NewEventHandler(client)
+	handler.EventChannel = make(chan SignalREvent)
+
+	handler.NotifyEventAsync(map[string]any{"Name": "AccessRequestCreated"})
+
+	if len(instrumentation.dropped) != 1 || instrumentation.dropped[0].Name != "AccessRequestCreated" {
+		t.Errorf("unexpected dropped events %+v", instrumentation.dropped)
+	}
+}
@@ -0,0 +1,225 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrClientClosed is returned for requests sent through a client after Close
+// was called.
+var ErrClientClosed = errors.New("safeguard: client is closed")
+
+// clientLifecycle tracks the background work of a client so that Close can stop it.
+type clientLifecycle struct {
+	sync.Mutex
+
+	closed      bool
+	closing     chan struct{}            // Closed when Close is called
+	stopRefresh context.CancelFunc       // Cancels the background token refresh
+	refreshDone chan struct{}            // Closed when the token refresh goroutine exits
+	inflight    sync.WaitGroup           // API requests currently being sent
+	handlers    map[*EventHandler]func() // Running event handlers and their cancel functions
+	closeOnce   sync.Once
+}
+
+// done returns a channel that is closed once Close is called.
+func (l *clientLifecycle) done() <-chan struct{} {
+	l.Lock()
+	defer l.Unlock()
+	if l.closing == nil {
+		l.closing = make(chan struct{})
+	}
+	return l.closing
+}
+
+// CloseOption configures SafeguardClient.Close.
+type CloseOption func(*closeOptions)
+
+// closeOptions holds the settings of a Close call.
+type closeOptions struct {
+	logout bool
+}
+
+// LogoutOnClose makes Close log out the user token after all in-flight
+// requests have finished, so it cannot be used anymore.
+func LogoutOnClose() CloseOption {
+	return func(o *closeOptions) {
+		o.logout = true
+	}
+}
+
+// Close shuts the client down. It stops the background token refresh, stops all
+// running event handlers, waits for in-flight API requests to finish and closes
+// idle connections. Requests sent after Close fail with ErrClientClosed.
+//
+// Close only waits as long as ctx allows; the client is shut down regardless.
+// Calling Close more than once does nothing.
+//
+// Parameters:
+//   - ctx: Context bounding how long Close waits for background work and in-flight requests.
+//   - opts: Options, e.g. LogoutOnClose.
+//
+// Returns:
+//   - error: ctx.Err() if waiting was cut short, or the error of the logout.
+//
+// Example:
+//
+//	client, err := safeguard.NewClientWithOptions(url)
+//	...
+//	defer func() {
+//	    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	    defer cancel()
+//	    client.Close(ctx, safeguard.LogoutOnClose())
+//	}()
+func (c *SafeguardClient) Close(ctx context.Context, opts ...CloseOption) error {
+	var o closeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var err error
+	c.lifecycle.closeOnce.Do(func() {
+		err = c.close(ctx, o)
+	})
+	return err
+}
+
+// close performs the shutdown of Close.
+func (c *SafeguardClient) close(ctx context.Context, o closeOptions) error {
+	c.lifecycle.Lock()
+	c.lifecycle.closed = true
+	if c.lifecycle.closing == nil {
+		c.lifecycle.closing = make(chan struct{})
+	}
+	close(c.lifecycle.closing)
+	stopRefresh, refreshDone := c.lifecycle.stopRefresh, c.lifecycle.refreshDone
+	handlers := make([]*EventHandler, 0, len(c.lifecycle.handlers))
+	for h, cancel := range c.lifecycle.handlers {
+		handlers = append(handlers, h)
+		cancel()
+	}
+	c.lifecycle.Unlock()
+
+	c.logger().Debug("Closing client", "eventHandlers", len(handlers), "logout", o.logout)
+
+	var errs []error
+	if stopRefresh != nil {
+		stopRefresh()
+		if err := waitFor(ctx, refreshDone); err != nil {
+			errs = append(errs, fmt.Errorf("token refresh did not stop: %w", err))
+		}
+	}
+
+	for _, h := range handlers {
+		if err := waitFor(ctx, h.done); err != nil {
+			errs = append(errs, fmt.Errorf("event handler did not stop: %w", err))
+		}
+	}
+
+	inflight := make(chan struct{})
+	go func() {
+		c.lifecycle.inflight.Wait()
+		close(inflight)
+	}()
+	if err := waitFor(ctx, inflight); err != nil {
+		errs = append(errs, fmt.Errorf("in-flight requests did not finish: %w", err))
+	}
+
+	if o.logout && c.AccessToken != nil && c.AccessToken.getUserToken() != "" {
+		if err := c.Logout(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.HttpClient != nil {
+		c.HttpClient.CloseIdleConnections()
+	}
+	if client := c.certClient.Load(); client != nil {
+		client.CloseIdleConnections()
+	}
+
+	c.logger().Debug("Client closed")
+	return errors.Join(errs...)
+}
+
+// waitFor blocks until done is closed or ctx ends.
+//
+// Returns:
+//   - error: nil if done was closed, otherwise the context error.
+func waitFor(ctx context.Context, done <-chan struct{}) error {
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// beginRequest registers an in-flight API request. Every successful call must
+// be followed by a call to endRequest.
+//
+// Returns:
+//   - error: ErrClientClosed if the client was closed.
+func (c *SafeguardClient) beginRequest() error {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	if c.lifecycle.closed {
+		return ErrClientClosed
+	}
+	c.lifecycle.inflight.Add(1)
+	return nil
+}
+
+// endRequest marks an in-flight API request registered by beginRequest as finished.
+func (c *SafeguardClient) endRequest() {
+	c.lifecycle.inflight.Done()
+}
+
+// startBackgroundRefresh starts the background token refresh goroutine.
+func (c *SafeguardClient) startBackgroundRefresh() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	c.lifecycle.Lock()
+	c.lifecycle.stopRefresh = cancel
+	c.lifecycle.refreshDone = done
+	c.lifecycle.Unlock()
+
+	go func() {
+		defer close(done)
+		c.refreshToken(ctx)
+	}()
+}
+
+// registerEventHandler records a running event handler so that Close can stop it.
+//
+// Parameters:
+//   - h: The event handler.
+//   - cancel: Cancels the context the handler runs with.
+//
+// Returns:
+//   - error: ErrClientClosed if the client was closed.
+func (c *SafeguardClient) registerEventHandler(h *EventHandler, cancel func()) error {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	if c.lifecycle.closed {
+		return ErrClientClosed
+	}
+	if c.lifecycle.handlers == nil {
+		c.lifecycle.handlers = make(map[*EventHandler]func())
+	}
+	c.lifecycle.handlers[h] = cancel
+	return nil
+}
+
+// unregisterEventHandler removes an event handler that stopped running.
+func (c *SafeguardClient) unregisterEventHandler(h *EventHandler) {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	delete(c.lifecycle.handlers, h)
+}
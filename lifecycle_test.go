@@ -0,0 +1,129 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCloseStopsBackgroundRefresh(t *testing.T) {
+	client, err := NewClientWithOptions("https://appliance.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	select {
+	case <-client.lifecycle.refreshDone:
+	default:
+		t.Error("expected the refresh goroutine to have stopped")
+	}
+
+	// Must not block without a refresh goroutine
+	client.signalAuthDone()
+
+	if err := client.Close(ctx); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+func TestRequestsAfterCloseFail(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	})
+
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, err := client.GetRequest(context.Background(), "Me"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+	if err := NewEventHandler(client).Run(context.Background()); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed from Run, got %v", err)
+	}
+}
+
+func TestCloseWaitsForInflightRequests(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-release
+		w.Write([]byte(`{}`))
+	})
+
+	requestErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetRequest(context.Background(), "Me")
+		requestErr <- err
+	}()
+	<-received
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- client.Close(context.Background())
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("Close returned while a request was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-requestErr; err != nil {
+		t.Errorf("in-flight request failed: %v", err)
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestCloseHonoursContext(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-release
+	})
+
+	go client.GetRequest(context.Background(), "Me")
+	<-received
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestCloseWithLogout(t *testing.T) {
+	var authorization string
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/service/core/v4/Token/Logout" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := client.Close(context.Background(), LogoutOnClose()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if authorization != "Bearer test-user-token" {
+		t.Errorf("unexpected Authorization header %q", authorization)
+	}
+	if token := client.AccessToken.getUserToken(); token != "" {
+		t.Errorf("expected the user token to be removed, got %q", token)
+	}
+}
@@ -1,6 +1,7 @@
 package safeguard
 
 import (
+	"context"
 	"encoding/json"
 	"strconv"
 	"time"
@@ -170,10 +171,10 @@ const (
 // Returns:
 //   - User: The user information for the authenticated user
 //   - error: An error if the request fails or the response cannot be parsed
-func (c *SafeguardClient) GetMe(filter Filter) (User, error) {
+func (c *SafeguardClient) GetMe(ctx context.Context, filter Filter) (User, error) {
 	query := "me" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return User{}, err
 	}
@@ -189,17 +190,18 @@ func (c *SafeguardClient) GetMe(filter Filter) (User, error) {
 // GetMeAccessRequestAssets retrieves all assets that the current user can request access to.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Filter criteria to narrow down the results
 //
 // Returns:
 //   - []PolicyAsset: A slice of assets the user can request access to
 //   - error: An error if the request fails or the response cannot be parsed
-func (c *SafeguardClient) GetMeAccessRequestAssets(filter Filter) ([]PolicyAsset, error) {
+func (c *SafeguardClient) GetMeAccessRequestAssets(ctx context.Context, filter Filter) ([]PolicyAsset, error) {
 	var assets []PolicyAsset
 
 	query := "me/AccessRequestAssets" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return []PolicyAsset{}, err
 	}
@@ -214,15 +216,16 @@ func (c *SafeguardClient) GetMeAccessRequestAssets(filter Filter) ([]PolicyAsset
 // GetMeAccessRequestAsset retrieves a specific asset that the current user can request access to.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - assetId: The ID of the asset to retrieve information for
 //
 // Returns:
 //   - PolicyAsset: The requested asset's information
 //   - error: An error if the asset cannot be found or the request fails
-func (c *SafeguardClient) GetMeAccessRequestAsset(assetId string) (PolicyAsset, error) {
+func (c *SafeguardClient) GetMeAccessRequestAsset(ctx context.Context, assetId string) (PolicyAsset, error) {
 	query := "me/AccessRequestAssets/" + assetId
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return PolicyAsset{}, err
 	}
@@ -238,15 +241,16 @@ func (c *SafeguardClient) GetMeAccessRequestAsset(assetId string) (PolicyAsset,
 // GetMeActionableRequests retrieves access requests that require action from the current user.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Filter criteria to narrow down the results
 //
 // Returns:
 //   - map[AccessRequestRole][]AccessRequest: Access requests grouped by role
 //   - error: An error if the request fails or the response cannot be parsed
-func (c *SafeguardClient) GetMeActionableRequests(filter Filter) (map[AccessRequestRole][]AccessRequest, error) {
+func (c *SafeguardClient) GetMeActionableRequests(ctx context.Context, filter Filter) (map[AccessRequestRole][]AccessRequest, error) {
 	query := "me/ActionableRequests" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -266,16 +270,17 @@ func (c *SafeguardClient) GetMeActionableRequests(filter Filter) (map[AccessRequ
 // GetMeActionableRequestsByRole retrieves access requests for a specific role that require action.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - role: The specific role to filter requests by
 //   - filter: Additional filter criteria to narrow down the results
 //
 // Returns:
 //   - []AccessRequest: Access requests for the specified role
 //   - error: An error if the request fails or the response cannot be parsed
-func (c *SafeguardClient) GetMeActionableRequestsByRole(role AccessRequestRole, filter Filter) ([]AccessRequest, error) {
+func (c *SafeguardClient) GetMeActionableRequestsByRole(ctx context.Context, role AccessRequestRole, filter Filter) ([]AccessRequest, error) {
 	query := "me/ActionableRequests/" + string(role) + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -309,13 +314,14 @@ func (a ActionableRequestsResult) SetClient(c *SafeguardClient) any {
 // and provides additional helper information.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Filter criteria to narrow down the results
 //
 // Returns:
 //   - ActionableRequestsResult: Processed access requests with additional metadata
 //   - error: An error if the request fails or the response cannot be parsed
-func (c *SafeguardClient) GetMeActionableRequestsDetailed(filter Filter) (ActionableRequestsResult, error) {
-	requests, err := c.GetMeActionableRequests(filter)
+func (c *SafeguardClient) GetMeActionableRequestsDetailed(ctx context.Context, filter Filter) (ActionableRequestsResult, error) {
+	requests, err := c.GetMeActionableRequests(ctx, filter)
 	if err != nil {
 		return ActionableRequestsResult{}, err
 	}
@@ -400,6 +406,7 @@ func (r *ActionableRequestsResult) GetRequestsForRole(role AccessRequestRole) []
 // GetMeAccountEntitlements retrieves the account entitlements for the current user.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - accessRequestType: Optional type of access request to filter by
 //   - includeActiveRequests: If true, includes currently active requests in the response
 //   - filterByCredential: If true, filters results by credential type
@@ -408,7 +415,7 @@ func (r *ActionableRequestsResult) GetRequestsForRole(role AccessRequestRole) []
 // Returns:
 //   - []AccountEntitlement: A slice of account entitlements for the user
 //   - error: An error if the request fails or the response cannot be parsed
-func (c *SafeguardClient) GetMeAccountEntitlements(accessRequestType AccessRequestType, includeActiveRequests bool, filterByCredential bool, filter Filter) ([]AccountEntitlement, error) {
+func (c *SafeguardClient) GetMeAccountEntitlements(ctx context.Context, accessRequestType AccessRequestType, includeActiveRequests bool, filterByCredential bool, filter Filter) ([]AccountEntitlement, error) {
 	var entitlements []AccountEntitlement
 
 	query := "me/AccountEntitlements" + filter.ToQueryString() +
@@ -419,7 +426,7 @@ func (c *SafeguardClient) GetMeAccountEntitlements(accessRequestType AccessReque
 		query += "&accessRequestType=" + string(accessRequestType)
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return []AccountEntitlement{}, err
 	}
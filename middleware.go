@@ -0,0 +1,189 @@
+package safeguard
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader is the header used by RequestIDMiddleware to send the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// Doer sends a single HTTP request and returns its response.
+// *http.Client implements Doer.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts an ordinary function to the Doer interface.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a Doer to inspect or modify API requests and responses.
+// A middleware sees the fully built request including authorization and
+// default headers, and may short-circuit by returning a response or error
+// without calling next. Each retry attempt passes through the chain again.
+//
+// Example:
+//
+//	client.Use(func(next safeguard.Doer) safeguard.Doer {
+//		return safeguard.DoerFunc(func(req *http.Request) (*http.Response, error) {
+//			req.Header.Set("Proxy-Authorization", "Bearer "+proxyToken)
+//			return next.Do(req)
+//		})
+//	})
+type Middleware func(next Doer) Doer
+
+// Use registers middlewares that wrap every API request sent by the client.
+// Middlewares run in the order they were registered, so the first one is the
+// outermost. Use must not be called concurrently with requests.
+//
+// Parameters:
+//   - middlewares: The middlewares to append to the chain.
+func (c *SafeguardClient) Use(middlewares ...Middleware) {
+	c.Middlewares = append(c.Middlewares, middlewares...)
+}
+
+// doer returns the HTTP client wrapped in the registered middlewares.
+func (c *SafeguardClient) doer() Doer {
+	var d Doer = c.httpClient()
+	for i := len(c.Middlewares) - 1; i >= 0; i-- {
+		d = c.Middlewares[i](d)
+	}
+	return d
+}
+
+// LoggingMiddleware logs every request and response at the given level.
+// Headers and response bodies are logged through SafeHeaders and
+// SafeResponseBody, so tokens and passwords are masked. Only the bodies of
+// error responses are read and logged; successful responses are passed on
+// untouched, so Stream and the paging iterators still decode them while they
+// are received.
+//
+// Parameters:
+//   - logger: The logger to write to.
+//   - level: The level used for successful requests; failures are logged at error level.
+//
+// Returns:
+//   - Middleware: The logging middleware.
+func LoggingMiddleware(logger *slog.Logger, level slog.Level) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			start := time.Now()
+
+			logger.Log(ctx, level, "API request",
+				"method", req.Method,
+				"url", req.URL.String(),
+				"headers", NewSafeHeaders(req.Header),
+			)
+
+			resp, err := next.Do(req)
+			if err != nil {
+				logger.Error("API request failed",
+					"method", req.Method,
+					"url", req.URL.String(),
+					"duration", time.Since(start),
+					"error", err,
+				)
+				return nil, err
+			}
+
+			if resp.StatusCode < http.StatusBadRequest {
+				// Leave the body to the caller, it may be streamed
+				logger.Log(ctx, level, "API response",
+					"method", req.Method,
+					"url", req.URL.String(),
+					"statusCode", resp.StatusCode,
+					"duration", time.Since(start),
+					"headers", NewSafeHeaders(resp.Header),
+				)
+				return resp, nil
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+
+			logger.Log(ctx, slog.LevelError, "API response",
+				"method", req.Method,
+				"url", req.URL.String(),
+				"statusCode", resp.StatusCode,
+				"duration", time.Since(start),
+				"headers", NewSafeHeaders(resp.Header),
+				"body", NewSafeResponseBody(body, req.URL.Path),
+			)
+
+			return resp, nil
+		})
+	}
+}
+
+// requestIDKey is the context key for request IDs set with WithRequestID.
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the given request ID. Requests made
+// with this context send the ID in the RequestIDHeader when RequestIDMiddleware
+// is registered, which allows correlating a whole workflow across calls.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - id: The request ID to send.
+//
+// Returns:
+//   - context.Context: A context carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set with WithRequestID.
+//
+// Parameters:
+//   - ctx: The context to look up the request ID in.
+//
+// Returns:
+//   - string: The request ID, or an empty string if none is set.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDMiddleware adds a request ID to every request that does not already
+// carry one. The ID is taken from the request context (see WithRequestID) or
+// generated randomly.
+//
+// Returns:
+//   - Middleware: The request ID middleware.
+func RequestIDMiddleware() Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(RequestIDHeader) == "" {
+				id := RequestIDFromContext(req.Context())
+				if id == "" {
+					id = newRequestID()
+				}
+				req.Header.Set(RequestIDHeader, id)
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// newRequestID generates a random 128 bit request ID in hex encoding.
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
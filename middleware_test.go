@@ -0,0 +1,195 @@
+package safeguard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMiddlewareOrderAndRequestVisibility(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("X-Trace")))
+	})
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+":before")
+				if req.Header.Get("Authorization") == "" {
+					t.Errorf("%s: expected authorization header to be set", name)
+				}
+				req.Header.Set("X-Trace", req.Header.Get("X-Trace")+name)
+				resp, err := next.Do(req)
+				order = append(order, name+":after")
+				return resp, err
+			})
+		}
+	}
+	client.Use(trace("a"), trace("b"))
+
+	body, err := client.GetRequest(context.Background(), "Assets")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(body) != "ab" {
+		t.Errorf("expected header built by both middlewares, got %q", body)
+	}
+	want := []string{"a:before", "b:before", "b:after", "a:after"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected middleware order: %v", order)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	var serverCalls atomic.Int32
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		serverCalls.Add(1)
+	})
+
+	faultErr := errors.New("injected fault")
+	client.Use(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodDelete {
+				return nil, faultErr
+			}
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"Code":1,"Message":"maintenance"}`)),
+				Request:    req,
+			}, nil
+		})
+	})
+
+	if _, err := client.GetRequest(context.Background(), "Assets"); !hasStatus(err, http.StatusServiceUnavailable) {
+		t.Errorf("expected injected 503, got %v", err)
+	}
+	if _, err := client.DeleteRequest(context.Background(), "Assets/1"); !errors.Is(err, faultErr) {
+		t.Errorf("expected injected error, got %v", err)
+	}
+	if got := serverCalls.Load(); got != 0 {
+		t.Errorf("expected no requests to reach the server, got %d", got)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var received []string
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(RequestIDHeader))
+	})
+	client.Use(RequestIDMiddleware())
+
+	if _, err := client.GetRequest(context.Background(), "Assets"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := WithRequestID(context.Background(), "workflow-42")
+	if _, err := client.GetRequest(ctx, "Assets"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(received[0]) != 32 {
+		t.Errorf("expected generated 32 character request ID, got %q", received[0])
+	}
+	if received[1] != "workflow-42" {
+		t.Errorf("expected request ID from context, got %q", received[1])
+	}
+}
+
+func TestLoggingMiddlewareMasksSecrets(t *testing.T) {
+	const password = "SuperSecretPassword123"
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"` + password + `"`))
+	})
+
+	var buf bytes.Buffer
+	client.Use(LoggingMiddleware(slog.New(slog.NewTextHandler(&buf, nil)), slog.LevelInfo))
+
+	body, err := client.PostRequest(context.Background(), "AccessRequests/1/CheckOutPassword", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(body) != `"`+password+`"` {
+		t.Errorf("middleware must not consume the response body, got %q", body)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "API request") || !strings.Contains(out, "API response") {
+		t.Errorf("expected request and response to be logged, got %q", out)
+	}
+	if strings.Contains(out, password) || strings.Contains(out, "test-user-token") {
+		t.Errorf("log output contains secrets: %q", out)
+	}
+}
+
+// markedBody lets a test tell whether a middleware replaced the response body.
+type markedBody struct {
+	io.ReadCloser
+}
+
+func TestLoggingMiddlewareBuffersOnlyErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantBuffer bool
+	}{
+		{name: "success", status: http.StatusOK},
+		{name: "error", status: http.StatusBadRequest, wantBuffer: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"Code":60000,"Message":"response body"}`))
+			})
+
+			var buf bytes.Buffer
+			var replaced bool
+			client.Use(
+				func(next Doer) Doer {
+					return DoerFunc(func(req *http.Request) (*http.Response, error) {
+						resp, err := next.Do(req)
+						if err == nil {
+							_, marked := resp.Body.(markedBody)
+							replaced = !marked
+						}
+						return resp, err
+					})
+				},
+				LoggingMiddleware(slog.New(slog.NewTextHandler(&buf, nil)), slog.LevelInfo),
+				func(next Doer) Doer {
+					return DoerFunc(func(req *http.Request) (*http.Response, error) {
+						resp, err := next.Do(req)
+						if err == nil {
+							resp.Body = markedBody{resp.Body}
+						}
+						return resp, err
+					})
+				},
+			)
+
+			_, err := client.GetRequest(context.Background(), "Assets")
+			if (err != nil) != tt.wantBuffer {
+				t.Fatalf("GetRequest() error = %v", err)
+			}
+			if replaced != tt.wantBuffer {
+				t.Errorf("expected body to be buffered %v, got %v", tt.wantBuffer, replaced)
+			}
+			if logged := strings.Contains(buf.String(), "response body"); logged != tt.wantBuffer {
+				t.Errorf("expected body to be logged %v, got %q", tt.wantBuffer, buf.String())
+			}
+			var apiErr *APIError
+			if tt.wantBuffer && (!errors.As(err, &apiErr) || apiErr.Message != "response body") {
+				t.Errorf("expected the error body to reach the caller, got %v", err)
+			}
+		})
+	}
+}
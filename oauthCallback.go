@@ -0,0 +1,202 @@
+package safeguard
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultOAuthTimeout bounds LoginWithOauth, which takes no context, so it does
+// not wait forever if the user closes the browser.
+const defaultOAuthTimeout = 5 * time.Minute
+
+// OAuthError is an error returned by RSTS to the OAuth callback, e.g. because the
+// user denied consent. Use errors.As to inspect it:
+//
+//	var oauthErr *safeguard.OAuthError
+//	if errors.As(err, &oauthErr) && oauthErr.Denied() {
+//	    fmt.Println("login cancelled by the user")
+//	}
+type OAuthError struct {
+	Code        string // OAuth error code, e.g. "access_denied"
+	Description string // Human readable description, if any
+}
+
+// Error implements the error interface.
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("OAuth authorization failed: %s: %s", e.Code, e.Description)
+	}
+	return fmt.Sprintf("OAuth authorization failed: %s", e.Code)
+}
+
+// Denied reports whether the user denied the authorization.
+func (e *OAuthError) Denied() bool {
+	return e.Code == "access_denied"
+}
+
+// OAuthOption configures LoginWithOauthContext.
+type OAuthOption func(*oauthOptions)
+
+// oauthOptions holds the settings of an OAuth login.
+type oauthOptions struct {
+	address     string                     // Address of the callback listener
+	openBrowser func(authURL string) error // Shows the authorization URL to the user
+}
+
+// WithOAuthCallbackAddress sets the address the OAuth callback listener binds to, e.g.
+// "127.0.0.1:9000". Port 0 picks a free port. The default is localhost:8400.
+//
+// Parameters:
+//   - address: The host and port to listen on.
+func WithOAuthCallbackAddress(address string) OAuthOption {
+	return func(o *oauthOptions) {
+		o.address = address
+	}
+}
+
+// WithOAuthBrowser replaces opening the authorization URL in the default browser,
+// e.g. to print it or to open a specific browser.
+//
+// Parameters:
+//   - open: Shows the authorization URL to the user.
+func WithOAuthBrowser(open func(authURL string) error) OAuthOption {
+	return func(o *oauthOptions) {
+		o.openBrowser = open
+	}
+}
+
+// oauthCallback is the result of the OAuth callback.
+type oauthCallback struct {
+	code string
+	err  error
+}
+
+// callbackListener receives the OAuth redirect from RSTS with an HTTP server on
+// a local port.
+type callbackListener struct {
+	listener net.Listener
+	server   *http.Server
+	state    string
+	result   chan oauthCallback
+}
+
+// newCallbackListener starts the callback listener.
+//
+// Parameters:
+//   - address: The host and port to listen on; port 0 picks a free port.
+//
+// Returns:
+//   - *callbackListener: The running listener; it must be closed.
+//   - error: An error if the address cannot be bound.
+func (c *SafeguardClient) newCallbackListener(address string) (*callbackListener, error) {
+	state := make([]byte, 16)
+	if _, err := rand.Read(state); err != nil {
+		return nil, fmt.Errorf("failed to generate OAuth state: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start OAuth callback listener on %s: %w", address, err)
+	}
+
+	l := &callbackListener{
+		listener: listener,
+		state:    base64.RawURLEncoding.EncodeToString(state),
+		result:   make(chan oauthCallback, 1),
+	}
+	l.server = &http.Server{
+		Handler:           http.HandlerFunc(l.handle),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go l.server.Serve(listener)
+
+	c.logger().Debug("OAuth callback listener started", "address", listener.Addr())
+	return l, nil
+}
+
+// port returns the port the listener is bound to.
+func (l *callbackListener) port() int {
+	_, port, _ := net.SplitHostPort(l.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+// handle serves the OAuth redirect. The first callback with the state of the
+// login ends it. Callbacks with another state, e.g. forged by a web page the
+// user has open, are answered with 400 and do not affect the login.
+func (l *callbackListener) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	params := r.URL.Query()
+	if subtle.ConstantTimeCompare([]byte(params.Get("state")), []byte(l.state)) != 1 {
+		writeCallbackPage(w, http.StatusBadRequest, "Authentication failed", "The request does not belong to this login.")
+		return
+	}
+
+	if code := params.Get("error"); code != "" {
+		err := &OAuthError{Code: code, Description: params.Get("error_description")}
+		writeCallbackPage(w, http.StatusOK, "Authentication cancelled", err.Error())
+		l.deliver(oauthCallback{err: err})
+		return
+	}
+
+	code := params.Get("oauth")
+	if code == "" {
+		code = params.Get("code")
+	}
+	if code == "" {
+		writeCallbackPage(w, http.StatusBadRequest, "Authentication failed", "No authorization code was received.")
+		l.deliver(oauthCallback{err: errors.New("no authorization code received in OAuth callback")})
+		return
+	}
+
+	writeCallbackPage(w, http.StatusOK, "Authentication successful", "You can close this window and return to the application.")
+	l.deliver(oauthCallback{code: code})
+}
+
+// deliver passes the first result to the waiting login and drops later ones.
+func (l *callbackListener) deliver(result oauthCallback) {
+	select {
+	case l.result <- result:
+	default:
+	}
+}
+
+// wait returns the authorization code of the callback, or an error if the
+// callback failed or ctx ended first.
+func (l *callbackListener) wait(ctx context.Context) (string, error) {
+	select {
+	case result := <-l.result:
+		return result.code, result.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("no OAuth callback received: %w", ctx.Err())
+	}
+}
+
+// Close stops the listener.
+func (l *callbackListener) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return l.server.Shutdown(ctx)
+}
+
+// writeCallbackPage writes the page shown in the browser after the redirect.
+func writeCallbackPage(w http.ResponseWriter, status int, title, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<!DOCTYPE html><html><head><title>%[1]s</title></head><body><h1>%[1]s</h1><p>%[2]s</p></body></html>",
+		html.EscapeString(title), html.EscapeString(message))
+}
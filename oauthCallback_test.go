@@ -0,0 +1,206 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// fakeBrowser returns an OAuth browser that follows the redirect to the callback
+// listener with the given query, built from the state of the login.
+func fakeBrowser(t *testing.T, query func(state string) url.Values) func(string) error {
+	t.Helper()
+
+	return func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		params := u.Query()
+		if params.Get("state") == "" || params.Get("code_challenge") == "" {
+			t.Errorf("expected state and code challenge in %s", authURL)
+		}
+
+		callback := fmt.Sprintf("http://127.0.0.1:%s/?%s", params.Get("port"), query(params.Get("state")).Encode())
+		go func() {
+			// A browser asks for the icon first, which must not end the login
+			if resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%s/favicon.ico", params.Get("port"))); err == nil {
+				resp.Body.Close()
+			}
+			resp, err := http.Get(callback)
+			if err != nil {
+				t.Errorf("callback failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+			if resp.Header.Get("Content-Type") != "text/html; charset=utf-8" {
+				t.Errorf("unexpected callback page type %q", resp.Header.Get("Content-Type"))
+			}
+		}()
+		return nil
+	}
+}
+
+func TestLoginWithOauthContext(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.LoginWithOauthContext(ctx,
+		WithOAuthCallbackAddress("127.0.0.1:0"),
+		WithOAuthBrowser(fakeBrowser(t, func(state string) url.Values {
+			return url.Values{"oauth": {"code-alice"}, "state": {state}}
+		})),
+	)
+	if err != nil {
+		t.Fatalf("LoginWithOauthContext() error = %v", err)
+	}
+	if name, err := me(ctx, client); err != nil || name != "alice" {
+		t.Errorf("expected alice, got %q (%v)", name, err)
+	}
+	if f.redirectURI != "urn:InstalledApplicationTcpListener" {
+		t.Errorf("unexpected redirect URI %q", f.redirectURI)
+	}
+}
+
+func TestLoginWithOauthContextCallbackErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query func(state string) url.Values
+		check func(error) bool
+	}{
+		{
+			name: "denied consent",
+			query: func(state string) url.Values {
+				return url.Values{"error": {"access_denied"}, "error_description": {"User cancelled"}, "state": {state}}
+			},
+			check: func(err error) bool {
+				var oauthErr *OAuthError
+				return errors.As(err, &oauthErr) && oauthErr.Denied() && oauthErr.Description == "User cancelled"
+			},
+		},
+		{
+			name: "no code",
+			query: func(state string) url.Values {
+				return url.Values{"state": {state}}
+			},
+			check: func(err error) bool { return err != nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeAuthServer(t, 3600, true)
+			client := newSessionTestClient(t, f)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err := client.LoginWithOauthContext(ctx,
+				WithOAuthCallbackAddress("127.0.0.1:0"),
+				WithOAuthBrowser(fakeBrowser(t, tt.query)),
+			)
+			if !tt.check(err) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if got := f.loginCount(); got != 0 {
+				t.Errorf("expected no login, got %d", got)
+			}
+		})
+	}
+}
+
+func TestLoginWithOauthContextIgnoresForgedCallbacks(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	browser := func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		params := u.Query()
+		callback := fmt.Sprintf("http://127.0.0.1:%s/?", params.Get("port"))
+
+		go func() {
+			// Another web page sends forged callbacks before the real redirect
+			for _, forged := range []url.Values{
+				{"oauth": {"code-mallory"}, "state": {"forged"}},
+				{"oauth": {"code-mallory"}},
+				{"error": {"access_denied"}, "state": {"forged"}},
+			} {
+				resp, err := http.Get(callback + forged.Encode())
+				if err != nil {
+					t.Errorf("forged callback failed: %v", err)
+					return
+				}
+				resp.Body.Close()
+				if resp.StatusCode != http.StatusBadRequest {
+					t.Errorf("expected 400 for forged callback %v, got %d", forged, resp.StatusCode)
+				}
+			}
+
+			valid := url.Values{"oauth": {"code-alice"}, "state": {params.Get("state")}}
+			resp, err := http.Get(callback + valid.Encode())
+			if err != nil {
+				t.Errorf("callback failed: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+		return nil
+	}
+
+	err := client.LoginWithOauthContext(ctx, WithOAuthCallbackAddress("127.0.0.1:0"), WithOAuthBrowser(browser))
+	if err != nil {
+		t.Fatalf("LoginWithOauthContext() error = %v", err)
+	}
+	if name, err := me(ctx, client); err != nil || name != "alice" {
+		t.Errorf("expected alice, got %q (%v)", name, err)
+	}
+	if got := f.loginCount(); got != 1 {
+		t.Errorf("expected a single login, got %d", got)
+	}
+}
+
+func TestLoginWithOauthContextTimeout(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := client.LoginWithOauthContext(ctx,
+		WithOAuthCallbackAddress("127.0.0.1:0"),
+		WithOAuthBrowser(func(string) error { return nil }),
+	)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestLoginWithOauthContextAddressInUse(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer busy.Close()
+
+	client := newSessionTestClient(t, newFakeAuthServer(t, 3600, true))
+	err = client.LoginWithOauthContext(context.Background(),
+		WithOAuthCallbackAddress(busy.Addr().String()),
+		WithOAuthBrowser(func(string) error {
+			t.Error("the browser must not be opened without a listener")
+			return nil
+		}),
+	)
+	if err == nil {
+		t.Error("expected an error for an address in use")
+	}
+}
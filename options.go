@@ -0,0 +1,469 @@
+package safeguard
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// ClientOption configures a SafeguardClient created with NewClientWithOptions.
+type ClientOption func(*clientOptions) error
+
+// clientOptions collects the settings applied by ClientOption functions.
+type clientOptions struct {
+	apiVersion string
+
+	httpClient *http.Client
+	transport  http.RoundTripper
+
+	rootCAs         *x509.CertPool
+	caBundles       []string
+	systemRoots     bool
+	cwdCertificates bool
+
+	proxy               func(*http.Request) (*url.URL, error)
+	timeout             time.Duration
+	tlsHandshakeTimeout time.Duration
+
+	logger          *slog.Logger
+	disableRefresh  bool
+	replay          bool
+	tokenSource     TokenSource
+	tokenCache      *clientTokenCache
+	middlewares     []Middleware
+	readLimit       RateLimit
+	writeLimit      RateLimit
+	failover        *FailoverPolicy
+	routing         RoutingPolicy
+	instrumentation Instrumentation
+}
+
+// defaultClientOptions returns the settings used when no options are given.
+func defaultClientOptions() *clientOptions {
+	return &clientOptions{
+		apiVersion: "v4",
+	}
+}
+
+// WithAPIVersion sets the Safeguard API version used for requests. Defaults to "v4".
+//
+// Parameters:
+//   - version: The API version, e.g. "v4".
+func WithAPIVersion(version string) ClientOption {
+	return func(o *clientOptions) error {
+		if version == "" {
+			return errors.New("API version must not be empty")
+		}
+		o.apiVersion = version
+		return nil
+	}
+}
+
+// WithCABundle trusts the PEM encoded certificates in the given file when
+// verifying the appliance certificate. It can be used multiple times.
+// Unless WithSystemRoots is also used, only the given certificates are trusted.
+//
+// Parameters:
+//   - path: Path to a PEM file containing one or more CA certificates.
+func WithCABundle(path string) ClientOption {
+	return func(o *clientOptions) error {
+		o.caBundles = append(o.caBundles, path)
+		return nil
+	}
+}
+
+// WithCertPool trusts the certificates in the given pool when verifying the
+// appliance certificate. The pool is cloned, so later changes to it have no effect.
+//
+// Parameters:
+//   - pool: The certificate pool to trust.
+func WithCertPool(pool *x509.CertPool) ClientOption {
+	return func(o *clientOptions) error {
+		if pool == nil {
+			return errors.New("certificate pool must not be nil")
+		}
+		o.rootCAs = pool.Clone()
+		return nil
+	}
+}
+
+// WithSystemRoots trusts the operating system's root certificates in addition
+// to any certificates added with WithCABundle. Without any trust option the
+// system roots are used by default.
+func WithSystemRoots() ClientOption {
+	return func(o *clientOptions) error {
+		o.systemRoots = true
+		return nil
+	}
+}
+
+// WithCurrentDirectoryCertificates trusts every certificate file (.crt, .cer,
+// .pem, ...) found in the current working directory. No client scans the
+// working directory unless this option is given, and it should only be used
+// when the working directory is controlled.
+func WithCurrentDirectoryCertificates() ClientOption {
+	return func(o *clientOptions) error {
+		o.cwdCertificates = true
+		return nil
+	}
+}
+
+// WithHTTPClient uses the given HTTP client for all requests. The client is
+// copied, so the caller's instance is not modified; certificate logins use a
+// copy with a cloned transport that presents the client certificate. Trust and proxy options
+// cannot be combined with a custom client because they configure the transport.
+//
+// Parameters:
+//   - client: The HTTP client to use.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) error {
+		if client == nil {
+			return errors.New("HTTP client must not be nil")
+		}
+		o.httpClient = client
+		return nil
+	}
+}
+
+// WithTransport uses the given RoundTripper for all requests. Trust and proxy
+// options cannot be combined with a custom transport.
+//
+// Parameters:
+//   - transport: The RoundTripper to use.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(o *clientOptions) error {
+		if transport == nil {
+			return errors.New("transport must not be nil")
+		}
+		o.transport = transport
+		return nil
+	}
+}
+
+// WithProxy sends all requests through the given proxy. By default the proxy
+// is taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+//
+// Parameters:
+//   - proxyURL: The proxy URL, e.g. "http://proxy.example.com:3128".
+func WithProxy(proxyURL string) ClientOption {
+	return func(o *clientOptions) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		o.proxy = http.ProxyURL(u)
+		return nil
+	}
+}
+
+// WithTimeout limits the total duration of each HTTP request, including
+// reading the response body. Per-call deadlines can still be set via context.
+//
+// Parameters:
+//   - timeout: The request timeout. Zero means no timeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) error {
+		o.timeout = timeout
+		return nil
+	}
+}
+
+// WithTLSHandshakeTimeout limits the time spent on the TLS handshake.
+//
+// Parameters:
+//   - timeout: The handshake timeout. Zero means no timeout.
+func WithTLSHandshakeTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) error {
+		o.tlsHandshakeTimeout = timeout
+		return nil
+	}
+}
+
+// WithLogger sets the logger used by this client. The package logger is not modified.
+//
+// Parameters:
+//   - l: The logger to use.
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(o *clientOptions) error {
+		if l == nil {
+			return errors.New("logger must not be nil")
+		}
+		o.logger = l
+		return nil
+	}
+}
+
+// WithMiddleware registers middlewares that wrap every API request, see SafeguardClient.Use.
+//
+// Parameters:
+//   - middlewares: The middlewares to register, outermost first.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(o *clientOptions) error {
+		o.middlewares = append(o.middlewares, middlewares...)
+		return nil
+	}
+}
+
+// WithReadRateLimit limits requests sent to the appliance URL, see SafeguardClient.SetReadLimit.
+//
+// Parameters:
+//   - l: The limits for read requests.
+func WithReadRateLimit(l RateLimit) ClientOption {
+	return func(o *clientOptions) error {
+		o.readLimit = l
+		return nil
+	}
+}
+
+// WithWriteRateLimit limits requests sent to the cluster leader URL, see SafeguardClient.SetWriteLimit.
+//
+// Parameters:
+//   - l: The limits for write requests.
+func WithWriteRateLimit(l RateLimit) ClientOption {
+	return func(o *clientOptions) error {
+		o.writeLimit = l
+		return nil
+	}
+}
+
+// WithClusterFailover enables failover of reads across cluster members, see
+// SafeguardClient.EnableFailover.
+//
+// Parameters:
+//   - policy: The failover settings, e.g. DefaultFailoverPolicy().
+func WithClusterFailover(policy FailoverPolicy) ClientOption {
+	return func(o *clientOptions) error {
+		o.failover = &policy
+		return nil
+	}
+}
+
+// WithRouting sets which node serves reads and writes, see RoutingPolicy.
+//
+// Parameters:
+//   - policy: The routing policy for API requests.
+func WithRouting(policy RoutingPolicy) ClientOption {
+	return func(o *clientOptions) error {
+		o.routing = policy
+		return nil
+	}
+}
+
+// WithInstrumentation reports API calls, logins, token refreshes and SignalR
+// events to an Instrumentation, e.g. an OpenTelemetry adapter.
+//
+// Parameters:
+//   - instrumentation: The receiver of the telemetry.
+func WithInstrumentation(instrumentation Instrumentation) ClientOption {
+	return func(o *clientOptions) error {
+		o.instrumentation = instrumentation
+		return nil
+	}
+}
+
+// WithoutBackgroundRefresh disables the goroutine that renews the access token
+// before it expires. Callers are then responsible for logging in again.
+func WithoutBackgroundRefresh() ClientOption {
+	return func(o *clientOptions) error {
+		o.disableRefresh = true
+		return nil
+	}
+}
+
+// WithCredentialReplay keeps the password, certificate password or in-memory
+// certificate of the last login and logs in with it again when the token cannot
+// be renewed with the RSTS refresh token, e.g. because the appliance issued none.
+// Without this option credentials are never kept after a login.
+func WithCredentialReplay() ClientOption {
+	return func(o *clientOptions) error {
+		o.replay = true
+		return nil
+	}
+}
+
+// WithTokenSource makes the client log in with source before its first request,
+// so no Login method has to be called. The token is renewed with the RSTS
+// refresh token or by asking source again, see LoginWithTokenSource.
+//
+// Parameters:
+//   - source: The token source, e.g. PasswordTokenSource or StaticTokenSource.
+func WithTokenSource(source TokenSource) ClientOption {
+	return func(o *clientOptions) error {
+		if source == nil {
+			return errors.New("token source must not be nil")
+		}
+		o.tokenSource = source
+		return nil
+	}
+}
+
+// WithTokenCache makes the client keep its token in cache, so a new process can
+// reuse the token of an earlier one instead of logging in again. Every new or
+// renewed token is stored under identity, and Logout removes it. The cached
+// token is only used after an explicit RestoreCachedToken call; Login methods
+// always log in, so their credentials are checked.
+//
+// Parameters:
+//   - cache: The cache, e.g. a FileTokenCache.
+//   - identity: The user the token belongs to, e.g. the username; it must match
+//     the user of the logins of the client.
+func WithTokenCache(cache TokenCache, identity string) ClientOption {
+	return func(o *clientOptions) error {
+		if cache == nil {
+			return errors.New("token cache must not be nil")
+		}
+		o.tokenCache = &clientTokenCache{cache: cache, identity: identity}
+		return nil
+	}
+}
+
+// clientLogger returns the logger configured for the client, or the package logger.
+func (o *clientOptions) clientLogger() *slog.Logger {
+	if o.logger != nil {
+		return o.logger
+	}
+	return GetLogger()
+}
+
+// hasTransportSettings reports whether options were given that configure the
+// transport created by the client itself.
+func (o *clientOptions) hasTransportSettings() bool {
+	return o.rootCAs != nil || len(o.caBundles) > 0 || o.systemRoots || o.cwdCertificates ||
+		o.proxy != nil || o.tlsHandshakeTimeout > 0
+}
+
+// newHTTPClient builds the HTTP client described by the options.
+//
+// Returns:
+//   - *http.Client: The configured HTTP client.
+//   - error: An error if the options conflict or a CA bundle cannot be loaded.
+func (o *clientOptions) newHTTPClient() (*http.Client, error) {
+	if (o.httpClient != nil || o.transport != nil) && o.hasTransportSettings() {
+		return nil, errors.New("trust, proxy and TLS options cannot be combined with a custom HTTP client or transport")
+	}
+
+	var client http.Client
+	if o.httpClient != nil {
+		client = *o.httpClient
+	}
+	if o.timeout > 0 {
+		client.Timeout = o.timeout
+	}
+
+	if o.transport != nil {
+		client.Transport = o.transport
+		return &client, nil
+	}
+	if o.httpClient != nil {
+		return &client, nil
+	}
+
+	rootCAs, err := o.rootCertPool()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    rootCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	if o.proxy != nil {
+		transport.Proxy = o.proxy
+	}
+	if o.tlsHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = o.tlsHandshakeTimeout
+	}
+	client.Transport = transport
+
+	return &client, nil
+}
+
+// rootCertPool builds the pool of trusted root certificates.
+//
+// Returns:
+//   - *x509.CertPool: The pool to use, or nil to use the system roots.
+//   - error: An error if the options conflict or a CA bundle cannot be loaded.
+func (o *clientOptions) rootCertPool() (*x509.CertPool, error) {
+	if o.rootCAs == nil && len(o.caBundles) == 0 && !o.cwdCertificates {
+		// A nil pool makes crypto/tls use the system roots
+		return nil, nil
+	}
+
+	var pool *x509.CertPool
+	switch {
+	case o.rootCAs != nil && o.systemRoots:
+		return nil, errors.New("WithCertPool cannot be combined with WithSystemRoots")
+	case o.rootCAs != nil:
+		pool = o.rootCAs
+	case o.systemRoots:
+		systemPool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load system root certificates: %w", err)
+		}
+		pool = systemPool
+	default:
+		pool = x509.NewCertPool()
+	}
+
+	for _, path := range o.caBundles {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", path)
+		}
+	}
+
+	if o.cwdCertificates {
+		addCurrentDirectoryCertificates(o.clientLogger(), pool)
+	}
+
+	return pool, nil
+}
+
+// NewClientWithOptions creates a new SafeguardClient configured by the given options.
+// Unlike NewClient it does not modify the package logger and only trusts the
+// system root certificates unless told otherwise.
+//
+// Example:
+//
+//	client, err := safeguard.NewClientWithOptions("https://appliance.example.com",
+//		safeguard.WithCABundle("/etc/ssl/pam-ca.pem"),
+//		safeguard.WithTimeout(30*time.Second),
+//		safeguard.WithLogger(logger),
+//	)
+//
+// Parameters:
+//   - applianceUrl: The URL of the appliance to connect to.
+//   - opts: Options configuring the client.
+//
+// Returns:
+//   - *SafeguardClient: The configured client.
+//   - error: An error if the URL is invalid or the options cannot be applied.
+func NewClientWithOptions(applianceUrl string, opts ...ClientOption) (*SafeguardClient, error) {
+	o := defaultClientOptions()
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, _, _, _, err := splitApplianceURL(applianceUrl); err != nil {
+		return nil, fmt.Errorf("invalid appliance URL: %w", err)
+	}
+
+	httpClient, err := o.newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(applianceUrl, o, httpClient), nil
+}
@@ -0,0 +1,174 @@
+package safeguard
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeServerCA writes the certificate of a TLS test server to a PEM file.
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewClientWithOptionsTrust(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caBundle := writeServerCA(t, server)
+	pool := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	tests := []struct {
+		name    string
+		opts    []ClientOption
+		wantErr bool
+	}{
+		{name: "system roots only", opts: nil, wantErr: true},
+		{name: "CA bundle", opts: []ClientOption{WithCABundle(caBundle)}, wantErr: false},
+		{name: "CA bundle with system roots", opts: []ClientOption{WithCABundle(caBundle), WithSystemRoots()}, wantErr: false},
+		{name: "cert pool", opts: []ClientOption{WithCertPool(pool)}, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]ClientOption{WithoutBackgroundRefresh()}, tt.opts...)
+			client, err := NewClientWithOptions(server.URL, opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			resp, err := client.HttpClient.Get(server.URL)
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("request error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCurrentDirectoryCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caBundle, err := os.ReadFile(writeServerCA(t, server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "appliance.pem"), caBundle, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	tests := []struct {
+		name      string
+		newClient func() (*SafeguardClient, error)
+		wantErr   bool
+	}{
+		{
+			name: "NewClient",
+			newClient: func() (*SafeguardClient, error) {
+				return NewClient(server.URL, "v4", false), nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "without option",
+			newClient: func() (*SafeguardClient, error) {
+				return NewClientWithOptions(server.URL, WithoutBackgroundRefresh())
+			},
+			wantErr: true,
+		},
+		{
+			name: "with option",
+			newClient: func() (*SafeguardClient, error) {
+				return NewClientWithOptions(server.URL, WithoutBackgroundRefresh(), WithCurrentDirectoryCertificates())
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := tt.newClient()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer client.Close(context.Background())
+
+			resp, err := client.HttpClient.Get(server.URL)
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("request error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClientWithOptionsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		opts []ClientOption
+	}{
+		{name: "invalid URL", url: "ftp://appliance.example.com"},
+		{name: "missing CA bundle", url: "https://appliance.example.com", opts: []ClientOption{WithCABundle("does-not-exist.pem")}},
+		{name: "custom client with CA bundle", url: "https://appliance.example.com", opts: []ClientOption{WithHTTPClient(&http.Client{}), WithCABundle("ca.pem")}},
+		{name: "custom transport with proxy", url: "https://appliance.example.com", opts: []ClientOption{WithTransport(&http.Transport{}), WithProxy("http://proxy:3128")}},
+		{name: "cert pool with system roots", url: "https://appliance.example.com", opts: []ClientOption{WithCertPool(x509.NewCertPool()), WithSystemRoots()}},
+		{name: "nil logger", url: "https://appliance.example.com", opts: []ClientOption{WithLogger(nil)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClientWithOptions(tt.url, tt.opts...); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestNewClientWithOptionsSettings(t *testing.T) {
+	transport := &http.Transport{}
+	client, err := NewClientWithOptions("https://appliance.example.com",
+		WithAPIVersion("v3"),
+		WithTransport(transport),
+		WithTimeout(5*time.Second),
+		WithoutBackgroundRefresh(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if client.ApiVersion != "v3" {
+		t.Errorf("ApiVersion = %s, want v3", client.ApiVersion)
+	}
+	if client.HttpClient.Transport != transport {
+		t.Error("expected custom transport to be used")
+	}
+	if client.HttpClient.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want 5s", client.HttpClient.Timeout)
+	}
+	if client.authDone != nil {
+		t.Error("expected background refresh to be disabled")
+	}
+
+	// Must not block without a refresh goroutine
+	client.signalAuthDone()
+}
@@ -0,0 +1,110 @@
+package safeguard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+	"strconv"
+)
+
+// DefaultPageSize is the number of items requested per page by the pagination
+// iterators when the filter does not set a Limit.
+const DefaultPageSize = 500
+
+// paginate returns an iterator that lazily fetches the collection at path one
+// page at a time. The next page is only requested once all items of the
+// current page have been consumed, so breaking out of the loop stops paging.
+// Each page is decoded while it is received instead of being buffered.
+//
+// Paging starts at filter.Page and uses filter.Limit as page size, falling
+// back to DefaultPageSize. Set filter.Orderby to get a stable order across pages.
+// Iteration ends with the first page that has fewer than filter.Limit items,
+// or with a page that starts with the same item as the previous one, which
+// means the endpoint ignores the paging parameters.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - c: The SafeguardClient used for the requests.
+//   - path: The API path of the collection, e.g. "AssetAccounts".
+//   - filter: Query parameters applied to every page.
+//
+// Returns:
+//   - iter.Seq2[T, error]: Yields each item with a nil error. A failed page
+//     request is yielded once as an error, after which iteration ends.
+func paginate[T ClientHolder](ctx context.Context, c *SafeguardClient, path string, filter Filter) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		filter.Count = false
+		if filter.Limit <= 0 {
+			filter.Limit = DefaultPageSize
+		}
+		if filter.Page < 0 {
+			filter.Page = 0
+		}
+
+		var previous *T
+		for {
+			stopped, repeated := false, false
+			var first *T
+			count, err := streamArray(ctx, c, path+filter.ToQueryString(), func(item T) bool {
+				if first == nil {
+					first = &item
+					if previous != nil && reflect.DeepEqual(item, *previous) {
+						repeated = true
+						return false
+					}
+				}
+				stopped = !yield(addClient(c, item), nil)
+				return !stopped
+			})
+			if stopped {
+				return
+			}
+			if repeated {
+				c.logger().Warn("Endpoint returned the same page again, it does not support paging", "path", path)
+				return
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			if count < filter.Limit {
+				return
+			}
+			previous = first
+			filter.Page++
+		}
+	}
+}
+
+// countItems returns the total number of items at path matching the filter,
+// using the count=true query option. Fields, paging and ordering are ignored.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - c: The SafeguardClient used for the request.
+//   - path: The API path of the collection, e.g. "AssetAccounts".
+//   - filter: Query parameters for filtering the collection.
+//
+// Returns:
+//   - int: The number of matching items.
+//   - error: An error if the request fails or the response is not a number.
+func countItems(ctx context.Context, c *SafeguardClient, path string, filter Filter) (int, error) {
+	query := Filter{Filter: filter.Filter, Count: true}
+
+	response, err := c.GetRequest(ctx, path+query.ToQueryString())
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := strconv.Atoi(string(bytes.TrimSpace(response)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse count of %s: %w", path, err)
+	}
+
+	return total, nil
+}
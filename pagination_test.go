@@ -0,0 +1,159 @@
+package safeguard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// newPagingServerClient serves total asset accounts at AssetAccounts, honouring
+// the page, limit and count query parameters.
+func newPagingServerClient(t *testing.T, total int, requests *atomic.Int32) *SafeguardClient {
+	t.Helper()
+	return newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		q := r.URL.Query()
+
+		if q.Get("count") == "true" {
+			fmt.Fprint(w, total)
+			return
+		}
+
+		page, _ := strconv.Atoi(q.Get("page"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		if limit == 0 {
+			limit = total
+		}
+
+		items := []AssetAccount{}
+		for id := page*limit + 1; id <= min((page+1)*limit, total); id++ {
+			items = append(items, AssetAccount{Id: id})
+		}
+		json.NewEncoder(w).Encode(items)
+	})
+}
+
+func TestPaginateFetchesAllPages(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        int
+		limit        int
+		wantRequests int32
+	}{
+		{name: "partial last page", total: 5, limit: 2, wantRequests: 3},
+		{name: "full last page", total: 4, limit: 2, wantRequests: 3},
+		{name: "empty collection", total: 0, limit: 2, wantRequests: 1},
+		{name: "default page size", total: 3, limit: 0, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			client := newPagingServerClient(t, tt.total, &requests)
+
+			var ids []int
+			for account, err := range client.AssetAccounts(context.Background(), Filter{Limit: tt.limit}) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if account.apiClient != client {
+					t.Error("expected client to be set on yielded item")
+				}
+				ids = append(ids, account.Id)
+			}
+
+			if len(ids) != tt.total {
+				t.Fatalf("expected %d accounts, got %d", tt.total, len(ids))
+			}
+			for i, id := range ids {
+				if id != i+1 {
+					t.Errorf("item %d has Id %d, want %d", i, id, i+1)
+				}
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("expected %d requests, got %d", tt.wantRequests, got)
+			}
+		})
+	}
+}
+
+func TestPaginateStopsWhenLoopBreaks(t *testing.T) {
+	var requests atomic.Int32
+	client := newPagingServerClient(t, 10, &requests)
+
+	count := 0
+	for _, err := range client.AssetAccounts(context.Background(), Filter{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		count++
+		if count == 3 {
+			break
+		}
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestPaginateStopsWhenServerIgnoresPaging(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `[{"Id":1},{"Id":2}]`)
+	})
+
+	var ids []int
+	for account, err := range client.AssetAccounts(context.Background(), Filter{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, account.Id)
+		if len(ids) > 10 {
+			t.Fatal("iteration did not stop")
+		}
+	}
+
+	if len(ids) != 2 {
+		t.Errorf("expected the page once, got Ids %v", ids)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestPaginateYieldsRequestError(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	var errs int
+	for _, err := range client.AssetAccounts(context.Background(), Filter{}) {
+		if !IsForbidden(err) {
+			t.Errorf("expected forbidden error, got %v", err)
+		}
+		errs++
+	}
+
+	if errs != 1 {
+		t.Errorf("expected exactly one error, got %d", errs)
+	}
+}
+
+func TestCountItems(t *testing.T) {
+	var requests atomic.Int32
+	client := newPagingServerClient(t, 80000, &requests)
+
+	total, err := client.CountAssetAccounts(context.Background(), Filter{Fields: Fields{"Name"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 80000 {
+		t.Errorf("expected total 80000, got %d", total)
+	}
+}
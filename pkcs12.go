@@ -69,22 +69,3 @@ func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
 	}
 	return nil, fmt.Errorf("failed to parse private key in common formats")
 }
-
-// tLSConfigForPKCS12 creates a tls.Config from a PKCS12 encoded certificate.
-// Parameters:
-//   - data: the PKCS12 encoded certificate bytes
-//   - password: the password for the PKCS12 encoded certificate
-//
-// Returns:
-//   - *tls.Config: TLS configuration using the certificate
-//   - error: An error if configuration fails
-func tLSConfigForPKCS12(data []byte, password string) (*tls.Config, error) {
-	cert, err := loadPKCS12(data, password)
-	if err != nil {
-		return nil, err
-	}
-	return &tls.Config{
-		Certificates:  []tls.Certificate{cert},
-		Renegotiation: tls.RenegotiateOnceAsClient,
-	}, nil
-}
@@ -1,8 +1,10 @@
 package safeguard
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 )
 
 // PolicyAccount represents a Safeguard account with its associated policies and properties
@@ -190,17 +192,18 @@ func (p PolicyAccount) ToJson() (string, error) {
 //	accounts, err := GetPolicyAccounts(fields)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: A Filter object containing field comparisons and ordering preferences
 //
 // Returns:
 //   - []PolicyAccount: A slice of PolicyAccount objects matching the filter criteria
 //   - error: An error if the request fails or response parsing fails, nil otherwise
-func (c *SafeguardClient) GetPolicyAccounts(fields Filter) ([]PolicyAccount, error) {
+func (c *SafeguardClient) GetPolicyAccounts(ctx context.Context, fields Filter) ([]PolicyAccount, error) {
 	var policyAccounts []PolicyAccount
 
 	query := "PolicyAccounts" + fields.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -212,6 +215,42 @@ func (c *SafeguardClient) GetPolicyAccounts(fields Filter) ([]PolicyAccount, err
 	return addClientToSlice(c, policyAccounts), nil
 }
 
+// PolicyAccounts returns an iterator over all policy accounts matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.PolicyAccounts(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[PolicyAccount, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) PolicyAccounts(ctx context.Context, filter Filter) iter.Seq2[PolicyAccount, error] {
+	return paginate[PolicyAccount](ctx, c, "PolicyAccounts", filter)
+}
+
+// CountPolicyAccounts returns the total number of policy accounts matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching policy accounts
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountPolicyAccounts(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "PolicyAccounts", filter)
+}
+
 // GetPolicyAccount retrieves a single policy account by its unique identifier.
 //
 // The method can include additional related objects in the response based on the
@@ -224,13 +263,14 @@ func (c *SafeguardClient) GetPolicyAccounts(fields Filter) ([]PolicyAccount, err
 //	account, err := GetPolicyAccount(123, fields)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the policy account to retrieve
 //   - fields: Optional Fields object specifying which related objects to include
 //
 // Returns:
 //   - PolicyAccount: The requested policy account with all specified related objects
 //   - error: An error if the account is not found or request fails, nil otherwise
-func (c *SafeguardClient) GetPolicyAccount(id int, fields Fields) (PolicyAccount, error) {
+func (c *SafeguardClient) GetPolicyAccount(ctx context.Context, id int, fields Fields) (PolicyAccount, error) {
 	var policyAccount PolicyAccount
 
 	query := fmt.Sprintf("PolicyAccounts/%d", id)
@@ -238,7 +278,7 @@ func (c *SafeguardClient) GetPolicyAccount(id int, fields Fields) (PolicyAccount
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return policyAccount, err
 	}
@@ -260,13 +300,14 @@ func (c *SafeguardClient) GetPolicyAccount(id int, fields Fields) (PolicyAccount
 //	linkedAccounts, err := account.LinkToUser(user)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - user: The User object representing the user to link with
 //
 // Returns:
 //   - []PolicyAccount: A slice containing the updated account after linking
 //   - error: An error if the link operation fails, nil otherwise
-func (p PolicyAccount) LinkToUser(user User) ([]PolicyAccount, error) {
-	return p.apiClient.AddLinkedAccounts(user, []PolicyAccount{p})
+func (p PolicyAccount) LinkToUser(ctx context.Context, user User) ([]PolicyAccount, error) {
+	return p.apiClient.AddLinkedAccounts(ctx, user, []PolicyAccount{p})
 }
 
 // UnlinkFromUser removes the relationship between a policy account and a user.
@@ -280,11 +321,12 @@ func (p PolicyAccount) LinkToUser(user User) ([]PolicyAccount, error) {
 //	unlinkedAccounts, err := account.UnlinkFromUser(user)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - user: The User object representing the user to unlink from
 //
 // Returns:
 //   - []PolicyAccount: A slice containing the updated account after unlinking
 //   - error: An error if the unlink operation fails, nil otherwise
-func (p PolicyAccount) UnlinkFromUser(user User) ([]PolicyAccount, error) {
-	return p.apiClient.RemoveLinkedAccounts(user, []PolicyAccount{p})
+func (p PolicyAccount) UnlinkFromUser(ctx context.Context, user User) ([]PolicyAccount, error) {
+	return p.apiClient.RemoveLinkedAccounts(ctx, user, []PolicyAccount{p})
 }
@@ -1,8 +1,10 @@
 package safeguard
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 )
 
 // AssetPolicy represents a policy that an asset belongs to plus how that membership was granted
@@ -166,17 +168,18 @@ type SessionAccessProperties struct {
 //	assets, err := GetPolicyAssets(filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: A Filter object containing field comparisons and ordering preferences
 //
 // Returns:
 //   - []PolicyAsset: A slice of PolicyAsset objects matching the filter criteria
 //   - error: An error if the request or response parsing fails, nil otherwise
-func (c *SafeguardClient) GetPolicyAssets(fields Filter) ([]PolicyAsset, error) {
+func (c *SafeguardClient) GetPolicyAssets(ctx context.Context, fields Filter) ([]PolicyAsset, error) {
 	var policyAssets []PolicyAsset
 
 	query := "PolicyAssets" + fields.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -188,6 +191,42 @@ func (c *SafeguardClient) GetPolicyAssets(fields Filter) ([]PolicyAsset, error)
 	return addClientToSlice(c, policyAssets), nil
 }
 
+// PolicyAssets returns an iterator over all policy assets matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.PolicyAssets(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[PolicyAsset, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) PolicyAssets(ctx context.Context, filter Filter) iter.Seq2[PolicyAsset, error] {
+	return paginate[PolicyAsset](ctx, c, "PolicyAssets", filter)
+}
+
+// CountPolicyAssets returns the total number of policy assets matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching policy assets
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountPolicyAssets(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "PolicyAssets", filter)
+}
+
 // GetPolicyAsset retrieves a single policy asset by its unique identifier.
 //
 // The method supports including additional related objects based on the fields parameter.
@@ -200,13 +239,14 @@ func (c *SafeguardClient) GetPolicyAssets(fields Filter) ([]PolicyAsset, error)
 //	asset, err := GetPolicyAsset(123, fields)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the policy asset to retrieve
 //   - fields: Optional Fields object specifying which related objects to include
 //
 // Returns:
 //   - PolicyAsset: The requested policy asset with all specified related objects
 //   - error: An error if the asset is not found or request fails, nil otherwise
-func (c *SafeguardClient) GetPolicyAsset(id int, fields Fields) (PolicyAsset, error) {
+func (c *SafeguardClient) GetPolicyAsset(ctx context.Context, id int, fields Fields) (PolicyAsset, error) {
 	var policyAsset PolicyAsset
 
 	query := fmt.Sprintf("PolicyAssets/%d", id)
@@ -214,7 +254,7 @@ func (c *SafeguardClient) GetPolicyAsset(id int, fields Fields) (PolicyAsset, er
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return policyAsset, err
 	}
@@ -236,17 +276,18 @@ func (c *SafeguardClient) GetPolicyAsset(id int, fields Fields) (PolicyAsset, er
 //	groups, err := asset.GetAssetGroups(filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: A Filter object to restrict which groups are returned
 //
 // Returns:
 //   - []AssetGroup: A slice of AssetGroup objects this asset belongs to
 //   - error: An error if the request or response parsing fails, nil otherwise
-func (p PolicyAsset) GetAssetGroups(fields Filter) ([]AssetGroup, error) {
+func (p PolicyAsset) GetAssetGroups(ctx context.Context, fields Filter) ([]AssetGroup, error) {
 	var assetGroups []AssetGroup
 
 	query := fmt.Sprintf("PolicyAssets/%d/AssetGroups", p.Id) + fields.ToQueryString()
 
-	response, err := p.apiClient.GetRequest(query)
+	response, err := p.apiClient.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -268,17 +309,18 @@ func (p PolicyAsset) GetAssetGroups(fields Filter) ([]AssetGroup, error) {
 //	entries, err := directoryAsset.GetDirectoryServiceEntries(filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: A Filter object to restrict which entries are returned
 //
 // Returns:
 //   - []DirectoryServiceEntry: A slice of directory entries from this asset
 //   - error: An error if the request or response parsing fails, nil otherwise
-func (p PolicyAsset) GetDirectoryServiceEntries(fields Filter) ([]DirectoryServiceEntry, error) {
+func (p PolicyAsset) GetDirectoryServiceEntries(ctx context.Context, fields Filter) ([]DirectoryServiceEntry, error) {
 	var directoryServiceEntries []DirectoryServiceEntry
 
 	query := fmt.Sprintf("PolicyAssets/%d/DirectoryServiceEntries", p.Id) + fields.ToQueryString()
 
-	response, err := p.apiClient.GetRequest(query)
+	response, err := p.apiClient.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -300,17 +342,18 @@ func (p PolicyAsset) GetDirectoryServiceEntries(fields Filter) ([]DirectoryServi
 //	policies, err := asset.GetPolicies(filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: A Filter object to restrict which policies are returned
 //
 // Returns:
 //   - []AssetPolicy: A slice of policies granting access to this asset
 //   - error: An error if the request or response parsing fails, nil otherwise
-func (p PolicyAsset) GetPolicies(fields Filter) ([]AssetPolicy, error) {
+func (p PolicyAsset) GetPolicies(ctx context.Context, fields Filter) ([]AssetPolicy, error) {
 	var policies []AssetPolicy
 
 	query := fmt.Sprintf("PolicyAssets/%d/Policies", p.Id) + fields.ToQueryString()
 
-	response, err := p.apiClient.GetRequest(query)
+	response, err := p.apiClient.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
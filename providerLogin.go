@@ -0,0 +1,359 @@
+package safeguard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+)
+
+// providerScopePrefix is the prefix of the RSTS scope of an authentication provider.
+const providerScopePrefix = "rsts:sts:primaryproviderid:"
+
+// maxChallenges bounds the rounds of secondary authentication of a login, so a
+// misbehaving provider cannot keep the login going forever.
+const maxChallenges = 5
+
+// ProviderScope returns the AuthProvider for the RSTS provider id of an
+// authentication provider, e.g. the domain name of an Active Directory provider.
+//
+// Parameters:
+//   - rstsProviderID: The RstsProviderId of the authentication provider.
+//
+// Returns:
+//   - AuthProvider: The scope to log in with, e.g. "rsts:sts:primaryproviderid:ad.example.com".
+func ProviderScope(rstsProviderID string) AuthProvider {
+	if strings.HasPrefix(rstsProviderID, providerScopePrefix) {
+		return AuthProvider(rstsProviderID)
+	}
+	return AuthProvider(providerScopePrefix + rstsProviderID)
+}
+
+// providerID returns the RSTS provider id of the scope.
+func (a AuthProvider) providerID() string {
+	return strings.TrimPrefix(a.String(), providerScopePrefix)
+}
+
+// AuthProvider returns the scope to log in against this provider with
+// LoginWithProvider.
+//
+// Returns:
+//   - AuthProvider: The scope of the provider.
+func (a AuthenticationProvider) AuthProvider() AuthProvider {
+	if a.RstsProviderScope != "" {
+		return ProviderScope(a.RstsProviderScope)
+	}
+	return ProviderScope(a.RstsProviderId)
+}
+
+// MFAChallenge is a secondary authentication challenge, e.g. of a RADIUS or
+// OneLogin MFA provider.
+type MFAChallenge struct {
+	Provider AuthProvider // Primary provider the user logs in with
+	Username string       // User that logs in
+	Message  string       // Prompt from the secondary provider, e.g. "Enter your one-time password"
+	Attempt  int          // Number of the challenge within the login, starting at 1
+}
+
+// ChallengeHandler answers secondary authentication challenges during a login.
+type ChallengeHandler interface {
+	// Respond returns the answer to the challenge, e.g. a one-time password.
+	// Returning an empty answer confirms challenges that need no input, such as
+	// a push notification that was approved on the phone.
+	Respond(ctx context.Context, challenge MFAChallenge) (string, error)
+}
+
+// ChallengeHandlerFunc adapts a function to a ChallengeHandler.
+type ChallengeHandlerFunc func(ctx context.Context, challenge MFAChallenge) (string, error)
+
+// Respond calls f(ctx, challenge).
+func (f ChallengeHandlerFunc) Respond(ctx context.Context, challenge MFAChallenge) (string, error) {
+	return f(ctx, challenge)
+}
+
+// LoginWithProvider logs in with the username and password of a user of an
+// authentication provider, e.g. Active Directory, LDAP or RADIUS, as listed by
+// GetAuthenticationProviders. If the provider requires secondary
+// authentication, challenge answers the challenges.
+//
+// Without a challenge handler the OAuth resource owner password grant is used,
+// which fails for users that need secondary authentication. With one, the login
+// goes through the RSTS login controller, which supports secondary
+// authentication. Logins with a challenge handler are never replayed, so the
+// user is not asked again in the background.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the login, including the challenges.
+//   - provider: The provider to log in with, e.g. ProviderScope("ad.example.com").
+//   - username: The username of the user, e.g. "admin@ad.example.com".
+//   - password: The password of the user.
+//   - challenge: Answers secondary authentication challenges, or nil.
+//
+// Returns:
+//   - error: An error if the login fails, a challenge is not answered or RSTS
+//     rejects an answer.
+//
+// Example:
+//
+//	err := client.LoginWithProvider(ctx, safeguard.ProviderScope("ad.example.com"), "admin", password,
+//	    safeguard.ChallengeHandlerFunc(func(ctx context.Context, c safeguard.MFAChallenge) (string, error) {
+//	        fmt.Println(c.Message)
+//	        return readLine(ctx)
+//	    }))
+func (c *SafeguardClient) LoginWithProvider(ctx context.Context, provider AuthProvider, username, password string, challenge ChallengeHandler) (err error) {
+	ctx, end := c.traceLogin(ctx, LoginMethodPassword)
+	defer end(&err)
+
+	var replay func(ctx context.Context) error
+	if challenge == nil {
+		replay = c.credentialReplay(func(ctx context.Context) error {
+			username, password := c.AccessToken.getUserNamePassword()
+			return c.loginWithResourceOwner(ctx, provider, username, password)
+		})
+	}
+
+	return c.login(ctx, LoginMethodPassword, func(ctx context.Context) error {
+		if err := c.loginWithProvider(ctx, provider, username, password, challenge); err != nil {
+			return err
+		}
+		if replay != nil {
+			c.AccessToken.setUserNamePassword(username, password)
+		}
+		return nil
+	}, replay)
+}
+
+// ProviderTokenSource returns a TokenSource that logs in with the username and
+// password of a user of an authentication provider, see LoginWithProvider.
+//
+// Parameters:
+//   - provider: The provider to log in with.
+//   - username: The username of the user.
+//   - password: The password of the user.
+//   - challenge: Answers secondary authentication challenges, or nil.
+//
+// Returns:
+//   - TokenSource: The token source.
+func ProviderTokenSource(provider AuthProvider, username, password string, challenge ChallengeHandler) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context, c *SafeguardClient) (*Token, error) {
+		if err := c.loginWithProvider(ctx, provider, username, password, challenge); err != nil {
+			return nil, err
+		}
+		return c.AccessToken.token(), nil
+	})
+}
+
+// loginWithProvider performs the login of LoginWithProvider.
+func (c *SafeguardClient) loginWithProvider(ctx context.Context, provider AuthProvider, username, password string, challenge ChallengeHandler) error {
+	if challenge == nil {
+		return c.loginWithResourceOwner(ctx, provider, username, password)
+	}
+
+	if c.AccessToken == nil {
+		c.AccessToken = &RSTSAuthResponse{}
+	}
+	codeVerifier, codeChallenge := generateCodeChallenge()
+
+	lc, err := c.newLoginController(codeChallenge)
+	if err != nil {
+		return err
+	}
+
+	// Primary authentication
+	if _, err := lc.step(ctx, loginStepInitialize, nil); err != nil {
+		return err
+	}
+	status, err := lc.step(ctx, loginStepPrimary, url.Values{
+		"directoryComboBox": {provider.providerID()},
+		"usernameTextbox":   {username},
+		"passwordTextbox":   {password},
+	})
+	if err != nil {
+		return fmt.Errorf("primary authentication failed: %w", err)
+	}
+
+	// Secondary authentication, repeated as long as the provider asks for more
+	if status.secondary {
+		if status, err = lc.step(ctx, loginStepSecondaryInit, nil); err != nil {
+			return fmt.Errorf("secondary authentication failed: %w", err)
+		}
+		for attempt := 1; status.secondary; attempt++ {
+			if attempt > maxChallenges {
+				return fmt.Errorf("secondary authentication failed: more than %d challenges", maxChallenges)
+			}
+			answer, err := challenge.Respond(ctx, MFAChallenge{
+				Provider: provider,
+				Username: username,
+				Message:  status.message,
+				Attempt:  attempt,
+			})
+			if err != nil {
+				return fmt.Errorf("secondary authentication cancelled: %w", err)
+			}
+			status, err = lc.step(ctx, loginStepSecondary, url.Values{"secondaryLoginTextbox": {answer}})
+			if err != nil {
+				return fmt.Errorf("secondary authentication failed: %w", err)
+			}
+		}
+	}
+
+	code, err := lc.authorizationCode(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.redeemAuthorizationCode(ctx, code, installedApplicationRedirectURI, codeVerifier); err != nil {
+		return err
+	}
+
+	c.logger().Info("Login successful", "provider", provider)
+	return nil
+}
+
+// loginStep is a step of the RSTS login controller.
+type loginStep int
+
+// Steps of the RSTS login controller, in the order of a login.
+const (
+	loginStepInitialize    loginStep = 1 // Starts the login and sets the CSRF cookie
+	loginStepPrimary       loginStep = 3 // Authenticates with username and password
+	loginStepSecondaryInit loginStep = 5 // Starts secondary authentication and returns its prompt
+	loginStepClaims        loginStep = 6 // Completes the login and returns the authorization code
+	loginStepSecondary     loginStep = 7 // Answers a secondary authentication challenge
+)
+
+// loginController drives a login through the RSTS login controller, the
+// endpoint behind the RSTS login page.
+type loginController struct {
+	client   *http.Client // Client with a cookie jar for the CSRF cookie
+	endpoint *url.URL
+}
+
+// loginStatus is the outcome of a login controller step.
+type loginStatus struct {
+	secondary bool   // Whether secondary authentication is (still) required
+	message   string // Prompt or message of the provider
+}
+
+// newLoginController prepares a login through the RSTS login controller.
+//
+// Parameters:
+//   - codeChallenge: The PKCE code challenge of the login.
+//
+// Returns:
+//   - *loginController: The login controller.
+//   - error: An error if the appliance URL is invalid.
+func (c *SafeguardClient) newLoginController(codeChallenge string) (*loginController, error) {
+	endpoint, err := url.Parse(c.Appliance.getUrl() + "/RSTS/UserLogin/LoginController")
+	if err != nil {
+		return nil, fmt.Errorf("invalid appliance URL: %w", err)
+	}
+	endpoint.RawQuery = url.Values{
+		"response_type":         {"code"},
+		"code_challenge_method": {"S256"},
+		"code_challenge":        {codeChallenge},
+		"redirect_uri":          {installedApplicationRedirectURI},
+	}.Encode()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	client := *c.httpClient()
+	client.Jar = jar
+	return &loginController{client: &client, endpoint: endpoint}, nil
+}
+
+// step posts a step to the login controller. The login controller answers 200
+// when a step is complete and 203 when secondary authentication is required.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
+//   - step: The step to perform.
+//   - form: The form fields of the step, without the CSRF token.
+//
+// Returns:
+//   - loginStatus: The outcome of the step.
+//   - error: An error if the request fails or the step is rejected.
+func (l *loginController) step(ctx context.Context, step loginStep, form url.Values) (loginStatus, error) {
+	status, _, err := l.post(ctx, step, form)
+	return status, err
+}
+
+// post performs step and also returns the response body.
+func (l *loginController) post(ctx context.Context, step loginStep, form url.Values) (loginStatus, []byte, error) {
+	if form == nil {
+		form = url.Values{}
+	}
+	for _, cookie := range l.client.Jar.Cookies(l.endpoint) {
+		if cookie.Name == "CsrfToken" {
+			form.Set("csrfTokenTextbox", cookie.Value)
+		}
+	}
+
+	u := *l.endpoint
+	query := u.Query()
+	query.Set("loginRequestStep", fmt.Sprint(int(step)))
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return loginStatus{}, nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return loginStatus{}, nil, fmt.Errorf("RSTS login request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	message := loginMessage(body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return loginStatus{message: message}, body, nil
+	case http.StatusNonAuthoritativeInfo:
+		return loginStatus{secondary: true, message: message}, body, nil
+	default:
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return loginStatus{}, body, fmt.Errorf("RSTS rejected the login: %s", message)
+	}
+}
+
+// authorizationCode completes the login and returns its authorization code.
+func (l *loginController) authorizationCode(ctx context.Context) (string, error) {
+	_, body, err := l.post(ctx, loginStepClaims, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to complete login: %w", err)
+	}
+
+	var claims struct {
+		RelyingPartyUrl string `json:"RelyingPartyUrl"`
+	}
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return "", fmt.Errorf("failed to parse login response: %w", err)
+	}
+	code, err := parseAuthorizationCode(claims.RelyingPartyUrl)
+	if err != nil {
+		return "", fmt.Errorf("failed to complete login: %w", err)
+	}
+	return code, nil
+}
+
+// loginMessage extracts the message of a login controller response. Responses
+// are either JSON with a "Message" property or plain text.
+func loginMessage(body []byte) string {
+	var response struct {
+		Message string `json:"Message"`
+	}
+	if err := json.Unmarshal(body, &response); err == nil {
+		return response.Message
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,218 @@
+package safeguard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// fakeLoginController adds the RSTS login controller to the server. Users of the
+// provider "ad.example.com" log in with "<username>-password"; users in otp must
+// answer the challenges with the listed one-time passwords in order.
+func (f *fakeAuthServer) fakeLoginController(t *testing.T, otp map[string][]string) {
+	t.Helper()
+
+	type login struct {
+		username  string
+		remaining []string // One-time passwords still to be answered
+		done      bool
+	}
+	var mu sync.Mutex
+	logins := map[string]*login{} // By CSRF token
+
+	f.mux.HandleFunc("POST /RSTS/UserLogin/LoginController", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Query().Get("redirect_uri") != installedApplicationRedirectURI || r.URL.Query().Get("code_challenge") == "" {
+			t.Errorf("unexpected login controller URL %s", r.URL)
+		}
+
+		step := r.URL.Query().Get("loginRequestStep")
+		if step == "1" {
+			csrf := fmt.Sprintf("csrf-%d", len(logins))
+			logins[csrf] = &login{}
+			http.SetCookie(w, &http.Cookie{Name: "CsrfToken", Value: csrf, Path: "/RSTS"})
+			return
+		}
+
+		cookie, err := r.Cookie("CsrfToken")
+		if err != nil || r.FormValue("csrfTokenTextbox") != cookie.Value || logins[cookie.Value] == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"Message": "invalid CSRF token"})
+			return
+		}
+		l := logins[cookie.Value]
+
+		switch step {
+		case "3":
+			username := r.FormValue("usernameTextbox")
+			if r.FormValue("directoryComboBox") != "ad.example.com" || r.FormValue("passwordTextbox") != username+"-password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				json.NewEncoder(w).Encode(map[string]string{"Message": "Invalid username or password"})
+				return
+			}
+			l.username, l.remaining = username, otp[username]
+			l.done = len(l.remaining) == 0
+			if !l.done {
+				w.WriteHeader(http.StatusNonAuthoritativeInfo)
+			}
+		case "5", "7":
+			if l.username == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if step == "7" {
+				if len(l.remaining) == 0 || r.FormValue("secondaryLoginTextbox") != l.remaining[0] {
+					w.WriteHeader(http.StatusUnauthorized)
+					json.NewEncoder(w).Encode(map[string]string{"Message": "Invalid one-time password"})
+					return
+				}
+				l.remaining = l.remaining[1:]
+			}
+			if len(l.remaining) > 0 {
+				w.WriteHeader(http.StatusNonAuthoritativeInfo)
+				json.NewEncoder(w).Encode(map[string]string{"Message": fmt.Sprintf("Enter one-time password %d", len(otp[l.username])-len(l.remaining)+1)})
+				return
+			}
+			l.done = true
+		case "6":
+			if !l.done {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"RelyingPartyUrl": installedApplicationRedirectURI + "?oauth=code-" + l.username})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+}
+
+func TestProviderScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider AuthenticationProvider
+		want     AuthProvider
+	}{
+		{name: "provider id", provider: AuthenticationProvider{RstsProviderId: "ad.example.com"}, want: "rsts:sts:primaryproviderid:ad.example.com"},
+		{name: "scope", provider: AuthenticationProvider{RstsProviderId: "ignored", RstsProviderScope: "rsts:sts:primaryproviderid:radius"}, want: "rsts:sts:primaryproviderid:radius"},
+		{name: "local", provider: AuthenticationProvider{RstsProviderId: "local"}, want: AuthProviderLocal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.provider.AuthProvider(); got != tt.want {
+				t.Errorf("AuthProvider() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginWithProviderPasswordGrant(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+	ctx := context.Background()
+
+	if err := client.LoginWithProvider(ctx, ProviderScope("ad.example.com"), "dave", "dave-password", nil); err != nil {
+		t.Fatalf("LoginWithProvider() error = %v", err)
+	}
+	if f.scope != "rsts:sts:primaryproviderid:ad.example.com" {
+		t.Errorf("unexpected scope %q", f.scope)
+	}
+	if name, err := me(ctx, client); err != nil || name != "dave" {
+		t.Errorf("expected dave, got %q (%v)", name, err)
+	}
+}
+
+func TestLoginWithProviderChallenges(t *testing.T) {
+	tests := []struct {
+		name           string
+		username       string
+		password       string
+		answers        []string
+		wantChallenges []string
+		wantErr        bool
+	}{
+		{name: "no secondary authentication", username: "dave", password: "dave-password"},
+		{
+			name:           "one challenge",
+			username:       "carol",
+			password:       "carol-password",
+			answers:        []string{"111111"},
+			wantChallenges: []string{"Enter one-time password 1"},
+		},
+		{
+			name:           "two challenges",
+			username:       "erin",
+			password:       "erin-password",
+			answers:        []string{"111111", "222222"},
+			wantChallenges: []string{"Enter one-time password 1", "Enter one-time password 2"},
+		},
+		{
+			name:           "wrong answer",
+			username:       "carol",
+			password:       "carol-password",
+			answers:        []string{"000000"},
+			wantChallenges: []string{"Enter one-time password 1"},
+			wantErr:        true,
+		},
+		{name: "wrong password", username: "carol", password: "wrong", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeAuthServer(t, 3600, true)
+			f.fakeLoginController(t, map[string][]string{
+				"carol": {"111111"},
+				"erin":  {"111111", "222222"},
+			})
+			client := newSessionTestClient(t, f)
+			ctx := context.Background()
+
+			var challenges []string
+			handler := ChallengeHandlerFunc(func(ctx context.Context, c MFAChallenge) (string, error) {
+				if c.Username != tt.username || c.Attempt != len(challenges)+1 {
+					t.Errorf("unexpected challenge %+v", c)
+				}
+				challenges = append(challenges, c.Message)
+				return tt.answers[len(challenges)-1], nil
+			})
+
+			err := client.LoginWithProvider(ctx, ProviderScope("ad.example.com"), tt.username, tt.password, handler)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoginWithProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if fmt.Sprint(challenges) != fmt.Sprint(tt.wantChallenges) {
+				t.Errorf("got challenges %q, want %q", challenges, tt.wantChallenges)
+			}
+			if tt.wantErr {
+				return
+			}
+			if name, err := me(ctx, client); err != nil || name != tt.username {
+				t.Errorf("expected %s, got %q (%v)", tt.username, name, err)
+			}
+		})
+	}
+}
+
+func TestLoginWithProviderChallengeCancelled(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	f.fakeLoginController(t, map[string][]string{"carol": {"111111"}})
+	client := newSessionTestClient(t, f)
+
+	errCancelled := errors.New("user cancelled")
+	err := client.LoginWithProvider(context.Background(), ProviderScope("ad.example.com"), "carol", "carol-password",
+		ChallengeHandlerFunc(func(context.Context, MFAChallenge) (string, error) {
+			return "", errCancelled
+		}))
+	if !errors.Is(err, errCancelled) {
+		t.Errorf("expected the error of the handler, got %v", err)
+	}
+	if got := f.loginCount(); got != 0 {
+		t.Errorf("expected no login, got %d", got)
+	}
+}
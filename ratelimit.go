@@ -0,0 +1,203 @@
+package safeguard
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimit caps the rate and concurrency of one class of API requests.
+// A zero value imposes no limits.
+//
+// Example:
+//
+//	// At most 20 reads per second and 10 in flight
+//	client.SetReadLimit(safeguard.RateLimit{RequestsPerSecond: 20, MaxConcurrent: 10})
+type RateLimit struct {
+	RequestsPerSecond float64 // Sustained request rate (0 means unlimited)
+	Burst             int     // Requests allowed at once above the rate (defaults to RequestsPerSecond, at least 1)
+	MaxConcurrent     int     // Maximum number of requests in flight (0 means unlimited)
+}
+
+// requestLimiter enforces a RateLimit.
+type requestLimiter struct {
+	rate  *rate.Limiter
+	slots chan struct{}
+}
+
+// newRequestLimiter creates a limiter for the given limits.
+//
+// Returns:
+//   - *requestLimiter: The limiter, or nil if the limits impose no restrictions.
+func newRequestLimiter(l RateLimit) *requestLimiter {
+	if l.RequestsPerSecond <= 0 && l.MaxConcurrent <= 0 {
+		return nil
+	}
+
+	limiter := &requestLimiter{}
+	if l.RequestsPerSecond > 0 {
+		burst := l.Burst
+		if burst <= 0 {
+			burst = max(1, int(math.Ceil(l.RequestsPerSecond)))
+		}
+		limiter.rate = rate.NewLimiter(rate.Limit(l.RequestsPerSecond), burst)
+	}
+	if l.MaxConcurrent > 0 {
+		limiter.slots = make(chan struct{}, l.MaxConcurrent)
+	}
+	return limiter
+}
+
+// acquire blocks until the request may be sent or the context is done.
+// A nil limiter never blocks.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//
+// Returns:
+//   - func(): Releases the concurrency slot once the response has been read.
+//   - error: The context error if the context ended while waiting.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	release, err := l.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if l != nil && l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// acquireSlot blocks until a concurrency slot is free or the context is done.
+// Unlike acquire it does not count against the request rate.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//
+// Returns:
+//   - func(): Releases the slot; calling it more than once has no effect.
+//   - error: The context error if the context ended while waiting.
+func (l *requestLimiter) acquireSlot(ctx context.Context) (func(), error) {
+	if l == nil || l.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return sync.OnceFunc(func() { <-l.slots }), nil
+}
+
+// limitedBody is a streamed response body that holds the concurrency slot of
+// its request until it is closed, as the response is still being received.
+type limitedBody struct {
+	io.ReadCloser
+
+	ctx     context.Context
+	limiter *requestLimiter
+	release func() // Releases the slot; nil while it is lent out
+	err     error  // Error of reacquiring the slot
+}
+
+// Read reads from the body, failing if the slot could not be reacquired.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.ReadCloser.Read(p)
+}
+
+// Close closes the body and releases its concurrency slot.
+func (b *limitedBody) Close() error {
+	if b.release != nil {
+		b.release()
+		b.release = nil
+	}
+	return b.ReadCloser.Close()
+}
+
+// lendSlot runs fn without holding the concurrency slot of body, so fn may send
+// further requests even if MaxConcurrent is reached, e.g. from the loop body of
+// Stream. Unless fn stops the reading, the slot is reacquired before body is
+// read again.
+//
+// Parameters:
+//   - body: The body passed to a bodyReader.
+//   - fn: The function to run.
+//
+// Returns:
+//   - bool: The result of fn.
+func lendSlot(body io.Reader, fn func() bool) bool {
+	b, ok := body.(*limitedBody)
+	if !ok || b.release == nil {
+		return fn()
+	}
+
+	b.release()
+	b.release = nil
+	if !fn() {
+		return false
+	}
+	b.release, b.err = b.limiter.acquireSlot(b.ctx)
+	return true
+}
+
+// SetReadLimit limits requests sent to the appliance URL, which serves reads.
+// It must not be called concurrently with requests.
+//
+// Parameters:
+//   - l: The limits to apply. A zero RateLimit removes all limits.
+func (c *SafeguardClient) SetReadLimit(l RateLimit) {
+	c.readLimiter = newRequestLimiter(l)
+}
+
+// SetWriteLimit limits requests sent to the cluster leader URL, which handles writes.
+// It must not be called concurrently with requests.
+//
+// Parameters:
+//   - l: The limits to apply. A zero RateLimit removes all limits.
+func (c *SafeguardClient) SetWriteLimit(l RateLimit) {
+	c.writeLimiter = newRequestLimiter(l)
+}
+
+// limiterFor selects the limiter for a request based on the node it is sent to.
+// If the appliance is the cluster leader, the HTTP method decides instead.
+//
+// Parameters:
+//   - req: The request about to be sent.
+//
+// Returns:
+//   - *requestLimiter: The read or write limiter, which may be nil.
+func (c *SafeguardClient) limiterFor(req *http.Request) *requestLimiter {
+	if c.isWriteRequest(req) {
+		return c.writeLimiter
+	}
+	return c.readLimiter
+}
+
+// isWriteRequest reports whether the request targets the cluster leader.
+func (c *SafeguardClient) isWriteRequest(req *http.Request) bool {
+	leaderUrl := c.ClusterLeader.getUrl()
+	if leaderUrl != "" && leaderUrl != c.Appliance.getUrl() {
+		return strings.HasPrefix(req.URL.String(), leaderUrl+"/")
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
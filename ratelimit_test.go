@@ -0,0 +1,125 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitCapsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := maxInFlight.Load()
+			if n <= old || maxInFlight.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	})
+	client.SetReadLimit(RateLimit{MaxConcurrent: 2})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetRequest(context.Background(), "Assets"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", got)
+	}
+}
+
+func TestRateLimitCapsRequestRate(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	client.SetReadLimit(RateLimit{RequestsPerSecond: 20, Burst: 1})
+
+	start := time.Now()
+	for range 5 {
+		if _, err := client.GetRequest(context.Background(), "Assets"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// The first request uses the burst, the other four wait 50ms each
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("expected requests to be throttled, took only %v", elapsed)
+	}
+}
+
+func TestRateLimitHonoursContext(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	client.SetWriteLimit(RateLimit{MaxConcurrent: 1})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.DeleteRequest(context.Background(), "Assets/1")
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.DeleteRequest(ctx, "Assets/2")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded while waiting for a slot, got %v", err)
+	}
+
+	// Reads are limited separately and must not wait for the write slot
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	if _, err := client.GetRequest(context.Background(), "Assets"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	<-done
+}
+
+func TestIsWriteRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		appliance string
+		leader    string
+		method    string
+		url       string
+		want      bool
+	}{
+		{name: "read from appliance", appliance: "https://node1.example.com:443", leader: "https://node2.example.com:443", method: http.MethodGet, url: "https://node1.example.com:443/service/core/v4/Assets", want: false},
+		{name: "post to appliance", appliance: "https://node1.example.com:443", leader: "https://node2.example.com:443", method: http.MethodPost, url: "https://node1.example.com:443/service/core/v4/Assets/1/CheckPassword", want: false},
+		{name: "write to leader", appliance: "https://node1.example.com:443", leader: "https://node2.example.com:443", method: http.MethodPut, url: "https://node2.example.com:443/service/core/v4/Assets/1", want: true},
+		{name: "appliance is leader, GET", appliance: "https://node1.example.com:443", leader: "https://node1.example.com:443", method: http.MethodGet, url: "https://node1.example.com:443/service/core/v4/Assets", want: false},
+		{name: "appliance is leader, DELETE", appliance: "https://node1.example.com:443", leader: "https://node1.example.com:443", method: http.MethodDelete, url: "https://node1.example.com:443/service/core/v4/Assets/1", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &SafeguardClient{}
+			client.Appliance.setUrl(tt.appliance, -1)
+			client.ClusterLeader.setUrl(tt.leader, -1)
+
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := client.isWriteRequest(req); got != tt.want {
+				t.Errorf("isWriteRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
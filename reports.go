@@ -1,6 +1,7 @@
 package safeguard
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -109,18 +110,19 @@ func (a AccountTaskData) ToJson() (string, error) {
 //	schedules, err := GetAccountTaskSchedules(CheckPassword, filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - taskName: The type of account task to retrieve schedules for
 //   - filter: A Filter object containing field comparisons and ordering preferences
 //
 // Returns:
 //   - []AccountTaskData: A slice of task schedules matching the filter criteria
 //   - error: An error if the request or response parsing fails, nil otherwise
-func (c *SafeguardClient) GetAccountTaskSchedules(taskName TaskNames, filter Filter) ([]AccountTaskData, error) {
+func (c *SafeguardClient) GetAccountTaskSchedules(ctx context.Context, taskName TaskNames, filter Filter) ([]AccountTaskData, error) {
 	var accountTaskSchedules []AccountTaskData
 
 	query := fmt.Sprintf("Reports/Tasks/AccountTaskSchedules/%s%s", taskName, filter.ToQueryString())
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
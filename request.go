@@ -1,11 +1,16 @@
 package safeguard
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
 )
 
 // SafeHeaders is a wrapper around http.Header that implements slog.LogValuer
@@ -24,17 +29,7 @@ func (sh SafeHeaders) LogValue() slog.Value {
 			// Mask authorization header to prevent token leakage
 			maskedValues := make([]string, len(values))
 			for i, value := range values {
-				if strings.HasPrefix(strings.ToLower(value), "bearer ") && len(value) > 7 {
-					// Show only "Bearer " + first 6 and last 4 characters with asterisks in between
-					tokenPart := value[7:] // Remove "Bearer " prefix
-					if len(tokenPart) > 10 {
-						maskedValues[i] = fmt.Sprintf("Bearer %s***%s", tokenPart[:6], tokenPart[len(tokenPart)-4:])
-					} else {
-						maskedValues[i] = "Bearer ***"
-					}
-				} else {
-					maskedValues[i] = "***"
-				}
+				maskedValues[i] = maskAuthorization(value)
 			}
 			attrs = append(attrs, slog.Any(key, maskedValues))
 		} else {
@@ -46,6 +41,20 @@ func (sh SafeHeaders) LogValue() slog.Value {
 	return slog.GroupValue(attrs...)
 }
 
+// maskAuthorization masks the credentials in an Authorization header value.
+// Bearer tokens keep their first 6 and last 4 characters, all other values are
+// replaced completely.
+func maskAuthorization(value string) string {
+	if strings.HasPrefix(strings.ToLower(value), "bearer ") && len(value) > 7 {
+		tokenPart := value[7:] // Remove "Bearer " prefix
+		if len(tokenPart) > 10 {
+			return fmt.Sprintf("Bearer %s***%s", tokenPart[:6], tokenPart[len(tokenPart)-4:])
+		}
+		return "Bearer ***"
+	}
+	return "***"
+}
+
 // NewSafeHeaders creates a SafeHeaders wrapper for the given http.Header.
 func NewSafeHeaders(headers http.Header) SafeHeaders {
 	return SafeHeaders{headers: headers}
@@ -127,221 +136,318 @@ func (c *SafeguardClient) getReadOnlyRootUrl() string {
 }
 
 // getReadWriteRootUrl constructs and returns the root URL for write operations.
-// It ensures write operations are directed to the current cluster leader.
+// It ensures write operations are directed to the current cluster leader, or
+// to the appliance URL if the leader cannot be resolved.
+//
+// Parameters:
+//   - ctx: Context used if the cluster leader has to be looked up again.
 //
 // Returns:
 //   - string: The complete root URL for read-write API operations.
-func (c *SafeguardClient) getReadWriteRootUrl() string {
-	// If the cluster leader URL is not set, fall back to the appliance URL
-	return fmt.Sprintf("%s/service/core/%s", c.getClusterLeaderUrl(), c.ApiVersion)
+func (c *SafeguardClient) getReadWriteRootUrl(ctx context.Context) string {
+	leaderUrl := c.getClusterLeaderUrl(ctx)
+	if leaderUrl == "" {
+		// The leader could not be resolved, e.g. Cluster/Members is forbidden
+		c.logger().Warn("Cluster leader unknown, sending request to appliance URL")
+		return c.getReadOnlyRootUrl()
+	}
+	return fmt.Sprintf("%s/service/core/%s", leaderUrl, c.ApiVersion)
 }
 
 // GetRequest makes a GET request to the specified path on the Safeguard API.
-// It constructs the full URL by combining the read-only root URL with the provided path,
-// creates an HTTP GET request, and sends it using the client's HTTP configuration.
+// The request is sent to the node selected by the client's RoutingPolicy, by
+// default the appliance URL. With cluster failover enabled, connection errors
+// and 5xx responses are retried on the other cluster members, healthiest first.
 //
 // Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
 //   - path: The API endpoint path to append to the root URL.
 //
 // Returns:
 //   - []byte: The response body from the API call.
 //   - error: An error if the request fails or returns a non-successful status code.
-func (c *SafeguardClient) GetRequest(path string) ([]byte, error) {
-	url := fmt.Sprintf("%s/%s", c.getReadOnlyRootUrl(), path)
-	logger.Debug("Preparing GET request",
-		"url", url,
-		"path", path,
-	)
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		logger.Error("Failed to create GET request",
-			"error", err,
-			"url", url,
-		)
-		return nil, err
-	}
-
-	return c.sendHttpRequest(req)
+func (c *SafeguardClient) GetRequest(ctx context.Context, path string) ([]byte, error) {
+	return responseBody(c.routedRequest(ctx, http.MethodGet, path, nil, nil))
 }
 
 // PostRequest sends an HTTP POST request to the specified path with the provided body.
-// It automatically handles authentication, request routing, and response processing.
+// The request is sent to the node selected by the client's RoutingPolicy, by
+// default the appliance URL. The body is buffered, so it can be sent again
+// safely if the first attempt provably never reached the server.
 //
 // Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
 //   - path: The endpoint path to which the request will be sent.
 //   - body: The request body data as an io.Reader.
 //
 // Returns:
 //   - []byte: The response body from the API call.
 //   - error: An error if the request fails or returns a non-successful status code.
-func (c *SafeguardClient) PostRequest(path string, body io.Reader) ([]byte, error) {
-
-	// Use the read-only root URL for POST requests
-	// Some Customers hide the cluster leader behind the firewall and only
-	// allow access to the appliance through an load balancer that is only
-	// accessible thru the appliance URL
-	// This is a workaround to allow POST requests to work in this scenario
-
-	url := fmt.Sprintf("%s/%s", c.getReadOnlyRootUrl(), path)
-	logger.Debug("Preparing POST request",
-		"url", url,
-		"path", path,
-	)
-
-	req, err := http.NewRequest(http.MethodPost, url, body)
-	if err != nil {
-		logger.Error("Failed to create POST request",
-			"error", err,
-			"url", url,
-		)
-		return nil, err
-	}
-
-	result, err := c.sendHttpRequest(req)
-	if err == nil {
-		logger.Debug("POST request successful",
-			"method", req.Method,
-			"url", url,
-			"responseBody", NewSafeResponseBody(result, path),
-		)
-		return result, nil
-	}
-
-	logger.Debug("POST request failed on read-only URL, retrying on read-write URL",
-		"url", url,
-		"path", path,
-	)
-
-	url = fmt.Sprintf("%s/%s", c.getReadWriteRootUrl(), path)
-	req, err = http.NewRequest(http.MethodPost, url, body)
-	if err != nil {
-		logger.Error("Failed to create POST request on read-write URL",
-			"error", err,
-			"url", url,
-		)
-		return nil, fmt.Errorf("POST request failed on read-write URL: %v", err)
-	}
-
-	result, err = c.sendHttpRequest(req)
-	if err == nil {
-		logger.Debug("POST request successful on read-write URL",
-			"method", req.Method,
-			"url", url,
-			"responseBody", NewSafeResponseBody(result, path),
-		)
-		return result, nil
-	}
-
-	logger.Error("POST request failed on read-write URL",
-		"url", url,
-		"path", path,
-	)
-	return nil, fmt.Errorf("POST request failed on read-write URL: %v", err)
+func (c *SafeguardClient) PostRequest(ctx context.Context, path string, body io.Reader) ([]byte, error) {
+	return responseBody(c.routedRequest(ctx, http.MethodPost, path, body, nil))
 }
 
 // PutRequest sends an HTTP PUT request to update resources on the Safeguard API.
-// It automatically handles authentication and routes requests through the cluster leader.
+// The request is sent to the node selected by the client's RoutingPolicy, by
+// default the cluster leader. With cluster failover enabled, a failure caused by
+// the leader makes the client resolve the leader again for the next write.
 //
 // Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
 //   - path: The endpoint path for the resource to update.
 //   - body: The request body containing the update data.
 //
 // Returns:
 //   - []byte: The response body from the API call.
 //   - error: An error if the request fails.
-func (c *SafeguardClient) PutRequest(path string, body io.Reader) ([]byte, error) {
-	url := fmt.Sprintf("%s/%s", c.getReadWriteRootUrl(), path)
-	logger.Debug("Preparing PUT request",
-		"url", url,
-		"path", path,
-	)
-
-	req, err := http.NewRequest(http.MethodPut, url, body)
-	if err != nil {
-		logger.Error("Failed to create PUT request",
-			"error", err,
-			"url", url,
-		)
-		return nil, err
-	}
-
-	return c.sendHttpRequest(req)
+func (c *SafeguardClient) PutRequest(ctx context.Context, path string, body io.Reader) ([]byte, error) {
+	return responseBody(c.routedRequest(ctx, http.MethodPut, path, body, nil))
 }
 
 // DeleteRequest sends an HTTP DELETE request to remove resources.
-// It ensures proper routing through the cluster leader for consistency.
+// The request is sent to the node selected by the client's RoutingPolicy, by
+// default the cluster leader.
 //
 // Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
 //   - path: The endpoint path identifying the resource to delete.
 //
 // Returns:
 //   - []byte: The response body if any.
 //   - error: An error if the deletion fails.
-func (c *SafeguardClient) DeleteRequest(path string) ([]byte, error) {
-	url := fmt.Sprintf("%s/%s", c.getReadWriteRootUrl(), path)
-	logger.Debug("Preparing DELETE request",
-		"url", url,
-		"path", path,
-	)
-
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
-	if err != nil {
-		logger.Error("Failed to create DELETE request",
-			"error", err,
-			"url", url,
-		)
-		return nil, err
-	}
-
-	return c.sendHttpRequest(req)
+func (c *SafeguardClient) DeleteRequest(ctx context.Context, path string) ([]byte, error) {
+	return responseBody(c.routedRequest(ctx, http.MethodDelete, path, nil, nil))
 }
 
 // sendHttpRequest handles the common logic for sending HTTP requests to the Safeguard API.
 // It sets necessary headers, performs the request, and processes the response.
 // Successful responses are considered to be those with status codes 200 (OK),
-// 201 (Created), or 202 (Accepted).
+// 201 (Created), or 202 (Accepted). Transient failures are retried according
+// to the client's RetryPolicy, honouring a Retry-After header sent by the appliance.
 //
 // Parameters:
 //   - req: A pointer to an http.Request object representing the prepared HTTP request.
 //
 // Returns:
 //   - []byte: The response body if the request is successful.
-//   - error: An error if the request fails, returns a non-successful status code,
-//     or if there are issues reading the response body.
+//   - error: An error if the request fails, an *APIError for a non-successful status code,
+//     or an error if there are issues reading the response body.
 //
 // The function handles logging of request details at debug level and any errors
 // that occur during the request processing.
 func (c *SafeguardClient) sendHttpRequest(req *http.Request) ([]byte, error) {
+	return responseBody(c.sendRequest(req, nil))
+}
+
+// sendRequest sends a request like sendHttpRequest and returns the complete response.
+// If the appliance rejects the token with 401 (Unauthorized) and the last login can
+// be repeated, the client logs in again and sends the request once more.
+//
+// Parameters:
+//   - req: The prepared HTTP request.
+//   - read: Consumes the body of a successful response while it is received, or
+//     nil to buffer it in Response.Body.
+//
+// Returns:
+//   - *Response: The status, headers and body of the last response, or nil if no
+//     response was received. The body is only set for successful responses.
+//   - error: An error if the request fails or returns a non-successful status code,
+//     or ErrClientClosed if the client was closed.
+func (c *SafeguardClient) sendRequest(req *http.Request, read bodyReader) (*Response, error) {
+	if err := c.beginRequest(); err != nil {
+		return nil, err
+	}
+	defer c.endRequest()
+
+	if err := c.ensureToken(req.Context()); err != nil {
+		return nil, err
+	}
 	c.setHeaders(req)
-	logger.Debug("Sending request",
+
+	ctx, end := c.instrumentation().StartAPICall(req.Context(), c.newAPICall(req))
+	req = req.WithContext(ctx)
+
+	start := time.Now()
+	generation := c.session.generation.Load()
+	resp, attempts, err := c.sendWithRetries(req, read)
+
+	if IsUnauthorized(err) && c.canRefresh() {
+		resp, attempts, err = c.retryAfterReauthentication(req, read, generation, resp, attempts, err)
+	}
+
+	result := APICallResult{Attempts: attempts, Duration: time.Since(start), Err: err}
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+	}
+	end(result)
+
+	return resp, err
+}
+
+// retryAfterReauthentication renews the token after a request was rejected with
+// 401 (Unauthorized) and sends the request again with the new token. Concurrent
+// requests rejected with the same token share a single login.
+//
+// Parameters:
+//   - req: The rejected request.
+//   - read: Consumes the body of a successful response, or nil to buffer it.
+//   - generation: The token generation the request was sent with.
+//   - resp, attempts, err: The result of the rejected request.
+//
+// Returns:
+//   - *Response: The response of the repeated request, or resp if the token could not be renewed.
+//   - int: The number of attempts made in total.
+//   - error: The error of the repeated request, or err if the token could not be renewed.
+func (c *SafeguardClient) retryAfterReauthentication(req *http.Request, read bodyReader, generation uint64, resp *Response, attempts int, err error) (*Response, int, error) {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, attempts, err
+	}
+
+	c.logger().Info("Token rejected, logging in again", "method", req.Method, "url", req.URL)
+	if loginErr := c.reauthenticate(req.Context(), generation); loginErr != nil {
+		return resp, attempts, err
+	}
+
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return nil, attempts, fmt.Errorf("failed to rewind request body: %w", bodyErr)
+		}
+		req.Body = body
+	}
+	c.setHeaders(req)
+
+	resp, retries, err := c.sendWithRetries(req, read)
+	return resp, attempts + retries, err
+}
+
+// sendWithRetries sends a request and retries it according to the client's RetryPolicy.
+//
+// A response body that failed while being read is never retried, since read may
+// already have consumed part of it.
+//
+// Parameters:
+//   - req: The prepared HTTP request including all headers.
+//   - read: Consumes the body of a successful response, or nil to buffer it.
+//
+// Returns:
+//   - *Response: The last response, or nil if no response was received.
+//   - int: The number of attempts made.
+//   - error: An error if the request fails or returns a non-successful status code.
+func (c *SafeguardClient) sendWithRetries(req *http.Request, read bodyReader) (*Response, int, error) {
+	if c.RetryPolicy == nil {
+		resp, err := newResponse(c.doHttpRequest(req, read))
+		return resp, 1, err
+	}
+
+	start := time.Now()
+	bo := c.RetryPolicy.newBackOff(req.Context())
+	for attempt := 1; ; attempt++ {
+		resp, body, err := c.doHttpRequest(req, read)
+		if err == nil {
+			r, err := newResponse(resp, body, nil)
+			return r, attempt, err
+		}
+
+		statusCode, transportErr := 0, err
+		var header http.Header
+		if resp != nil {
+			statusCode, header, transportErr = resp.StatusCode, resp.Header, nil
+		}
+		if !c.RetryPolicy.shouldRetry(req, statusCode, transportErr) {
+			r, err := newResponse(resp, nil, err)
+			return r, attempt, err
+		}
+
+		wait := bo.NextBackOff()
+		if wait == backoff.Stop {
+			r, err := newResponse(resp, nil, err)
+			return r, attempt, err
+		}
+		wait = c.RetryPolicy.retryWait(wait, retryAfter(header), time.Since(start))
+
+		c.logger().Warn("Retrying request after transient failure",
+			"method", req.Method,
+			"url", req.URL,
+			"attempt", attempt,
+			"statusCode", statusCode,
+			"wait", wait,
+			"error", err,
+		)
+
+		if waitErr := waitForRetry(req.Context(), wait); waitErr != nil {
+			r, err := newResponse(resp, nil, err)
+			return r, attempt, err
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, attempt, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+		}
+	}
+}
+
+// doHttpRequest performs a single attempt of an HTTP request through the client's
+// middleware chain and reads the response.
+//
+// When read is set, the body of a successful response is passed to it instead of
+// being buffered. The concurrency slot of the rate limiter is held until the
+// body is closed; read may lend it out with lendSlot to send further API requests.
+//
+// Parameters:
+//   - req: The prepared HTTP request including all headers.
+//   - read: Consumes the body of a successful response, or nil to buffer it.
+//
+// Returns:
+//   - *http.Response: The response (with its body already consumed), or nil if no
+//     complete response was received.
+//   - []byte: The response body if the request is successful and read is nil.
+//   - error: A transport error if resp is nil, an *APIError for a non-successful
+//     status code, or the error returned by read.
+func (c *SafeguardClient) doHttpRequest(req *http.Request, read bodyReader) (*http.Response, []byte, error) {
+	c.logger().Debug("Sending request",
 		"method", req.Method,
 		"url", req.URL.String(),
 		"headers", NewSafeHeaders(req.Header),
 	)
 
-	resp, err := c.HttpClient.Do(req)
+	limiter := c.limiterFor(req)
+	release, err := limiter.acquire(req.Context())
 	if err != nil {
-		logger.Error("Request failed",
+		return nil, nil, fmt.Errorf("request failed: %w", err)
+	}
+	release = sync.OnceFunc(release)
+	defer release()
+
+	resp, err := c.doer().Do(req)
+	if err != nil {
+		c.logger().Error("Request failed",
 			"error", err,
 			"method", req.Method,
 			"url", req.URL,
 		)
-		return nil, fmt.Errorf("request failed: %v", err)
+		return nil, nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if read != nil && isSuccessStatus(resp.StatusCode) {
+		body := &limitedBody{ReadCloser: resp.Body, ctx: req.Context(), limiter: limiter, release: release}
+		defer body.Close()
+		return resp, nil, c.streamResponse(req, resp, body, read)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		logger.Error("Failed to read response body",
+		c.logger().Error("Failed to read response body",
 			"error", err,
 			"method", req.Method,
 			"url", req.URL,
 		)
-		return nil, err
+		return nil, nil, err
 	}
 
-	logger.Debug("Response received",
+	c.logger().Debug("Response received",
 		"method", req.Method,
 		"url", req.URL,
 		"status", resp.Status,
@@ -350,8 +456,8 @@ func (c *SafeguardClient) sendHttpRequest(req *http.Request) ([]byte, error) {
 		"headers", NewSafeHeaders(resp.Header),
 	)
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusCreated {
-		logger.Error("Request failed with non-success status code",
+	if !isSuccessStatus(resp.StatusCode) {
+		c.logger().Error("Request failed with non-success status code",
 			"method", req.Method,
 			"url", req.URL,
 			"status", resp.Status,
@@ -359,17 +465,53 @@ func (c *SafeguardClient) sendHttpRequest(req *http.Request) ([]byte, error) {
 			"responseHeaders", NewSafeHeaders(resp.Header),
 			"responseBody", NewSafeResponseBody(body, req.URL.Path),
 		)
-		return nil, fmt.Errorf("error during %s request to %s: HTTP %d - %s", req.Method, req.URL, resp.StatusCode, string(body))
+		return resp, nil, newAPIError(req, resp.StatusCode, body)
 	}
 
-	logger.Debug("Request completed successfully",
+	c.logger().Debug("Request completed successfully",
 		"method", req.Method,
 		"url", req.URL,
 		"statusCode", resp.StatusCode,
 		"bodyLength", len(body),
 	)
 
-	return body, nil
+	return resp, body, nil
+}
+
+// streamResponse passes the body of a successful response to read.
+//
+// Parameters:
+//   - req: The request that was sent.
+//   - resp: The successful response.
+//   - body: The unread body of resp, holding the concurrency slot of the request.
+//   - read: Consumes the body.
+//
+// Returns:
+//   - error: A *bodyError wrapping the error of read, or nil.
+func (c *SafeguardClient) streamResponse(req *http.Request, resp *http.Response, body *limitedBody, read bodyReader) error {
+	c.logger().Debug("Streaming response",
+		"method", req.Method,
+		"url", req.URL,
+		"status", resp.Status,
+		"statusCode", resp.StatusCode,
+		"headers", NewSafeHeaders(resp.Header),
+	)
+
+	if err := read(body); err != nil {
+		c.logger().Error("Failed to read response body",
+			"error", err,
+			"method", req.Method,
+			"url", req.URL,
+		)
+		return &bodyError{err: err}
+	}
+	return nil
+}
+
+// isSuccessStatus reports whether an API response status code means success:
+// 200 (OK), 201 (Created) or 202 (Accepted).
+func isSuccessStatus(statusCode int) bool {
+	return statusCode == http.StatusOK || statusCode == http.StatusAccepted || statusCode == http.StatusCreated
 }
 
 // setHeaders configures the HTTP request headers for Safeguard API requests.
@@ -384,7 +526,7 @@ func (c *SafeguardClient) sendHttpRequest(req *http.Request) ([]byte, error) {
 // The function modifies the request headers in place and ensures all necessary
 // headers are present for successful API communication.
 func (c *SafeguardClient) setHeaders(req *http.Request) {
-	logger.Debug("Setting request headers", "existingHeaders", NewSafeHeaders(req.Header))
+	c.logger().Debug("Setting request headers", "existingHeaders", NewSafeHeaders(req.Header))
 
 	req.Header = c.getAuthorizationHeader()
 
@@ -397,7 +539,7 @@ func (c *SafeguardClient) setHeaders(req *http.Request) {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	logger.Debug("Headers set successfully",
+	c.logger().Debug("Headers set successfully",
 		"finalHeaders", NewSafeHeaders(req.Header),
 		"method", req.Method,
 		"url", req.URL,
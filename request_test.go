@@ -0,0 +1,105 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestServerClient(t *testing.T, handler http.HandlerFunc) *SafeguardClient {
+	t.Helper()
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	client := &SafeguardClient{
+		AccessToken: &RSTSAuthResponse{
+			UserToken: "test-user-token",
+		},
+		HttpClient: ts.Client(),
+		ApiVersion: "v4",
+	}
+	client.Appliance.setUrl(ts.URL, -1)
+	client.ClusterLeader.setUrl(ts.URL, -1)
+	return client
+}
+
+func TestGetRequestHonoursContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetRequest(ctx, "Assets")
+	if err == nil {
+		t.Fatal("expected error for cancelled context, got nil")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request was not cancelled in time, took %v", elapsed)
+	}
+}
+
+func TestRequestMethodsHonourCancelledContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		call   func(ctx context.Context, c *SafeguardClient) ([]byte, error)
+	}{
+		{
+			name:   "GET",
+			method: http.MethodGet,
+			call: func(ctx context.Context, c *SafeguardClient) ([]byte, error) {
+				return c.GetRequest(ctx, "Assets")
+			},
+		},
+		{
+			name:   "PUT",
+			method: http.MethodPut,
+			call: func(ctx context.Context, c *SafeguardClient) ([]byte, error) {
+				return c.PutRequest(ctx, "Assets/1", nil)
+			},
+		},
+		{
+			name:   "DELETE",
+			method: http.MethodDelete,
+			call: func(ctx context.Context, c *SafeguardClient) ([]byte, error) {
+				return c.DeleteRequest(ctx, "Assets/1")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.method {
+					t.Errorf("expected method %s, got %s", tt.method, r.Method)
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+
+			if _, err := tt.call(context.Background(), client); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if _, err := tt.call(ctx, client); !errors.Is(err, context.Canceled) {
+				t.Errorf("expected context.Canceled, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// RetryPolicy configures how the client retries requests that failed with a
+// transient error, such as a dropped connection, HTTP 429 (Too Many Requests)
+// or HTTP 503 during appliance maintenance.
+//
+// Idempotent requests (GET, HEAD, PUT, DELETE, OPTIONS) are retried by default.
+// POST requests are only retried when RetryPost is set, because the appliance
+// may already have executed a POST whose response was lost.
+//
+// Example:
+//
+//	policy := safeguard.DefaultRetryPolicy()
+//	policy.MaxRetries = 5
+//	client.RetryPolicy = &policy
+type RetryPolicy struct {
+	MaxRetries           int           // Maximum number of retries after the first attempt
+	InitialInterval      time.Duration // Wait time before the first retry
+	MaxInterval          time.Duration // Upper bound for the wait time between retries, also for Retry-After
+	Multiplier           float64       // Factor the wait time grows by after each retry
+	RandomizationFactor  float64       // Jitter applied to each wait time (0 disables jitter)
+	MaxElapsedTime       time.Duration // Total time after which no further retries are made (0 means no limit)
+	RetryableStatusCodes []int         // HTTP status codes that are considered transient
+	RetryPost            bool          // Also retry POST requests
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient.
+// It retries idempotent requests up to three times with exponential backoff
+// and jitter on connection errors and HTTP 429, 502, 503 and 504.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:          3,
+		InitialInterval:     500 * time.Millisecond,
+		MaxInterval:         10 * time.Second,
+		Multiplier:          2,
+		RandomizationFactor: 0.5,
+		MaxElapsedTime:      1 * time.Minute,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// newBackOff creates the backoff schedule for a single request.
+func (p RetryPolicy) newBackOff(ctx context.Context) backoff.BackOff {
+	bo := backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(p.InitialInterval),
+		backoff.WithMaxInterval(p.MaxInterval),
+		backoff.WithMultiplier(p.Multiplier),
+		backoff.WithRandomizationFactor(p.RandomizationFactor),
+		backoff.WithMaxElapsedTime(p.MaxElapsedTime),
+	)
+	return backoff.WithContext(backoff.WithMaxRetries(bo, uint64(p.MaxRetries)), ctx)
+}
+
+// allowsMethod reports whether requests with the given HTTP method may be retried.
+func (p RetryPolicy) allowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return p.RetryPost
+	default:
+		return false
+	}
+}
+
+// shouldRetry decides whether a failed attempt is worth retrying.
+//
+// Parameters:
+//   - req: The request that was sent.
+//   - statusCode: The HTTP status code of the response, or 0 if no response was received.
+//   - err: The transport error, or nil if a response was received.
+//
+// Returns:
+//   - bool: true if the request should be sent again.
+func (p RetryPolicy) shouldRetry(req *http.Request, statusCode int, err error) bool {
+	// Requests that are not idempotent are only sent again if the first
+	// attempt provably never reached the server
+	if !p.allowsMethod(req.Method) && !neverReachedServer(err) {
+		return false
+	}
+
+	// A request body can only be sent again if it can be recreated
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		// Never retry when the caller gave up
+		return req.Context().Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return slices.Contains(p.RetryableStatusCodes, statusCode)
+}
+
+// retryAfter parses the Retry-After header of a response.
+// The header may either contain a number of seconds or an HTTP date.
+//
+// Parameters:
+//   - header: The response headers.
+//
+// Returns:
+//   - time.Duration: The requested wait time, or 0 if the header is missing or invalid.
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
+
+// retryWait returns the time to wait before the next retry. A longer wait
+// requested with Retry-After is honoured up to MaxInterval and the time left
+// of MaxElapsedTime, so a server cannot stall a request indefinitely.
+//
+// Parameters:
+//   - scheduled: The wait time of the backoff schedule.
+//   - requested: The wait time requested by the Retry-After header, or 0.
+//   - elapsed: The time spent on the request so far.
+//
+// Returns:
+//   - time.Duration: The time to wait.
+func (p RetryPolicy) retryWait(scheduled, requested, elapsed time.Duration) time.Duration {
+	if p.MaxInterval > 0 {
+		requested = min(requested, p.MaxInterval)
+	}
+	if p.MaxElapsedTime > 0 {
+		requested = min(requested, p.MaxElapsedTime-elapsed)
+	}
+	return max(scheduled, requested)
+}
+
+// waitForRetry blocks for the given duration or until the context is done.
+//
+// Returns:
+//   - error: The context error if the context ended before the wait was over.
+func waitForRetry(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,228 @@
+package safeguard
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialInterval = time.Millisecond
+	policy.MaxInterval = 5 * time.Millisecond
+	return &policy
+}
+
+func TestRetryOnTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"Id":1}`))
+	})
+	client.RetryPolicy = testRetryPolicy()
+
+	body, err := client.GetRequest(context.Background(), "Assets/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != `{"Id":1}` {
+		t.Errorf("unexpected body: %s", body)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRetryGivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	client.RetryPolicy = testRetryPolicy()
+	client.RetryPolicy.MaxRetries = 2
+
+	_, err := client.GetRequest(context.Background(), "Assets")
+	if !hasStatus(err, http.StatusTooManyRequests) {
+		t.Fatalf("expected HTTP 429 APIError, got %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRetryDoesNotRetryNonTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	client.RetryPolicy = testRetryPolicy()
+
+	_, err := client.GetRequest(context.Background(), "Assets/1")
+	if !IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRetryPostRequiresOptIn(t *testing.T) {
+	tests := []struct {
+		name      string
+		retryPost bool
+		wantCalls int32
+	}{
+		{name: "POST not retried by default", retryPost: false, wantCalls: 1},
+		{name: "POST retried when enabled", retryPost: true, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			var bodies []string
+			client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				b, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(b))
+				w.WriteHeader(http.StatusServiceUnavailable)
+			})
+			client.RetryPolicy = testRetryPolicy()
+			client.RetryPolicy.MaxRetries = 1
+			client.RetryPolicy.RetryPost = tt.retryPost
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+				client.getReadOnlyRootUrl()+"/Assets", bytes.NewReader([]byte(`{"Name":"test"}`)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.sendHttpRequest(req); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("expected %d attempts, got %d", tt.wantCalls, got)
+			}
+			for i, b := range bodies {
+				if b != `{"Name":"test"}` {
+					t.Errorf("attempt %d sent body %q", i+1, b)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenContextIsCancelled(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.RetryPolicy = testRetryPolicy()
+	client.RetryPolicy.MaxInterval = time.Minute // Honour the full Retry-After
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetRequest(ctx, "Assets")
+	if !hasStatus(err, http.StatusServiceUnavailable) {
+		t.Fatalf("expected HTTP 503 APIError, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("retry did not stop on context cancellation, took %v", elapsed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{name: "missing header", value: "", min: 0, max: 0},
+		{name: "seconds", value: "5", min: 5 * time.Second, max: 5 * time.Second},
+		{name: "invalid value", value: "soon", min: 0, max: 0},
+		{name: "http date", value: time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), min: 8 * time.Second, max: 10 * time.Second},
+		{name: "http date in the past", value: time.Now().Add(-10 * time.Second).UTC().Format(http.TimeFormat), min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+			got := retryAfter(header)
+			if got < tt.min || got > tt.max {
+				t.Errorf("retryAfter(%q) = %v, want between %v and %v", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	policy := RetryPolicy{MaxInterval: 10 * time.Second, MaxElapsedTime: time.Minute}
+
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		scheduled time.Duration
+		requested time.Duration
+		elapsed   time.Duration
+		want      time.Duration
+	}{
+		{name: "no Retry-After", policy: policy, scheduled: time.Second, want: time.Second},
+		{name: "shorter Retry-After", policy: policy, scheduled: 2 * time.Second, requested: time.Second, want: 2 * time.Second},
+		{name: "longer Retry-After", policy: policy, scheduled: time.Second, requested: 5 * time.Second, want: 5 * time.Second},
+		{name: "capped by MaxInterval", policy: policy, scheduled: time.Second, requested: time.Hour, want: 10 * time.Second},
+		{name: "capped by MaxElapsedTime", policy: policy, scheduled: time.Second, requested: time.Hour, elapsed: 55 * time.Second, want: 5 * time.Second},
+		{name: "no limits", policy: RetryPolicy{}, scheduled: time.Second, requested: time.Hour, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.retryWait(tt.scheduled, tt.requested, tt.elapsed); got != tt.want {
+				t.Errorf("retryWait() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterIsCapped(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+	client.RetryPolicy = testRetryPolicy()
+
+	start := time.Now()
+	if _, err := client.GetRequest(context.Background(), "Assets"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Retry-After was not capped, took %v", elapsed)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+}
@@ -2,8 +2,10 @@ package safeguard
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"time"
 )
 
@@ -95,17 +97,18 @@ type HourlyRestrictionProperties struct {
 //	roles, err := GetRoles(filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: Filter object containing field comparisons and ordering preferences
 //
 // Returns:
 //   - []Role: A slice of roles matching the filter criteria
 //   - error: An error if the request fails, nil otherwise
-func (c *SafeguardClient) GetRoles(fields Filter) ([]Role, error) {
+func (c *SafeguardClient) GetRoles(ctx context.Context, fields Filter) ([]Role, error) {
 	var userRoles []Role
 
 	query := "Roles" + fields.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -117,13 +120,49 @@ func (c *SafeguardClient) GetRoles(fields Filter) ([]Role, error) {
 	return addClientToSlice(c, userRoles), nil
 }
 
+// Roles returns an iterator over all roles matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.Roles(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[Role, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) Roles(ctx context.Context, filter Filter) iter.Seq2[Role, error] {
+	return paginate[Role](ctx, c, "Roles", filter)
+}
+
+// CountRoles returns the total number of roles matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching roles
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountRoles(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "Roles", filter)
+}
+
 // Delete removes the role identified by the Role's Id from the system.
 // It sends a DELETE request to the API endpoint corresponding to the role's Id.
 // If the request fails, it returns an error.
-func (r Role) Delete() error {
+func (r Role) Delete(ctx context.Context) error {
 	query := fmt.Sprintf("Roles/%d", r.Id)
 
-	_, err := r.apiClient.DeleteRequest(query)
+	_, err := r.apiClient.DeleteRequest(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -140,20 +179,21 @@ func (r Role) Delete() error {
 //
 // Parameters:
 //
+//	ctx - Context for cancellation and timeouts.
 //	updatedRole - The Role object containing the updated role data.
 //
 // Returns:
 //
 //	Role - The updated Role object with the API client added.
 //	error - An error if any occurred during the update process.
-func (r Role) Update(updatedRole Role) (Role, error) {
+func (r Role) Update(ctx context.Context, updatedRole Role) (Role, error) {
 	query := fmt.Sprintf("Roles/%d", r.Id)
 
 	updatedRoleJSON, err := json.Marshal(updatedRole)
 	if err != nil {
 		return Role{}, err
 	}
-	response, err := r.apiClient.PutRequest(query, bytes.NewReader(updatedRoleJSON))
+	response, err := r.apiClient.PutRequest(ctx, query, bytes.NewReader(updatedRoleJSON))
 	if err != nil {
 		return Role{}, err
 	}
@@ -170,6 +210,7 @@ func (r Role) Update(updatedRole Role) (Role, error) {
 // (add or remove) on the provided identities.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - operation: The operation to perform (e.g., add or remove members).
 //   - identities: A slice of Identity objects representing the members to be added or removed.
 //
@@ -183,7 +224,7 @@ func (r Role) Update(updatedRole Role) (Role, error) {
 //	if err != nil {
 //	    log.Fatalf("Failed to modify members: %v", err)
 //	}
-func (r Role) ModifyMembers(operation ApiSetOperation, identities []Identity) ([]Identity, error) {
+func (r Role) ModifyMembers(ctx context.Context, operation ApiSetOperation, identities []Identity) ([]Identity, error) {
 	var members []Identity
 
 	query := fmt.Sprintf("Roles/%d/Members/%s", r.Id, operation)
@@ -192,7 +233,7 @@ func (r Role) ModifyMembers(operation ApiSetOperation, identities []Identity) ([
 		return nil, err
 	}
 
-	response, err := r.apiClient.PostRequest(query, bytes.NewReader(data))
+	response, err := r.apiClient.PostRequest(ctx, query, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -215,13 +256,14 @@ func (r Role) ModifyMembers(operation ApiSetOperation, identities []Identity) ([
 //	entitlements, err := GetEntitlements(filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: Filter object containing field comparisons and ordering preferences
 //
 // Returns:
 //   - []Role: A slice of roles matching the filter criteria
 //   - error: An error if the request fails, nil otherwise
-func (c *SafeguardClient) GetEntitlements(fields Filter) ([]Role, error) {
-	return c.GetRoles(fields)
+func (c *SafeguardClient) GetEntitlements(ctx context.Context, fields Filter) ([]Role, error) {
+	return c.GetRoles(ctx, fields)
 }
 
 // GetRole retrieves details for a specific role by ID.
@@ -236,13 +278,14 @@ func (c *SafeguardClient) GetEntitlements(fields Filter) ([]Role, error) {
 //	role, err := GetRole(123, fields)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the role to retrieve
 //   - fields: Optional Fields object specifying which related objects to include
 //
 // Returns:
 //   - Role: The requested role with all specified related objects
 //   - error: An error if the role is not found or request fails, nil otherwise
-func (c *SafeguardClient) GetRole(id int, fields Fields) (Role, error) {
+func (c *SafeguardClient) GetRole(ctx context.Context, id int, fields Fields) (Role, error) {
 	var userRole Role
 
 	query := fmt.Sprintf("Roles/%d", id)
@@ -250,7 +293,7 @@ func (c *SafeguardClient) GetRole(id int, fields Fields) (Role, error) {
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return userRole, err
 	}
@@ -272,16 +315,17 @@ func (c *SafeguardClient) GetRole(id int, fields Fields) (Role, error) {
 //	entitlement, err := GetEntitlement(123, fields)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the role to retrieve
 //   - fields: Optional Fields object specifying which related objects to include
 //
 // Returns:
 //   - Role: The requested role with all specified related objects
 //   - error: An error if the role is not found or request fails, nil otherwise
-func (c *SafeguardClient) GetEntitlement(id int, fields Fields) (Role, error) {
+func (c *SafeguardClient) GetEntitlement(ctx context.Context, id int, fields Fields) (Role, error) {
 	// NOTE: This is an alias for GetRole, Personal Roles seems to Entitlements in the GUI
 
-	return c.GetRole(id, fields)
+	return c.GetRole(ctx, id, fields)
 }
 
 // GetRoleMembers retrieves the list of members belonging to a specific role.
@@ -296,18 +340,19 @@ func (c *SafeguardClient) GetEntitlement(id int, fields Fields) (Role, error) {
 //	members, err := GetRoleMembers(123, filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the role
 //   - filter: Filter object to restrict which members are returned
 //
 // Returns:
 //   - []ManagedByUser: A slice of users who are members of the role
 //   - error: An error if the request fails, nil otherwise
-func (c *SafeguardClient) GetRoleMembers(id int, filter Filter) ([]Identity, error) {
+func (c *SafeguardClient) GetRoleMembers(ctx context.Context, id int, filter Filter) ([]Identity, error) {
 	var members []Identity
 
 	query := fmt.Sprintf("Roles/%d/Members%s", id, filter.ToQueryString())
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -329,13 +374,14 @@ func (c *SafeguardClient) GetRoleMembers(id int, filter Filter) ([]Identity, err
 //	members, err := role.GetMembers(filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Filter object to restrict which members are returned
 //
 // Returns:
 //   - []ManagedByUser: A slice of users who are members of the role
 //   - error: An error if the request fails, nil otherwise
-func (r Role) GetMembers(filter Filter) ([]Identity, error) {
-	return r.apiClient.GetRoleMembers(r.Id, filter)
+func (r Role) GetMembers(ctx context.Context, filter Filter) ([]Identity, error) {
+	return r.apiClient.GetRoleMembers(ctx, r.Id, filter)
 }
 
 // GetRolePolicies retrieves the list of access policies associated with a specific role.
@@ -350,18 +396,19 @@ func (r Role) GetMembers(filter Filter) ([]Identity, error) {
 //	policies, err := GetRolePolicies(123, filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the role
 //   - filter: Filter object to restrict which policies are returned
 //
 // Returns:
 //   - []AccessPolicy: A slice of access policies associated with the role
 //   - error: An error if the request fails, nil otherwise
-func (c *SafeguardClient) GetRolePolicies(id int, filter Filter) ([]AccessPolicy, error) {
+func (c *SafeguardClient) GetRolePolicies(ctx context.Context, id int, filter Filter) ([]AccessPolicy, error) {
 	var policies []AccessPolicy
 
 	query := fmt.Sprintf("Roles/%d/Policies%s", id, filter.ToQueryString())
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -384,11 +431,12 @@ func (c *SafeguardClient) GetRolePolicies(id int, filter Filter) ([]AccessPolicy
 //	policies, err := role.GetPolicies(filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - filter: Filter object to restrict which policies are returned
 //
 // Returns:
 //   - []AccessPolicy: A slice of access policies associated with the role
 //   - error: An error if the request fails, nil otherwise
-func (r Role) GetPolicies(filter Filter) ([]AccessPolicy, error) {
-	return r.apiClient.GetRolePolicies(r.Id, filter)
+func (r Role) GetPolicies(ctx context.Context, filter Filter) ([]AccessPolicy, error) {
+	return r.apiClient.GetRolePolicies(ctx, r.Id, filter)
 }
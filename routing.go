@@ -0,0 +1,201 @@
+package safeguard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Route selects the node an API request is sent to.
+type Route int
+
+const (
+	// RouteDefault sends reads and POST requests to the appliance URL and PUT
+	// and DELETE requests to the cluster leader.
+	RouteDefault Route = iota
+	// RouteAnyNode sends requests to the appliance URL. With cluster failover
+	// enabled, failed reads are repeated on other cluster members.
+	RouteAnyNode
+	// RouteLeader sends requests directly to the cluster leader. If the leader
+	// cannot be resolved or reached at all, the request is sent to the appliance
+	// URL instead.
+	RouteLeader
+	// RouteLoadBalancer always sends requests to the configured appliance URL,
+	// e.g. a load balancer that forwards writes to the leader itself.
+	RouteLoadBalancer
+)
+
+// String returns the name of the route.
+func (r Route) String() string {
+	switch r {
+	case RouteAnyNode:
+		return "any-node"
+	case RouteLeader:
+		return "leader"
+	case RouteLoadBalancer:
+		return "load-balancer"
+	default:
+		return "default"
+	}
+}
+
+// RoutingPolicy decides which node serves each class of API requests.
+// The zero value sends reads and POST requests to the appliance URL, as some
+// setups only expose the cluster leader through a load balancer, and PUT and
+// DELETE requests to the cluster leader.
+//
+// Example:
+//
+//	// Send all writes directly to the leader
+//	client.Routing = safeguard.RoutingPolicy{Writes: safeguard.RouteLeader}
+type RoutingPolicy struct {
+	Reads     Route            // Route for GET requests
+	Writes    Route            // Route for POST, PUT and DELETE requests
+	Endpoints map[string]Route // Routes for specific API paths, matched by longest prefix (e.g. "AccessRequests")
+}
+
+// routeFor returns the route for a request.
+//
+// Parameters:
+//   - method: The HTTP method of the request.
+//   - path: The API path relative to the core service root.
+//
+// Returns:
+//   - Route: The resolved route, never RouteDefault.
+func (p RoutingPolicy) routeFor(method, path string) Route {
+	route := p.Writes
+	if method == http.MethodGet {
+		route = p.Reads
+	}
+
+	matched := -1
+	for prefix, r := range p.Endpoints {
+		if strings.HasPrefix(path, prefix) && len(prefix) > matched {
+			route, matched = r, len(prefix)
+		}
+	}
+
+	if route != RouteDefault {
+		return route
+	}
+	switch method {
+	case http.MethodGet:
+		return RouteAnyNode
+	case http.MethodPost:
+		return RouteLoadBalancer
+	default:
+		return RouteLeader
+	}
+}
+
+// rootUrlFor returns the API root URL for the given route.
+func (c *SafeguardClient) rootUrlFor(ctx context.Context, route Route) string {
+	switch route {
+	case RouteLeader:
+		return c.getReadWriteRootUrl(ctx)
+	case RouteAnyNode:
+		return fmt.Sprintf("%s/service/core/%s", c.readNode(), c.ApiVersion)
+	default:
+		return c.getReadOnlyRootUrl()
+	}
+}
+
+// routedRequest sends an API request to the node selected by the routing policy.
+// The body is buffered so that it can be replayed by retries and fallbacks.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
+//   - method: The HTTP method.
+//   - path: The API path relative to the core service root.
+//   - body: The request body, or nil.
+//   - read: Consumes the body of a successful response while it is received, or
+//     nil to buffer it in Response.Body.
+//
+// Returns:
+//   - *Response: The response, or nil if no response was received.
+//   - error: An error if the request fails or returns a non-successful status code.
+func (c *SafeguardClient) routedRequest(ctx context.Context, method, path string, body io.Reader, read bodyReader) (*Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	route := c.Routing.routeFor(method, path)
+	root := c.rootUrlFor(ctx, route)
+	url := fmt.Sprintf("%s/%s", root, path)
+	c.logger().Debug("Preparing "+method+" request",
+		"url", url,
+		"path", path,
+		"route", route,
+	)
+
+	if route == RouteAnyNode && method == http.MethodGet && c.failover != nil {
+		c.refreshClusterEndpointsIfStale(ctx)
+	}
+
+	resp, err := c.sendBufferedRequest(ctx, method, url, payload, read)
+	if err == nil {
+		return resp, nil
+	}
+
+	switch route {
+	case RouteAnyNode:
+		if method == http.MethodGet && c.failover != nil && isFailoverError(err) {
+			failed := strings.TrimSuffix(root, fmt.Sprintf("/service/core/%s", c.ApiVersion))
+			return c.failoverRead(ctx, failed, path, err, read)
+		}
+	case RouteLeader:
+		c.handleWriteFailure(err)
+		if applianceUrl := c.getReadOnlyRootUrl(); neverReachedServer(err) && !strings.HasPrefix(url, applianceUrl+"/") {
+			c.logger().Warn("Cluster leader unreachable, sending request to appliance URL",
+				"method", method,
+				"path", path,
+				"error", err,
+			)
+			return c.sendBufferedRequest(ctx, method, fmt.Sprintf("%s/%s", applianceUrl, path), payload, read)
+		}
+	}
+
+	return resp, err
+}
+
+// sendBufferedRequest creates a request with a replayable body and sends it.
+func (c *SafeguardClient) sendBufferedRequest(ctx context.Context, method, url string, payload []byte, read bodyReader) (*Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		c.logger().Error("Failed to create request",
+			"error", err,
+			"method", method,
+			"url", url,
+		)
+		return nil, err
+	}
+
+	return c.sendRequest(req, read)
+}
+
+// neverReachedServer reports whether an error proves that a request was never
+// delivered to the server, so that sending it again cannot execute it twice.
+// This is the case for DNS failures and failures to establish a connection.
+func neverReachedServer(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,249 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRoutingPolicyRouteFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RoutingPolicy
+		method string
+		path   string
+		want   Route
+	}{
+		{name: "default read", method: http.MethodGet, path: "Assets", want: RouteAnyNode},
+		{name: "default post", method: http.MethodPost, path: "Assets", want: RouteLoadBalancer},
+		{name: "default put", method: http.MethodPut, path: "Assets/1", want: RouteLeader},
+		{name: "default delete", method: http.MethodDelete, path: "Assets/1", want: RouteLeader},
+		{name: "writes via load balancer", policy: RoutingPolicy{Writes: RouteLoadBalancer}, method: http.MethodPut, path: "Assets/1", want: RouteLoadBalancer},
+		{name: "reads from leader", policy: RoutingPolicy{Reads: RouteLeader}, method: http.MethodGet, path: "Assets", want: RouteLeader},
+		{
+			name:   "endpoint override",
+			policy: RoutingPolicy{Endpoints: map[string]Route{"AccessRequests": RouteLeader}},
+			method: http.MethodGet,
+			path:   "AccessRequests/1",
+			want:   RouteLeader,
+		},
+		{
+			name:   "longest prefix wins",
+			policy: RoutingPolicy{Endpoints: map[string]Route{"Assets": RouteLeader, "Assets/1/CheckPassword": RouteAnyNode}},
+			method: http.MethodPost,
+			path:   "Assets/1/CheckPassword",
+			want:   RouteAnyNode,
+		},
+		{
+			name:   "default override falls back to method",
+			policy: RoutingPolicy{Reads: RouteLeader, Endpoints: map[string]Route{"Me": RouteDefault}},
+			method: http.MethodGet,
+			path:   "Me",
+			want:   RouteAnyNode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.routeFor(tt.method, tt.path); got != tt.want {
+				t.Errorf("routeFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newRoutingTestClient returns a client with separate appliance and leader servers.
+func newRoutingTestClient(t *testing.T, leader http.HandlerFunc) (*SafeguardClient, *[]string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var hits []string
+	record := func(node string, next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			hits = append(hits, node+" "+r.Method+" "+string(b))
+			mu.Unlock()
+			if next != nil {
+				next(w, r)
+			}
+		}
+	}
+
+	appliance := httptest.NewServer(record("appliance", nil))
+	t.Cleanup(appliance.Close)
+	leaderServer := httptest.NewServer(record("leader", leader))
+	t.Cleanup(leaderServer.Close)
+
+	client := newTestServerClient(t, nil)
+	client.Appliance.setUrl(appliance.URL, -1)
+	client.ClusterLeader.setUrl(leaderServer.URL, -1)
+	client.RetryPolicy = testRetryPolicy()
+	return client, &hits
+}
+
+func TestRoutingSendsRequestsToSelectedNode(t *testing.T) {
+	client, hits := newRoutingTestClient(t, nil)
+	ctx := context.Background()
+
+	client.GetRequest(ctx, "Assets")
+	client.PostRequest(ctx, "Assets", strings.NewReader(`{"Name":"a"}`))
+	client.PutRequest(ctx, "Assets/1", strings.NewReader(`{"Name":"b"}`))
+	client.Routing = RoutingPolicy{Writes: RouteLoadBalancer}
+	client.DeleteRequest(ctx, "Assets/1")
+	client.Routing = RoutingPolicy{Writes: RouteLeader}
+	client.PostRequest(ctx, "Assets", strings.NewReader(`{"Name":"c"}`))
+
+	want := "appliance GET ,appliance POST {\"Name\":\"a\"},leader PUT {\"Name\":\"b\"},appliance DELETE ,leader POST {\"Name\":\"c\"}"
+	if got := strings.Join(*hits, ","); got != want {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestRoutingDoesNotResendDeliveredWrite(t *testing.T) {
+	client, hits := newRoutingTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	client.Routing = RoutingPolicy{Writes: RouteLeader}
+
+	_, err := client.PostRequest(context.Background(), "AccessRequests", strings.NewReader(`{"AccountId":1}`))
+	if !hasStatus(err, http.StatusInternalServerError) {
+		t.Fatalf("expected HTTP 500, got %v", err)
+	}
+	if len(*hits) != 1 {
+		t.Errorf("expected the POST to be sent once, got %q", *hits)
+	}
+}
+
+func TestRoutingFallsBackWhenLeaderUnreachable(t *testing.T) {
+	client, hits := newRoutingTestClient(t, nil)
+
+	// A closed listener refuses connections, so the POST never reaches it
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadUrl := "http://" + listener.Addr().String()
+	listener.Close()
+	client.ClusterLeader.setUrl(deadUrl, time.Hour)
+	client.Routing = RoutingPolicy{Writes: RouteLeader}
+
+	body := `{"AccountId":1,"Reason":"fallback"}`
+	if _, err := client.PostRequest(context.Background(), "AccessRequests", strings.NewReader(body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "appliance POST " + body
+	if got := strings.Join(*hits, ","); got != want {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestRoutingWritesWhenLeaderUnresolved(t *testing.T) {
+	var mu sync.Mutex
+	var hits []string
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/Cluster/Members") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		hits = append(hits, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+	})
+	client.ClusterLeader = applianceURL{} // Not resolved yet, as after NewClient
+	client.RetryPolicy = testRetryPolicy()
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		routing RoutingPolicy
+		send    func() error
+		want    string
+	}{
+		{
+			name: "default post",
+			send: func() error {
+				_, err := client.PostRequest(ctx, "AccessRequests", strings.NewReader(`{}`))
+				return err
+			},
+			want: "POST /service/core/v4/AccessRequests",
+		},
+		{
+			name: "default put",
+			send: func() error {
+				_, err := client.PutRequest(ctx, "Assets/1", strings.NewReader(`{}`))
+				return err
+			},
+			want: "PUT /service/core/v4/Assets/1",
+		},
+		{
+			name:    "leader post",
+			routing: RoutingPolicy{Writes: RouteLeader},
+			send: func() error {
+				_, err := client.PostRequest(ctx, "AccessRequests", strings.NewReader(`{}`))
+				return err
+			},
+			want: "POST /service/core/v4/AccessRequests",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits = nil
+			client.Routing = tt.routing
+			if err := tt.send(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Join(hits, ","); got != tt.want {
+				t.Errorf("requests = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryResendsPostThatNeverReachedServer(t *testing.T) {
+	policy := testRetryPolicy()
+
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:1/Assets", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	if !policy.shouldRetry(req, 0, dialErr) {
+		t.Error("expected a POST that was never delivered to be retried")
+	}
+	if policy.shouldRetry(req, 0, io.ErrUnexpectedEOF) {
+		t.Error("expected a POST that may have been delivered not to be retried")
+	}
+}
+
+func TestNeverReachedServer(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "dns failure", err: &net.DNSError{Err: "no such host", Name: "node1.example.com"}, want: true},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+		{name: "read failure", err: &net.OpError{Op: "read", Err: errors.New("connection reset")}, want: false},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, want: false},
+		{name: "api error", err: &APIError{StatusCode: http.StatusServiceUnavailable}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := neverReachedServer(tt.err); got != tt.want {
+				t.Errorf("neverReachedServer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
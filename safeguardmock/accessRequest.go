@@ -0,0 +1,109 @@
+package safeguardmock
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/sthayduk/safeguard-go"
+)
+
+// AccessRequestService is a mock of safeguard.AccessRequestService. Each method records the
+// call and invokes the field of the same name with the suffix Func. If the
+// field is nil, the method returns an error.
+type AccessRequestService struct {
+	GetMeAccountEntitlementsFunc func(ctx context.Context, accessRequestType safeguard.AccessRequestType, includeActiveRequests bool, filterByCredential bool, filter safeguard.Filter) ([]safeguard.AccountEntitlement, error)
+	GetAccessRequestsFunc        func(ctx context.Context, filter safeguard.Filter) ([]safeguard.AccessRequest, error)
+	AccessRequestsFunc           func(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.AccessRequest, error]
+	CountAccessRequestsFunc      func(ctx context.Context, filter safeguard.Filter) (int, error)
+	GetAccessRequestFunc         func(ctx context.Context, id string, fields safeguard.Fields) (safeguard.AccessRequest, error)
+	NewAccessRequestsFunc        func(ctx context.Context, accountEntitlements []safeguard.AccountEntitlement, requestDuration time.Duration) ([]safeguard.AccessRequestBatchResponse, error)
+	CheckOutPasswordFunc         func(ctx context.Context, accessRequest safeguard.AccessRequest, shouldWaitForPending bool) (string, error)
+	CheckInAccessRequestFunc     func(ctx context.Context, id string) (safeguard.AccessRequest, error)
+	CancelAccessRequestFunc      func(ctx context.Context, id string) (safeguard.AccessRequest, error)
+
+	recorder
+}
+
+var _ safeguard.AccessRequestService = (*AccessRequestService)(nil)
+
+// GetMeAccountEntitlements calls GetMeAccountEntitlementsFunc.
+func (m *AccessRequestService) GetMeAccountEntitlements(ctx context.Context, accessRequestType safeguard.AccessRequestType, includeActiveRequests bool, filterByCredential bool, filter safeguard.Filter) ([]safeguard.AccountEntitlement, error) {
+	m.record("GetMeAccountEntitlements", accessRequestType, includeActiveRequests, filterByCredential, filter)
+	if m.GetMeAccountEntitlementsFunc == nil {
+		return nil, notImplemented("AccessRequestService", "GetMeAccountEntitlements")
+	}
+	return m.GetMeAccountEntitlementsFunc(ctx, accessRequestType, includeActiveRequests, filterByCredential, filter)
+}
+
+// GetAccessRequests calls GetAccessRequestsFunc.
+func (m *AccessRequestService) GetAccessRequests(ctx context.Context, filter safeguard.Filter) ([]safeguard.AccessRequest, error) {
+	m.record("GetAccessRequests", filter)
+	if m.GetAccessRequestsFunc == nil {
+		return nil, notImplemented("AccessRequestService", "GetAccessRequests")
+	}
+	return m.GetAccessRequestsFunc(ctx, filter)
+}
+
+// AccessRequests calls AccessRequestsFunc.
+func (m *AccessRequestService) AccessRequests(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.AccessRequest, error] {
+	m.record("AccessRequests", filter)
+	if m.AccessRequestsFunc == nil {
+		return failedSeq[safeguard.AccessRequest](notImplemented("AccessRequestService", "AccessRequests"))
+	}
+	return m.AccessRequestsFunc(ctx, filter)
+}
+
+// CountAccessRequests calls CountAccessRequestsFunc.
+func (m *AccessRequestService) CountAccessRequests(ctx context.Context, filter safeguard.Filter) (int, error) {
+	m.record("CountAccessRequests", filter)
+	if m.CountAccessRequestsFunc == nil {
+		return 0, notImplemented("AccessRequestService", "CountAccessRequests")
+	}
+	return m.CountAccessRequestsFunc(ctx, filter)
+}
+
+// GetAccessRequest calls GetAccessRequestFunc.
+func (m *AccessRequestService) GetAccessRequest(ctx context.Context, id string, fields safeguard.Fields) (safeguard.AccessRequest, error) {
+	m.record("GetAccessRequest", id, fields)
+	if m.GetAccessRequestFunc == nil {
+		return safeguard.AccessRequest{}, notImplemented("AccessRequestService", "GetAccessRequest")
+	}
+	return m.GetAccessRequestFunc(ctx, id, fields)
+}
+
+// NewAccessRequests calls NewAccessRequestsFunc.
+func (m *AccessRequestService) NewAccessRequests(ctx context.Context, accountEntitlements []safeguard.AccountEntitlement, requestDuration time.Duration) ([]safeguard.AccessRequestBatchResponse, error) {
+	m.record("NewAccessRequests", accountEntitlements, requestDuration)
+	if m.NewAccessRequestsFunc == nil {
+		return nil, notImplemented("AccessRequestService", "NewAccessRequests")
+	}
+	return m.NewAccessRequestsFunc(ctx, accountEntitlements, requestDuration)
+}
+
+// CheckOutPassword calls CheckOutPasswordFunc.
+func (m *AccessRequestService) CheckOutPassword(ctx context.Context, accessRequest safeguard.AccessRequest, shouldWaitForPending bool) (string, error) {
+	m.record("CheckOutPassword", accessRequest, shouldWaitForPending)
+	if m.CheckOutPasswordFunc == nil {
+		return "", notImplemented("AccessRequestService", "CheckOutPassword")
+	}
+	return m.CheckOutPasswordFunc(ctx, accessRequest, shouldWaitForPending)
+}
+
+// CheckInAccessRequest calls CheckInAccessRequestFunc.
+func (m *AccessRequestService) CheckInAccessRequest(ctx context.Context, id string) (safeguard.AccessRequest, error) {
+	m.record("CheckInAccessRequest", id)
+	if m.CheckInAccessRequestFunc == nil {
+		return safeguard.AccessRequest{}, notImplemented("AccessRequestService", "CheckInAccessRequest")
+	}
+	return m.CheckInAccessRequestFunc(ctx, id)
+}
+
+// CancelAccessRequest calls CancelAccessRequestFunc.
+func (m *AccessRequestService) CancelAccessRequest(ctx context.Context, id string) (safeguard.AccessRequest, error) {
+	m.record("CancelAccessRequest", id)
+	if m.CancelAccessRequestFunc == nil {
+		return safeguard.AccessRequest{}, notImplemented("AccessRequestService", "CancelAccessRequest")
+	}
+	return m.CancelAccessRequestFunc(ctx, id)
+}
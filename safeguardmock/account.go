@@ -0,0 +1,158 @@
+package safeguardmock
+
+import (
+	"context"
+	"iter"
+
+	"github.com/sthayduk/safeguard-go"
+)
+
+// AccountService is a mock of safeguard.AccountService. Each method records the
+// call and invokes the field of the same name with the suffix Func. If the
+// field is nil, the method returns an error.
+type AccountService struct {
+	GetAssetAccountsFunc           func(ctx context.Context, filter safeguard.Filter) ([]safeguard.AssetAccount, error)
+	AssetAccountsFunc              func(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.AssetAccount, error]
+	CountAssetAccountsFunc         func(ctx context.Context, filter safeguard.Filter) (int, error)
+	GetAssetAccountFunc            func(ctx context.Context, id int, fields safeguard.Fields) (safeguard.AssetAccount, error)
+	CreateAssetAccountFunc         func(ctx context.Context, assetAccount safeguard.AssetAccount) (safeguard.AssetAccount, error)
+	CreateAssetAccountsFunc        func(ctx context.Context, assetAccounts []safeguard.AssetAccount) ([]safeguard.AssetAccount, error)
+	UpdateAssetAccountFunc         func(ctx context.Context, assetAccount safeguard.AssetAccount) (safeguard.AssetAccount, error)
+	DeleteAssetAccountFunc         func(ctx context.Context, id int) error
+	UpdatePasswordProfileFunc      func(ctx context.Context, assetAccount safeguard.AssetAccount, passwordPolicy safeguard.AccountPasswordRule) (safeguard.AssetAccount, error)
+	EnableAssetAccountFunc         func(ctx context.Context, assetAccount safeguard.AssetAccount) (safeguard.AssetAccount, error)
+	DisableAssetAccountFunc        func(ctx context.Context, assetAccount safeguard.AssetAccount) (safeguard.AssetAccount, error)
+	SuspendAssetAccountFunc        func(ctx context.Context, a safeguard.AssetAccount) (safeguard.ActivityLog, error)
+	ChangeAssetAccountPasswordFunc func(ctx context.Context, a safeguard.AssetAccount) (safeguard.ActivityLog, error)
+	CheckAssetAccountPasswordFunc  func(ctx context.Context, a safeguard.AssetAccount) (safeguard.ActivityLog, error)
+
+	recorder
+}
+
+var _ safeguard.AccountService = (*AccountService)(nil)
+
+// GetAssetAccounts calls GetAssetAccountsFunc.
+func (m *AccountService) GetAssetAccounts(ctx context.Context, filter safeguard.Filter) ([]safeguard.AssetAccount, error) {
+	m.record("GetAssetAccounts", filter)
+	if m.GetAssetAccountsFunc == nil {
+		return nil, notImplemented("AccountService", "GetAssetAccounts")
+	}
+	return m.GetAssetAccountsFunc(ctx, filter)
+}
+
+// AssetAccounts calls AssetAccountsFunc.
+func (m *AccountService) AssetAccounts(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.AssetAccount, error] {
+	m.record("AssetAccounts", filter)
+	if m.AssetAccountsFunc == nil {
+		return failedSeq[safeguard.AssetAccount](notImplemented("AccountService", "AssetAccounts"))
+	}
+	return m.AssetAccountsFunc(ctx, filter)
+}
+
+// CountAssetAccounts calls CountAssetAccountsFunc.
+func (m *AccountService) CountAssetAccounts(ctx context.Context, filter safeguard.Filter) (int, error) {
+	m.record("CountAssetAccounts", filter)
+	if m.CountAssetAccountsFunc == nil {
+		return 0, notImplemented("AccountService", "CountAssetAccounts")
+	}
+	return m.CountAssetAccountsFunc(ctx, filter)
+}
+
+// GetAssetAccount calls GetAssetAccountFunc.
+func (m *AccountService) GetAssetAccount(ctx context.Context, id int, fields safeguard.Fields) (safeguard.AssetAccount, error) {
+	m.record("GetAssetAccount", id, fields)
+	if m.GetAssetAccountFunc == nil {
+		return safeguard.AssetAccount{}, notImplemented("AccountService", "GetAssetAccount")
+	}
+	return m.GetAssetAccountFunc(ctx, id, fields)
+}
+
+// CreateAssetAccount calls CreateAssetAccountFunc.
+func (m *AccountService) CreateAssetAccount(ctx context.Context, assetAccount safeguard.AssetAccount) (safeguard.AssetAccount, error) {
+	m.record("CreateAssetAccount", assetAccount)
+	if m.CreateAssetAccountFunc == nil {
+		return safeguard.AssetAccount{}, notImplemented("AccountService", "CreateAssetAccount")
+	}
+	return m.CreateAssetAccountFunc(ctx, assetAccount)
+}
+
+// CreateAssetAccounts calls CreateAssetAccountsFunc.
+func (m *AccountService) CreateAssetAccounts(ctx context.Context, assetAccounts []safeguard.AssetAccount) ([]safeguard.AssetAccount, error) {
+	m.record("CreateAssetAccounts", assetAccounts)
+	if m.CreateAssetAccountsFunc == nil {
+		return nil, notImplemented("AccountService", "CreateAssetAccounts")
+	}
+	return m.CreateAssetAccountsFunc(ctx, assetAccounts)
+}
+
+// UpdateAssetAccount calls UpdateAssetAccountFunc.
+func (m *AccountService) UpdateAssetAccount(ctx context.Context, assetAccount safeguard.AssetAccount) (safeguard.AssetAccount, error) {
+	m.record("UpdateAssetAccount", assetAccount)
+	if m.UpdateAssetAccountFunc == nil {
+		return safeguard.AssetAccount{}, notImplemented("AccountService", "UpdateAssetAccount")
+	}
+	return m.UpdateAssetAccountFunc(ctx, assetAccount)
+}
+
+// DeleteAssetAccount calls DeleteAssetAccountFunc.
+func (m *AccountService) DeleteAssetAccount(ctx context.Context, id int) error {
+	m.record("DeleteAssetAccount", id)
+	if m.DeleteAssetAccountFunc == nil {
+		return notImplemented("AccountService", "DeleteAssetAccount")
+	}
+	return m.DeleteAssetAccountFunc(ctx, id)
+}
+
+// UpdatePasswordProfile calls UpdatePasswordProfileFunc.
+func (m *AccountService) UpdatePasswordProfile(ctx context.Context, assetAccount safeguard.AssetAccount, passwordPolicy safeguard.AccountPasswordRule) (safeguard.AssetAccount, error) {
+	m.record("UpdatePasswordProfile", assetAccount, passwordPolicy)
+	if m.UpdatePasswordProfileFunc == nil {
+		return safeguard.AssetAccount{}, notImplemented("AccountService", "UpdatePasswordProfile")
+	}
+	return m.UpdatePasswordProfileFunc(ctx, assetAccount, passwordPolicy)
+}
+
+// EnableAssetAccount calls EnableAssetAccountFunc.
+func (m *AccountService) EnableAssetAccount(ctx context.Context, assetAccount safeguard.AssetAccount) (safeguard.AssetAccount, error) {
+	m.record("EnableAssetAccount", assetAccount)
+	if m.EnableAssetAccountFunc == nil {
+		return safeguard.AssetAccount{}, notImplemented("AccountService", "EnableAssetAccount")
+	}
+	return m.EnableAssetAccountFunc(ctx, assetAccount)
+}
+
+// DisableAssetAccount calls DisableAssetAccountFunc.
+func (m *AccountService) DisableAssetAccount(ctx context.Context, assetAccount safeguard.AssetAccount) (safeguard.AssetAccount, error) {
+	m.record("DisableAssetAccount", assetAccount)
+	if m.DisableAssetAccountFunc == nil {
+		return safeguard.AssetAccount{}, notImplemented("AccountService", "DisableAssetAccount")
+	}
+	return m.DisableAssetAccountFunc(ctx, assetAccount)
+}
+
+// SuspendAssetAccount calls SuspendAssetAccountFunc.
+func (m *AccountService) SuspendAssetAccount(ctx context.Context, a safeguard.AssetAccount) (safeguard.ActivityLog, error) {
+	m.record("SuspendAssetAccount", a)
+	if m.SuspendAssetAccountFunc == nil {
+		return safeguard.ActivityLog{}, notImplemented("AccountService", "SuspendAssetAccount")
+	}
+	return m.SuspendAssetAccountFunc(ctx, a)
+}
+
+// ChangeAssetAccountPassword calls ChangeAssetAccountPasswordFunc.
+func (m *AccountService) ChangeAssetAccountPassword(ctx context.Context, a safeguard.AssetAccount) (safeguard.ActivityLog, error) {
+	m.record("ChangeAssetAccountPassword", a)
+	if m.ChangeAssetAccountPasswordFunc == nil {
+		return safeguard.ActivityLog{}, notImplemented("AccountService", "ChangeAssetAccountPassword")
+	}
+	return m.ChangeAssetAccountPasswordFunc(ctx, a)
+}
+
+// CheckAssetAccountPassword calls CheckAssetAccountPasswordFunc.
+func (m *AccountService) CheckAssetAccountPassword(ctx context.Context, a safeguard.AssetAccount) (safeguard.ActivityLog, error) {
+	m.record("CheckAssetAccountPassword", a)
+	if m.CheckAssetAccountPasswordFunc == nil {
+		return safeguard.ActivityLog{}, notImplemented("AccountService", "CheckAssetAccountPassword")
+	}
+	return m.CheckAssetAccountPasswordFunc(ctx, a)
+}
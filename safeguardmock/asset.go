@@ -0,0 +1,98 @@
+package safeguardmock
+
+import (
+	"context"
+	"iter"
+
+	"github.com/sthayduk/safeguard-go"
+)
+
+// AssetService is a mock of safeguard.AssetService. Each method records the
+// call and invokes the field of the same name with the suffix Func. If the
+// field is nil, the method returns an error.
+type AssetService struct {
+	GetAssetsFunc                       func(ctx context.Context, filter safeguard.Filter) ([]safeguard.Asset, error)
+	AssetsFunc                          func(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.Asset, error]
+	CountAssetsFunc                     func(ctx context.Context, filter safeguard.Filter) (int, error)
+	GetAssetFunc                        func(ctx context.Context, id int, fields safeguard.Fields) (safeguard.Asset, error)
+	UpdateAssetFunc                     func(ctx context.Context, id int, updatedAsset safeguard.Asset) (safeguard.Asset, error)
+	GetAssetDirectoryAccountsFunc       func(ctx context.Context, assetId int, filter safeguard.Filter) ([]safeguard.AssetAccount, error)
+	GetAssetDirectoryAssetsFunc         func(ctx context.Context, assetId int, filter safeguard.Filter) ([]safeguard.Asset, error)
+	GetAssetDirectoryServiceEntriesFunc func(ctx context.Context, assetId int, filter safeguard.Filter) ([]safeguard.DirectoryServiceEntry, error)
+
+	recorder
+}
+
+var _ safeguard.AssetService = (*AssetService)(nil)
+
+// GetAssets calls GetAssetsFunc.
+func (m *AssetService) GetAssets(ctx context.Context, filter safeguard.Filter) ([]safeguard.Asset, error) {
+	m.record("GetAssets", filter)
+	if m.GetAssetsFunc == nil {
+		return nil, notImplemented("AssetService", "GetAssets")
+	}
+	return m.GetAssetsFunc(ctx, filter)
+}
+
+// Assets calls AssetsFunc.
+func (m *AssetService) Assets(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.Asset, error] {
+	m.record("Assets", filter)
+	if m.AssetsFunc == nil {
+		return failedSeq[safeguard.Asset](notImplemented("AssetService", "Assets"))
+	}
+	return m.AssetsFunc(ctx, filter)
+}
+
+// CountAssets calls CountAssetsFunc.
+func (m *AssetService) CountAssets(ctx context.Context, filter safeguard.Filter) (int, error) {
+	m.record("CountAssets", filter)
+	if m.CountAssetsFunc == nil {
+		return 0, notImplemented("AssetService", "CountAssets")
+	}
+	return m.CountAssetsFunc(ctx, filter)
+}
+
+// GetAsset calls GetAssetFunc.
+func (m *AssetService) GetAsset(ctx context.Context, id int, fields safeguard.Fields) (safeguard.Asset, error) {
+	m.record("GetAsset", id, fields)
+	if m.GetAssetFunc == nil {
+		return safeguard.Asset{}, notImplemented("AssetService", "GetAsset")
+	}
+	return m.GetAssetFunc(ctx, id, fields)
+}
+
+// UpdateAsset calls UpdateAssetFunc.
+func (m *AssetService) UpdateAsset(ctx context.Context, id int, updatedAsset safeguard.Asset) (safeguard.Asset, error) {
+	m.record("UpdateAsset", id, updatedAsset)
+	if m.UpdateAssetFunc == nil {
+		return safeguard.Asset{}, notImplemented("AssetService", "UpdateAsset")
+	}
+	return m.UpdateAssetFunc(ctx, id, updatedAsset)
+}
+
+// GetAssetDirectoryAccounts calls GetAssetDirectoryAccountsFunc.
+func (m *AssetService) GetAssetDirectoryAccounts(ctx context.Context, assetId int, filter safeguard.Filter) ([]safeguard.AssetAccount, error) {
+	m.record("GetAssetDirectoryAccounts", assetId, filter)
+	if m.GetAssetDirectoryAccountsFunc == nil {
+		return nil, notImplemented("AssetService", "GetAssetDirectoryAccounts")
+	}
+	return m.GetAssetDirectoryAccountsFunc(ctx, assetId, filter)
+}
+
+// GetAssetDirectoryAssets calls GetAssetDirectoryAssetsFunc.
+func (m *AssetService) GetAssetDirectoryAssets(ctx context.Context, assetId int, filter safeguard.Filter) ([]safeguard.Asset, error) {
+	m.record("GetAssetDirectoryAssets", assetId, filter)
+	if m.GetAssetDirectoryAssetsFunc == nil {
+		return nil, notImplemented("AssetService", "GetAssetDirectoryAssets")
+	}
+	return m.GetAssetDirectoryAssetsFunc(ctx, assetId, filter)
+}
+
+// GetAssetDirectoryServiceEntries calls GetAssetDirectoryServiceEntriesFunc.
+func (m *AssetService) GetAssetDirectoryServiceEntries(ctx context.Context, assetId int, filter safeguard.Filter) ([]safeguard.DirectoryServiceEntry, error) {
+	m.record("GetAssetDirectoryServiceEntries", assetId, filter)
+	if m.GetAssetDirectoryServiceEntriesFunc == nil {
+		return nil, notImplemented("AssetService", "GetAssetDirectoryServiceEntries")
+	}
+	return m.GetAssetDirectoryServiceEntriesFunc(ctx, assetId, filter)
+}
@@ -0,0 +1,95 @@
+// Package safeguardmock provides mocks of the safeguard service interfaces for
+// unit tests. Each mock has a function field per method; set the fields a test
+// needs and inspect the recorded calls afterwards.
+//
+// Example:
+//
+//	requests := &safeguardmock.AccessRequestService{
+//	    CheckOutPasswordFunc: func(ctx context.Context, ar safeguard.AccessRequest, wait bool) (string, error) {
+//	        return "s3cret", nil
+//	    },
+//	}
+//	password, err := releasePassword(ctx, requests, entitlement)
+//	if calls := requests.CallsTo("CheckOutPassword"); len(calls) != 1 {
+//	    t.Errorf("expected one checkout, got %d", len(calls))
+//	}
+//
+// For tests that exercise the HTTP layer as well, use the sgfake package.
+package safeguardmock
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// Call is a recorded method call.
+type Call struct {
+	// Method is the name of the called method, e.g. "GetAssets".
+	Method string
+	// Args are the arguments of the call without the context.
+	Args []any
+}
+
+// recorder records the calls of a mock. It is safe for concurrent use.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// record appends a call.
+func (r *recorder) record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns all recorded calls in order.
+//
+// Returns:
+//   - []Call: The recorded calls.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the recorded calls of a single method in order.
+//
+// Parameters:
+//   - method: The method name, e.g. "GetAssets".
+//
+// Returns:
+//   - []Call: The recorded calls of the method.
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var calls []Call
+	for _, call := range r.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset removes all recorded calls.
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// notImplemented returns the error of a method whose function field is not set.
+func notImplemented(service, method string) error {
+	return fmt.Errorf("safeguardmock: %s.%s called but %sFunc is not set", service, method, method)
+}
+
+// failedSeq returns an iterator that yields a single error.
+func failedSeq[T any](err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, err)
+	}
+}
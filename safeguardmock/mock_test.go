@@ -0,0 +1,116 @@
+package safeguardmock
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sthayduk/safeguard-go"
+)
+
+// releasePassword is a workflow that depends only on the service interface.
+func releasePassword(ctx context.Context, requests safeguard.AccessRequestService, entitlement safeguard.AccountEntitlement) (string, error) {
+	responses, err := requests.NewAccessRequests(ctx, []safeguard.AccountEntitlement{entitlement}, time.Hour)
+	if err != nil {
+		return "", err
+	}
+	request := responses[0].Response
+
+	password, err := requests.CheckOutPassword(ctx, request, true)
+	if err != nil {
+		if _, cancelErr := requests.CancelAccessRequest(ctx, request.Id); cancelErr != nil {
+			return "", errors.Join(err, cancelErr)
+		}
+		return "", err
+	}
+	return password, nil
+}
+
+func TestAccessRequestServiceWorkflow(t *testing.T) {
+	entitlement := safeguard.AccountEntitlement{Account: safeguard.AccountInfo{Id: 7}}
+	newRequests := func(ctx context.Context, entitlements []safeguard.AccountEntitlement, d time.Duration) ([]safeguard.AccessRequestBatchResponse, error) {
+		return []safeguard.AccessRequestBatchResponse{{
+			IsSuccess: true,
+			Response:  safeguard.AccessRequest{Id: "1", AccountId: entitlements[0].Account.Id, State: safeguard.StateRequestAvailable},
+		}}, nil
+	}
+
+	tests := []struct {
+		name         string
+		checkOut     func(context.Context, safeguard.AccessRequest, bool) (string, error)
+		wantPassword string
+		wantErr      bool
+		wantCancels  int
+	}{
+		{
+			name: "password released",
+			checkOut: func(ctx context.Context, ar safeguard.AccessRequest, wait bool) (string, error) {
+				return "s3cret", nil
+			},
+			wantPassword: "s3cret",
+		},
+		{
+			name: "checkout fails",
+			checkOut: func(ctx context.Context, ar safeguard.AccessRequest, wait bool) (string, error) {
+				return "", errors.New("denied")
+			},
+			wantErr:     true,
+			wantCancels: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := &AccessRequestService{
+				NewAccessRequestsFunc: newRequests,
+				CheckOutPasswordFunc:  tt.checkOut,
+				CancelAccessRequestFunc: func(ctx context.Context, id string) (safeguard.AccessRequest, error) {
+					return safeguard.AccessRequest{Id: id, State: safeguard.StateCanceled}, nil
+				},
+			}
+
+			password, err := releasePassword(context.Background(), requests, entitlement)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("releasePassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if password != tt.wantPassword {
+				t.Errorf("releasePassword() = %q, want %q", password, tt.wantPassword)
+			}
+
+			checkouts := requests.CallsTo("CheckOutPassword")
+			if len(checkouts) != 1 {
+				t.Fatalf("expected 1 checkout, got %d", len(checkouts))
+			}
+			if ar := checkouts[0].Args[0].(safeguard.AccessRequest); ar.AccountId != 7 || checkouts[0].Args[1] != true {
+				t.Errorf("unexpected checkout arguments %v", checkouts[0].Args)
+			}
+			if got := len(requests.CallsTo("CancelAccessRequest")); got != tt.wantCancels {
+				t.Errorf("expected %d cancels, got %d", tt.wantCancels, got)
+			}
+		})
+	}
+}
+
+func TestUnsetMethod(t *testing.T) {
+	ctx := context.Background()
+	assets := &AssetService{}
+
+	if _, err := assets.GetAsset(ctx, 1, nil); err == nil || !strings.Contains(err.Error(), "GetAssetFunc") {
+		t.Errorf("expected an error naming GetAssetFunc, got %v", err)
+	}
+	for _, err := range assets.Assets(ctx, safeguard.Filter{}) {
+		if err == nil {
+			t.Error("expected the iterator to yield an error")
+		}
+	}
+	if calls := assets.Calls(); len(calls) != 2 || calls[0].Method != "GetAsset" || calls[1].Method != "Assets" {
+		t.Errorf("unexpected calls %v", calls)
+	}
+
+	assets.Reset()
+	if calls := assets.Calls(); len(calls) != 0 {
+		t.Errorf("expected no calls after Reset, got %v", calls)
+	}
+}
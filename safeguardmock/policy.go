@@ -0,0 +1,158 @@
+package safeguardmock
+
+import (
+	"context"
+	"iter"
+
+	"github.com/sthayduk/safeguard-go"
+)
+
+// PolicyService is a mock of safeguard.PolicyService. Each method records the
+// call and invokes the field of the same name with the suffix Func. If the
+// field is nil, the method returns an error.
+type PolicyService struct {
+	GetAccessPoliciesFunc   func(ctx context.Context, filter safeguard.Filter) ([]safeguard.AccessPolicy, error)
+	AccessPoliciesFunc      func(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.AccessPolicy, error]
+	CountAccessPoliciesFunc func(ctx context.Context, filter safeguard.Filter) (int, error)
+	GetAccessPolicyFunc     func(ctx context.Context, id int, fields safeguard.Fields) (safeguard.AccessPolicy, error)
+	UpdateAccessPolicyFunc  func(ctx context.Context, id int, updatedAccessPolicy safeguard.AccessPolicy) (safeguard.AccessPolicy, error)
+	DeleteAccessPolicyFunc  func(ctx context.Context, id int) error
+	GetPolicyAssetsFunc     func(ctx context.Context, filter safeguard.Filter) ([]safeguard.PolicyAsset, error)
+	PolicyAssetsFunc        func(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.PolicyAsset, error]
+	CountPolicyAssetsFunc   func(ctx context.Context, filter safeguard.Filter) (int, error)
+	GetPolicyAssetFunc      func(ctx context.Context, id int, fields safeguard.Fields) (safeguard.PolicyAsset, error)
+	GetPolicyAccountsFunc   func(ctx context.Context, filter safeguard.Filter) ([]safeguard.PolicyAccount, error)
+	PolicyAccountsFunc      func(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.PolicyAccount, error]
+	CountPolicyAccountsFunc func(ctx context.Context, filter safeguard.Filter) (int, error)
+	GetPolicyAccountFunc    func(ctx context.Context, id int, fields safeguard.Fields) (safeguard.PolicyAccount, error)
+
+	recorder
+}
+
+var _ safeguard.PolicyService = (*PolicyService)(nil)
+
+// GetAccessPolicies calls GetAccessPoliciesFunc.
+func (m *PolicyService) GetAccessPolicies(ctx context.Context, filter safeguard.Filter) ([]safeguard.AccessPolicy, error) {
+	m.record("GetAccessPolicies", filter)
+	if m.GetAccessPoliciesFunc == nil {
+		return nil, notImplemented("PolicyService", "GetAccessPolicies")
+	}
+	return m.GetAccessPoliciesFunc(ctx, filter)
+}
+
+// AccessPolicies calls AccessPoliciesFunc.
+func (m *PolicyService) AccessPolicies(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.AccessPolicy, error] {
+	m.record("AccessPolicies", filter)
+	if m.AccessPoliciesFunc == nil {
+		return failedSeq[safeguard.AccessPolicy](notImplemented("PolicyService", "AccessPolicies"))
+	}
+	return m.AccessPoliciesFunc(ctx, filter)
+}
+
+// CountAccessPolicies calls CountAccessPoliciesFunc.
+func (m *PolicyService) CountAccessPolicies(ctx context.Context, filter safeguard.Filter) (int, error) {
+	m.record("CountAccessPolicies", filter)
+	if m.CountAccessPoliciesFunc == nil {
+		return 0, notImplemented("PolicyService", "CountAccessPolicies")
+	}
+	return m.CountAccessPoliciesFunc(ctx, filter)
+}
+
+// GetAccessPolicy calls GetAccessPolicyFunc.
+func (m *PolicyService) GetAccessPolicy(ctx context.Context, id int, fields safeguard.Fields) (safeguard.AccessPolicy, error) {
+	m.record("GetAccessPolicy", id, fields)
+	if m.GetAccessPolicyFunc == nil {
+		return safeguard.AccessPolicy{}, notImplemented("PolicyService", "GetAccessPolicy")
+	}
+	return m.GetAccessPolicyFunc(ctx, id, fields)
+}
+
+// UpdateAccessPolicy calls UpdateAccessPolicyFunc.
+func (m *PolicyService) UpdateAccessPolicy(ctx context.Context, id int, updatedAccessPolicy safeguard.AccessPolicy) (safeguard.AccessPolicy, error) {
+	m.record("UpdateAccessPolicy", id, updatedAccessPolicy)
+	if m.UpdateAccessPolicyFunc == nil {
+		return safeguard.AccessPolicy{}, notImplemented("PolicyService", "UpdateAccessPolicy")
+	}
+	return m.UpdateAccessPolicyFunc(ctx, id, updatedAccessPolicy)
+}
+
+// DeleteAccessPolicy calls DeleteAccessPolicyFunc.
+func (m *PolicyService) DeleteAccessPolicy(ctx context.Context, id int) error {
+	m.record("DeleteAccessPolicy", id)
+	if m.DeleteAccessPolicyFunc == nil {
+		return notImplemented("PolicyService", "DeleteAccessPolicy")
+	}
+	return m.DeleteAccessPolicyFunc(ctx, id)
+}
+
+// GetPolicyAssets calls GetPolicyAssetsFunc.
+func (m *PolicyService) GetPolicyAssets(ctx context.Context, filter safeguard.Filter) ([]safeguard.PolicyAsset, error) {
+	m.record("GetPolicyAssets", filter)
+	if m.GetPolicyAssetsFunc == nil {
+		return nil, notImplemented("PolicyService", "GetPolicyAssets")
+	}
+	return m.GetPolicyAssetsFunc(ctx, filter)
+}
+
+// PolicyAssets calls PolicyAssetsFunc.
+func (m *PolicyService) PolicyAssets(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.PolicyAsset, error] {
+	m.record("PolicyAssets", filter)
+	if m.PolicyAssetsFunc == nil {
+		return failedSeq[safeguard.PolicyAsset](notImplemented("PolicyService", "PolicyAssets"))
+	}
+	return m.PolicyAssetsFunc(ctx, filter)
+}
+
+// CountPolicyAssets calls CountPolicyAssetsFunc.
+func (m *PolicyService) CountPolicyAssets(ctx context.Context, filter safeguard.Filter) (int, error) {
+	m.record("CountPolicyAssets", filter)
+	if m.CountPolicyAssetsFunc == nil {
+		return 0, notImplemented("PolicyService", "CountPolicyAssets")
+	}
+	return m.CountPolicyAssetsFunc(ctx, filter)
+}
+
+// GetPolicyAsset calls GetPolicyAssetFunc.
+func (m *PolicyService) GetPolicyAsset(ctx context.Context, id int, fields safeguard.Fields) (safeguard.PolicyAsset, error) {
+	m.record("GetPolicyAsset", id, fields)
+	if m.GetPolicyAssetFunc == nil {
+		return safeguard.PolicyAsset{}, notImplemented("PolicyService", "GetPolicyAsset")
+	}
+	return m.GetPolicyAssetFunc(ctx, id, fields)
+}
+
+// GetPolicyAccounts calls GetPolicyAccountsFunc.
+func (m *PolicyService) GetPolicyAccounts(ctx context.Context, filter safeguard.Filter) ([]safeguard.PolicyAccount, error) {
+	m.record("GetPolicyAccounts", filter)
+	if m.GetPolicyAccountsFunc == nil {
+		return nil, notImplemented("PolicyService", "GetPolicyAccounts")
+	}
+	return m.GetPolicyAccountsFunc(ctx, filter)
+}
+
+// PolicyAccounts calls PolicyAccountsFunc.
+func (m *PolicyService) PolicyAccounts(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.PolicyAccount, error] {
+	m.record("PolicyAccounts", filter)
+	if m.PolicyAccountsFunc == nil {
+		return failedSeq[safeguard.PolicyAccount](notImplemented("PolicyService", "PolicyAccounts"))
+	}
+	return m.PolicyAccountsFunc(ctx, filter)
+}
+
+// CountPolicyAccounts calls CountPolicyAccountsFunc.
+func (m *PolicyService) CountPolicyAccounts(ctx context.Context, filter safeguard.Filter) (int, error) {
+	m.record("CountPolicyAccounts", filter)
+	if m.CountPolicyAccountsFunc == nil {
+		return 0, notImplemented("PolicyService", "CountPolicyAccounts")
+	}
+	return m.CountPolicyAccountsFunc(ctx, filter)
+}
+
+// GetPolicyAccount calls GetPolicyAccountFunc.
+func (m *PolicyService) GetPolicyAccount(ctx context.Context, id int, fields safeguard.Fields) (safeguard.PolicyAccount, error) {
+	m.record("GetPolicyAccount", id, fields)
+	if m.GetPolicyAccountFunc == nil {
+		return safeguard.PolicyAccount{}, notImplemented("PolicyService", "GetPolicyAccount")
+	}
+	return m.GetPolicyAccountFunc(ctx, id, fields)
+}
@@ -0,0 +1,148 @@
+package safeguardmock
+
+import (
+	"context"
+	"iter"
+
+	"github.com/sthayduk/safeguard-go"
+)
+
+// UserService is a mock of safeguard.UserService. Each method records the
+// call and invokes the field of the same name with the suffix Func. If the
+// field is nil, the method returns an error.
+type UserService struct {
+	GetMeFunc                func(ctx context.Context, filter safeguard.Filter) (safeguard.User, error)
+	GetUsersFunc             func(ctx context.Context, filter safeguard.Filter) ([]safeguard.User, error)
+	UsersFunc                func(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.User, error]
+	CountUsersFunc           func(ctx context.Context, filter safeguard.Filter) (int, error)
+	GetUserFunc              func(ctx context.Context, id int, fields safeguard.Fields) (safeguard.User, error)
+	CreateUserFunc           func(ctx context.Context, user safeguard.User) (safeguard.User, error)
+	DeleteUserFunc           func(ctx context.Context, id int) error
+	GetLinkedAccountsFunc    func(ctx context.Context, id string) ([]safeguard.PolicyAccount, error)
+	AddLinkedAccountsFunc    func(ctx context.Context, user safeguard.User, policyAccount []safeguard.PolicyAccount) ([]safeguard.PolicyAccount, error)
+	RemoveLinkedAccountsFunc func(ctx context.Context, user safeguard.User, policyAccount []safeguard.PolicyAccount) ([]safeguard.PolicyAccount, error)
+	GetUserRolesFunc         func(ctx context.Context, id string) ([]safeguard.Role, error)
+	GetGroupsFunc            func(ctx context.Context, id string) ([]safeguard.UserGroup, error)
+	GetUserPreferencesFunc   func(ctx context.Context, id int) ([]safeguard.Preference, error)
+
+	recorder
+}
+
+var _ safeguard.UserService = (*UserService)(nil)
+
+// GetMe calls GetMeFunc.
+func (m *UserService) GetMe(ctx context.Context, filter safeguard.Filter) (safeguard.User, error) {
+	m.record("GetMe", filter)
+	if m.GetMeFunc == nil {
+		return safeguard.User{}, notImplemented("UserService", "GetMe")
+	}
+	return m.GetMeFunc(ctx, filter)
+}
+
+// GetUsers calls GetUsersFunc.
+func (m *UserService) GetUsers(ctx context.Context, filter safeguard.Filter) ([]safeguard.User, error) {
+	m.record("GetUsers", filter)
+	if m.GetUsersFunc == nil {
+		return nil, notImplemented("UserService", "GetUsers")
+	}
+	return m.GetUsersFunc(ctx, filter)
+}
+
+// Users calls UsersFunc.
+func (m *UserService) Users(ctx context.Context, filter safeguard.Filter) iter.Seq2[safeguard.User, error] {
+	m.record("Users", filter)
+	if m.UsersFunc == nil {
+		return failedSeq[safeguard.User](notImplemented("UserService", "Users"))
+	}
+	return m.UsersFunc(ctx, filter)
+}
+
+// CountUsers calls CountUsersFunc.
+func (m *UserService) CountUsers(ctx context.Context, filter safeguard.Filter) (int, error) {
+	m.record("CountUsers", filter)
+	if m.CountUsersFunc == nil {
+		return 0, notImplemented("UserService", "CountUsers")
+	}
+	return m.CountUsersFunc(ctx, filter)
+}
+
+// GetUser calls GetUserFunc.
+func (m *UserService) GetUser(ctx context.Context, id int, fields safeguard.Fields) (safeguard.User, error) {
+	m.record("GetUser", id, fields)
+	if m.GetUserFunc == nil {
+		return safeguard.User{}, notImplemented("UserService", "GetUser")
+	}
+	return m.GetUserFunc(ctx, id, fields)
+}
+
+// CreateUser calls CreateUserFunc.
+func (m *UserService) CreateUser(ctx context.Context, user safeguard.User) (safeguard.User, error) {
+	m.record("CreateUser", user)
+	if m.CreateUserFunc == nil {
+		return safeguard.User{}, notImplemented("UserService", "CreateUser")
+	}
+	return m.CreateUserFunc(ctx, user)
+}
+
+// DeleteUser calls DeleteUserFunc.
+func (m *UserService) DeleteUser(ctx context.Context, id int) error {
+	m.record("DeleteUser", id)
+	if m.DeleteUserFunc == nil {
+		return notImplemented("UserService", "DeleteUser")
+	}
+	return m.DeleteUserFunc(ctx, id)
+}
+
+// GetLinkedAccounts calls GetLinkedAccountsFunc.
+func (m *UserService) GetLinkedAccounts(ctx context.Context, id string) ([]safeguard.PolicyAccount, error) {
+	m.record("GetLinkedAccounts", id)
+	if m.GetLinkedAccountsFunc == nil {
+		return nil, notImplemented("UserService", "GetLinkedAccounts")
+	}
+	return m.GetLinkedAccountsFunc(ctx, id)
+}
+
+// AddLinkedAccounts calls AddLinkedAccountsFunc.
+func (m *UserService) AddLinkedAccounts(ctx context.Context, user safeguard.User, policyAccount []safeguard.PolicyAccount) ([]safeguard.PolicyAccount, error) {
+	m.record("AddLinkedAccounts", user, policyAccount)
+	if m.AddLinkedAccountsFunc == nil {
+		return nil, notImplemented("UserService", "AddLinkedAccounts")
+	}
+	return m.AddLinkedAccountsFunc(ctx, user, policyAccount)
+}
+
+// RemoveLinkedAccounts calls RemoveLinkedAccountsFunc.
+func (m *UserService) RemoveLinkedAccounts(ctx context.Context, user safeguard.User, policyAccount []safeguard.PolicyAccount) ([]safeguard.PolicyAccount, error) {
+	m.record("RemoveLinkedAccounts", user, policyAccount)
+	if m.RemoveLinkedAccountsFunc == nil {
+		return nil, notImplemented("UserService", "RemoveLinkedAccounts")
+	}
+	return m.RemoveLinkedAccountsFunc(ctx, user, policyAccount)
+}
+
+// GetUserRoles calls GetUserRolesFunc.
+func (m *UserService) GetUserRoles(ctx context.Context, id string) ([]safeguard.Role, error) {
+	m.record("GetUserRoles", id)
+	if m.GetUserRolesFunc == nil {
+		return nil, notImplemented("UserService", "GetUserRoles")
+	}
+	return m.GetUserRolesFunc(ctx, id)
+}
+
+// GetGroups calls GetGroupsFunc.
+func (m *UserService) GetGroups(ctx context.Context, id string) ([]safeguard.UserGroup, error) {
+	m.record("GetGroups", id)
+	if m.GetGroupsFunc == nil {
+		return nil, notImplemented("UserService", "GetGroups")
+	}
+	return m.GetGroupsFunc(ctx, id)
+}
+
+// GetUserPreferences calls GetUserPreferencesFunc.
+func (m *UserService) GetUserPreferences(ctx context.Context, id int) ([]safeguard.Preference, error) {
+	m.record("GetUserPreferences", id)
+	if m.GetUserPreferencesFunc == nil {
+		return nil, notImplemented("UserService", "GetUserPreferences")
+	}
+	return m.GetUserPreferencesFunc(ctx, id)
+}
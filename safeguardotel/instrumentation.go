@@ -0,0 +1,248 @@
+// Package safeguardotel reports the API calls, logins, token refreshes and
+// SignalR events of a safeguard.SafeguardClient to OpenTelemetry. Keeping the
+// adapter in its own package means the safeguard package itself does not
+// depend on OpenTelemetry.
+//
+// Example:
+//
+//	instrumentation, err := safeguardotel.New()
+//	if err != nil {
+//	    return err
+//	}
+//	client, err := safeguard.NewClientWithOptions(url,
+//	    safeguard.WithInstrumentation(instrumentation),
+//	)
+package safeguardotel
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sthayduk/safeguard-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/sthayduk/safeguard-go/safeguardotel"
+
+// Attribute keys specific to Safeguard.
+const (
+	// NodeKey is the role of the node that served an API call, see safeguard.NodeRole.
+	NodeKey = attribute.Key("safeguard.node")
+	// LoginMethodKey is the method of a login, see safeguard.LoginMethod.
+	LoginMethodKey = attribute.Key("safeguard.login.method")
+	// ReconnectKey is whether a SignalR connection replaced an earlier one.
+	ReconnectKey = attribute.Key("safeguard.signalr.reconnect")
+	// EventNameKey is the name of a SignalR event.
+	EventNameKey = attribute.Key("safeguard.event.name")
+)
+
+// Metric names.
+const (
+	// APICallDuration is a histogram of the duration of API calls in seconds,
+	// including retries. Calls that failed have the error.type attribute.
+	APICallDuration = "safeguard.api_call.duration"
+	// LoginDuration is a histogram of the duration of logins in seconds.
+	LoginDuration = "safeguard.login.duration"
+	// TokenRefreshes counts the logins that renewed the token of a client.
+	TokenRefreshes = "safeguard.token.refreshes"
+	// SignalRConnections counts the SignalR connections, including reconnects.
+	SignalRConnections = "safeguard.signalr.connections"
+	// EventsDropped counts the SignalR events dropped because the event channel was full.
+	EventsDropped = "safeguard.events.dropped"
+)
+
+// Option configures an Instrumentation.
+type Option func(*config)
+
+// config holds the settings applied by Options.
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider creates spans with the given provider instead of the
+// global one.
+//
+// Parameters:
+//   - provider: The tracer provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider records metrics with the given provider instead of the
+// global one.
+//
+// Parameters:
+//   - provider: The meter provider.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// Instrumentation implements safeguard.Instrumentation with OpenTelemetry.
+// API calls become client spans named after their method and route template,
+// logins become internal spans, and token refreshes, SignalR connections and
+// dropped events are added as events to the current span. All of them are
+// also recorded as metrics.
+type Instrumentation struct {
+	tracer             trace.Tracer
+	apiCallDuration    metric.Float64Histogram
+	loginDuration      metric.Float64Histogram
+	tokenRefreshes     metric.Int64Counter
+	signalRConnections metric.Int64Counter
+	eventsDropped      metric.Int64Counter
+}
+
+var _ safeguard.Instrumentation = (*Instrumentation)(nil)
+
+// New creates an Instrumentation that uses the global tracer and meter
+// providers unless options say otherwise.
+//
+// Parameters:
+//   - opts: Options to configure the instrumentation.
+//
+// Returns:
+//   - *Instrumentation: The instrumentation, to be passed to safeguard.WithInstrumentation.
+//   - error: An error if an instrument cannot be created.
+func New(opts ...Option) (*Instrumentation, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	i := &Instrumentation{tracer: cfg.tracerProvider.Tracer(ScopeName)}
+
+	var err error
+	if i.apiCallDuration, err = meter.Float64Histogram(APICallDuration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of Safeguard API calls, including retries."),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", APICallDuration, err)
+	}
+	if i.loginDuration, err = meter.Float64Histogram(LoginDuration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of Safeguard logins."),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", LoginDuration, err)
+	}
+	if i.tokenRefreshes, err = meter.Int64Counter(TokenRefreshes,
+		metric.WithUnit("{refresh}"),
+		metric.WithDescription("Logins that renewed the Safeguard token of a client."),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", TokenRefreshes, err)
+	}
+	if i.signalRConnections, err = meter.Int64Counter(SignalRConnections,
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("SignalR connections created, including reconnects."),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", SignalRConnections, err)
+	}
+	if i.eventsDropped, err = meter.Int64Counter(EventsDropped,
+		metric.WithUnit("{event}"),
+		metric.WithDescription("SignalR events dropped because the event channel was full."),
+	); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", EventsDropped, err)
+	}
+	return i, nil
+}
+
+// StartAPICall implements safeguard.Instrumentation.
+func (i *Instrumentation) StartAPICall(ctx context.Context, call safeguard.APICall) (context.Context, func(safeguard.APICallResult)) {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(call.Method),
+		semconv.URLTemplate(call.Route),
+		semconv.ServerAddress(call.Host),
+		NodeKey.String(string(call.Node)),
+	}
+	ctx, span := i.tracer.Start(ctx, call.Method+" "+call.Route,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, func(r safeguard.APICallResult) {
+		if r.StatusCode != 0 {
+			attrs = append(attrs, semconv.HTTPResponseStatusCode(r.StatusCode))
+		}
+		if r.Err != nil {
+			attrs = append(attrs, errorType(r))
+			span.RecordError(r.Err)
+			span.SetStatus(codes.Error, r.Err.Error())
+		}
+		span.SetAttributes(attrs...)
+		if r.Attempts > 1 {
+			span.SetAttributes(semconv.HTTPRequestResendCount(r.Attempts - 1))
+		}
+		span.End()
+
+		i.apiCallDuration.Record(ctx, r.Duration.Seconds(), metric.WithAttributes(attrs...))
+	}
+}
+
+// StartLogin implements safeguard.Instrumentation.
+func (i *Instrumentation) StartLogin(ctx context.Context, method safeguard.LoginMethod) (context.Context, func(error)) {
+	attrs := []attribute.KeyValue{LoginMethodKey.String(string(method))}
+	ctx, span := i.tracer.Start(ctx, "safeguard.login", trace.WithAttributes(attrs...))
+
+	start := time.Now()
+	return ctx, func(err error) {
+		if err != nil {
+			attrs = append(attrs, semconv.ErrorType(err))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+
+		i.loginDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	}
+}
+
+// TokenRefreshed implements safeguard.Instrumentation.
+func (i *Instrumentation) TokenRefreshed(ctx context.Context, method safeguard.LoginMethod, err error) {
+	attrs := []attribute.KeyValue{LoginMethodKey.String(string(method))}
+	if err != nil {
+		attrs = append(attrs, semconv.ErrorType(err))
+	}
+	trace.SpanFromContext(ctx).AddEvent("safeguard.token_refreshed", trace.WithAttributes(attrs...))
+	i.tokenRefreshes.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+// SignalRConnected implements safeguard.Instrumentation.
+func (i *Instrumentation) SignalRConnected(ctx context.Context, reconnect bool, err error) {
+	attrs := []attribute.KeyValue{ReconnectKey.Bool(reconnect)}
+	if err != nil {
+		attrs = append(attrs, semconv.ErrorType(err))
+	}
+	trace.SpanFromContext(ctx).AddEvent("safeguard.signalr_connected", trace.WithAttributes(attrs...))
+	i.signalRConnections.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+// EventDropped implements safeguard.Instrumentation.
+func (i *Instrumentation) EventDropped(ctx context.Context, event safeguard.SignalREvent) {
+	attrs := []attribute.KeyValue{EventNameKey.String(event.Name)}
+	trace.SpanFromContext(ctx).AddEvent("safeguard.event_dropped", trace.WithAttributes(attrs...))
+	i.eventsDropped.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+// errorType returns the error.type attribute of a failed API call: the status
+// code for HTTP errors and the Go type of the error otherwise.
+func errorType(r safeguard.APICallResult) attribute.KeyValue {
+	if r.StatusCode >= 400 {
+		return semconv.ErrorTypeKey.String(strconv.Itoa(r.StatusCode))
+	}
+	return semconv.ErrorType(r.Err)
+}
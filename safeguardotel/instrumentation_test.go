@@ -0,0 +1,221 @@
+package safeguardotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sthayduk/safeguard-go"
+	"github.com/sthayduk/safeguard-go/sgfake"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestInstrumentation returns an Instrumentation that records into memory.
+func newTestInstrumentation(t *testing.T) (*Instrumentation, *tracetest.InMemoryExporter, *sdkmetric.ManualReader, trace.Tracer) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() {
+		tracerProvider.Shutdown(context.Background())
+		meterProvider.Shutdown(context.Background())
+	})
+
+	i, err := New(WithTracerProvider(tracerProvider), WithMeterProvider(meterProvider))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return i, exporter, reader, tracerProvider.Tracer("test")
+}
+
+// collect returns the metrics recorded by reader, keyed by name.
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	metrics := map[string]metricdata.Aggregation{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+// findSpan returns the first span with the given name.
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("no span %q recorded", name)
+	return tracetest.SpanStub{}
+}
+
+// hasAttribute reports whether attrs contain the given attribute.
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInstrumentationWithClient(t *testing.T) {
+	i, exporter, reader, tracer := newTestInstrumentation(t)
+
+	server := sgfake.NewServer()
+	defer server.Close()
+	client, err := server.NewClient(safeguard.WithInstrumentation(i))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close(context.Background())
+
+	ctx, job := tracer.Start(context.Background(), "job")
+	if err := client.LoginWithPasswordContext(ctx, sgfake.DefaultUsername, sgfake.DefaultPassword); err != nil {
+		t.Fatalf("LoginWithPasswordContext() error = %v", err)
+	}
+	if _, err := client.GetMe(ctx, safeguard.Filter{}); err != nil {
+		t.Fatalf("GetMe() error = %v", err)
+	}
+	if _, err := client.GetAsset(ctx, 999, nil); err == nil {
+		t.Fatal("expected an error for a missing asset")
+	}
+	job.End()
+
+	spans := exporter.GetSpans()
+	jobSpan := findSpan(t, spans, "job")
+
+	login := findSpan(t, spans, "safeguard.login")
+	if login.Parent.SpanID() != jobSpan.SpanContext.SpanID() {
+		t.Error("expected the login span to be a child of the caller's span")
+	}
+	if !hasAttribute(login.Attributes, LoginMethodKey.String("password")) {
+		t.Errorf("unexpected login attributes %v", login.Attributes)
+	}
+
+	me := findSpan(t, spans, "GET /service/core/v4/me")
+	if me.SpanKind != trace.SpanKindClient || me.Parent.SpanID() != jobSpan.SpanContext.SpanID() {
+		t.Errorf("unexpected API call span kind %v or parent %v", me.SpanKind, me.Parent.SpanID())
+	}
+	for _, want := range []attribute.KeyValue{
+		attribute.String("http.request.method", "GET"),
+		attribute.String("url.template", "/service/core/v4/me"),
+		attribute.Int("http.response.status_code", 200),
+		NodeKey.String(string(safeguard.NodeAppliance)),
+	} {
+		if !hasAttribute(me.Attributes, want) {
+			t.Errorf("API call span is missing %v, got %v", want, me.Attributes)
+		}
+	}
+
+	missing := findSpan(t, spans, "GET /service/core/v4/Assets/{id}")
+	if missing.Status.Code != codes.Error || !hasAttribute(missing.Attributes, attribute.String("error.type", "404")) {
+		t.Errorf("expected a failed span with error.type 404, got %v %v", missing.Status, missing.Attributes)
+	}
+
+	metrics := collect(t, reader)
+	calls, ok := metrics[APICallDuration].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("no %s histogram recorded", APICallDuration)
+	}
+	var total, failed uint64
+	for _, point := range calls.DataPoints {
+		total += point.Count
+		if _, isError := point.Attributes.Value("error.type"); isError {
+			failed += point.Count
+		}
+	}
+	if total < 2 || failed != 1 {
+		t.Errorf("expected at least 2 API calls with 1 failure, got %d with %d failures", total, failed)
+	}
+	if logins, ok := metrics[LoginDuration].(metricdata.Histogram[float64]); !ok || len(logins.DataPoints) != 1 || logins.DataPoints[0].Count != 1 {
+		t.Errorf("expected one login, got %+v", metrics[LoginDuration])
+	}
+}
+
+func TestInstrumentationCounters(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(ctx context.Context, i *Instrumentation)
+		metric string
+		event  string
+		want   attribute.KeyValue
+	}{
+		{
+			name: "token refresh",
+			record: func(ctx context.Context, i *Instrumentation) {
+				i.TokenRefreshed(ctx, safeguard.LoginMethodCertificate, nil)
+			},
+			metric: TokenRefreshes,
+			event:  "safeguard.token_refreshed",
+			want:   LoginMethodKey.String("certificate"),
+		},
+		{
+			name: "failed token refresh",
+			record: func(ctx context.Context, i *Instrumentation) {
+				i.TokenRefreshed(ctx, safeguard.LoginMethodPassword, errors.New("denied"))
+			},
+			metric: TokenRefreshes,
+			event:  "safeguard.token_refreshed",
+			want:   attribute.String("error.type", "*errors.errorString"),
+		},
+		{
+			name: "signalr reconnect",
+			record: func(ctx context.Context, i *Instrumentation) {
+				i.SignalRConnected(ctx, true, nil)
+			},
+			metric: SignalRConnections,
+			event:  "safeguard.signalr_connected",
+			want:   ReconnectKey.Bool(true),
+		},
+		{
+			name: "dropped event",
+			record: func(ctx context.Context, i *Instrumentation) {
+				i.EventDropped(ctx, safeguard.SignalREvent{Name: "AccessRequestCreated"})
+			},
+			metric: EventsDropped,
+			event:  "safeguard.event_dropped",
+			want:   EventNameKey.String("AccessRequestCreated"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i, exporter, reader, tracer := newTestInstrumentation(t)
+
+			ctx, span := tracer.Start(context.Background(), "job")
+			tt.record(ctx, i)
+			span.End()
+
+			sum, ok := collect(t, reader)[tt.metric].(metricdata.Sum[int64])
+			if !ok || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+				t.Fatalf("expected %s to be 1, got %+v", tt.metric, sum)
+			}
+			if attrs := sum.DataPoints[0].Attributes.ToSlice(); !hasAttribute(attrs, tt.want) {
+				t.Errorf("expected attribute %v, got %v", tt.want, attrs)
+			}
+
+			events := findSpan(t, exporter.GetSpans(), "job").Events
+			if len(events) != 1 || events[0].Name != tt.event {
+				t.Errorf("expected span event %q, got %+v", tt.event, events)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package safeguard
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// The service interfaces group the operations of SafeguardClient by resource, so
+// that code can depend on the narrow set of operations it uses and be unit
+// tested without an appliance, e.g. with the mocks in the safeguardmock package.
+//
+// Resources returned by a service carry the client that fetched them, so their
+// own methods (e.g. AccessRequest.CheckIn) always use that client. Code that
+// should be testable with a mock calls the service methods instead.
+
+// AssetService provides the operations on assets.
+type AssetService interface {
+	GetAssets(ctx context.Context, filter Filter) ([]Asset, error)
+	Assets(ctx context.Context, filter Filter) iter.Seq2[Asset, error]
+	CountAssets(ctx context.Context, filter Filter) (int, error)
+	GetAsset(ctx context.Context, id int, fields Fields) (Asset, error)
+	UpdateAsset(ctx context.Context, id int, updatedAsset Asset) (Asset, error)
+	GetAssetDirectoryAccounts(ctx context.Context, assetId int, filter Filter) ([]AssetAccount, error)
+	GetAssetDirectoryAssets(ctx context.Context, assetId int, filter Filter) ([]Asset, error)
+	GetAssetDirectoryServiceEntries(ctx context.Context, assetId int, filter Filter) ([]DirectoryServiceEntry, error)
+}
+
+// AccountService provides the operations on asset accounts, including their
+// password tasks.
+type AccountService interface {
+	GetAssetAccounts(ctx context.Context, filter Filter) ([]AssetAccount, error)
+	AssetAccounts(ctx context.Context, filter Filter) iter.Seq2[AssetAccount, error]
+	CountAssetAccounts(ctx context.Context, filter Filter) (int, error)
+	GetAssetAccount(ctx context.Context, id int, fields Fields) (AssetAccount, error)
+	CreateAssetAccount(ctx context.Context, assetAccount AssetAccount) (AssetAccount, error)
+	CreateAssetAccounts(ctx context.Context, assetAccounts []AssetAccount) ([]AssetAccount, error)
+	UpdateAssetAccount(ctx context.Context, assetAccount AssetAccount) (AssetAccount, error)
+	DeleteAssetAccount(ctx context.Context, id int) error
+	UpdatePasswordProfile(ctx context.Context, assetAccount AssetAccount, passwordPolicy AccountPasswordRule) (AssetAccount, error)
+	EnableAssetAccount(ctx context.Context, assetAccount AssetAccount) (AssetAccount, error)
+	DisableAssetAccount(ctx context.Context, assetAccount AssetAccount) (AssetAccount, error)
+	SuspendAssetAccount(ctx context.Context, a AssetAccount) (ActivityLog, error)
+	ChangeAssetAccountPassword(ctx context.Context, a AssetAccount) (ActivityLog, error)
+	CheckAssetAccountPassword(ctx context.Context, a AssetAccount) (ActivityLog, error)
+}
+
+// AccessRequestService provides the access request workflow: finding the
+// accounts the current user may request, creating requests, checking out
+// passwords and closing requests.
+//
+// Example:
+//
+//	func releasePassword(ctx context.Context, requests safeguard.AccessRequestService, account safeguard.AccountEntitlement) (string, error) {
+//	    responses, err := requests.NewAccessRequests(ctx, []safeguard.AccountEntitlement{account}, time.Hour)
+//	    if err != nil {
+//	        return "", err
+//	    }
+//	    return requests.CheckOutPassword(ctx, responses[0].Response, true)
+//	}
+type AccessRequestService interface {
+	GetMeAccountEntitlements(ctx context.Context, accessRequestType AccessRequestType, includeActiveRequests bool, filterByCredential bool, filter Filter) ([]AccountEntitlement, error)
+	GetAccessRequests(ctx context.Context, filter Filter) ([]AccessRequest, error)
+	AccessRequests(ctx context.Context, filter Filter) iter.Seq2[AccessRequest, error]
+	CountAccessRequests(ctx context.Context, filter Filter) (int, error)
+	GetAccessRequest(ctx context.Context, id string, fields Fields) (AccessRequest, error)
+	NewAccessRequests(ctx context.Context, accountEntitlements []AccountEntitlement, requestDuration time.Duration) ([]AccessRequestBatchResponse, error)
+	CheckOutPassword(ctx context.Context, accessRequest AccessRequest, shouldWaitForPending bool) (string, error)
+	CheckInAccessRequest(ctx context.Context, id string) (AccessRequest, error)
+	CancelAccessRequest(ctx context.Context, id string) (AccessRequest, error)
+}
+
+// UserService provides the operations on users and the current user.
+type UserService interface {
+	GetMe(ctx context.Context, filter Filter) (User, error)
+	GetUsers(ctx context.Context, filter Filter) ([]User, error)
+	Users(ctx context.Context, filter Filter) iter.Seq2[User, error]
+	CountUsers(ctx context.Context, filter Filter) (int, error)
+	GetUser(ctx context.Context, id int, fields Fields) (User, error)
+	CreateUser(ctx context.Context, user User) (User, error)
+	DeleteUser(ctx context.Context, id int) error
+	GetLinkedAccounts(ctx context.Context, id string) ([]PolicyAccount, error)
+	AddLinkedAccounts(ctx context.Context, user User, policyAccount []PolicyAccount) ([]PolicyAccount, error)
+	RemoveLinkedAccounts(ctx context.Context, user User, policyAccount []PolicyAccount) ([]PolicyAccount, error)
+	GetUserRoles(ctx context.Context, id string) ([]Role, error)
+	GetGroups(ctx context.Context, id string) ([]UserGroup, error)
+	GetUserPreferences(ctx context.Context, id int) ([]Preference, error)
+}
+
+// PolicyService provides the operations on access policies and the assets and
+// accounts they grant access to.
+type PolicyService interface {
+	GetAccessPolicies(ctx context.Context, filter Filter) ([]AccessPolicy, error)
+	AccessPolicies(ctx context.Context, filter Filter) iter.Seq2[AccessPolicy, error]
+	CountAccessPolicies(ctx context.Context, filter Filter) (int, error)
+	GetAccessPolicy(ctx context.Context, id int, fields Fields) (AccessPolicy, error)
+	UpdateAccessPolicy(ctx context.Context, id int, updatedAccessPolicy AccessPolicy) (AccessPolicy, error)
+	DeleteAccessPolicy(ctx context.Context, id int) error
+	GetPolicyAssets(ctx context.Context, filter Filter) ([]PolicyAsset, error)
+	PolicyAssets(ctx context.Context, filter Filter) iter.Seq2[PolicyAsset, error]
+	CountPolicyAssets(ctx context.Context, filter Filter) (int, error)
+	GetPolicyAsset(ctx context.Context, id int, fields Fields) (PolicyAsset, error)
+	GetPolicyAccounts(ctx context.Context, filter Filter) ([]PolicyAccount, error)
+	PolicyAccounts(ctx context.Context, filter Filter) iter.Seq2[PolicyAccount, error]
+	CountPolicyAccounts(ctx context.Context, filter Filter) (int, error)
+	GetPolicyAccount(ctx context.Context, id int, fields Fields) (PolicyAccount, error)
+}
+
+// SafeguardClient implements all service interfaces.
+var (
+	_ AssetService         = (*SafeguardClient)(nil)
+	_ AccountService       = (*SafeguardClient)(nil)
+	_ AccessRequestService = (*SafeguardClient)(nil)
+	_ UserService          = (*SafeguardClient)(nil)
+	_ PolicyService        = (*SafeguardClient)(nil)
+)
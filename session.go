@@ -0,0 +1,220 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// refreshMargin is how long before the token expires it is refreshed.
+	refreshMargin = time.Minute
+	// minRefreshWait is the shortest time between two refresh attempts, so a
+	// failing refresh or a short-lived token does not flood the appliance.
+	minRefreshWait = time.Second
+)
+
+// errNoRefreshToken is returned when a token has to be renewed with the refresh
+// token grant but RSTS did not issue a refresh token.
+var errNoRefreshToken = errors.New("no refresh token available")
+
+// errNotRepeatable is returned when a token has to be renewed but there is
+// neither a refresh token nor a login that can be replayed.
+var errNotRepeatable = errors.New("the token cannot be renewed without logging in again")
+
+// session owns the authentication state of a client: how the current token was
+// obtained, how to renew it and which token generation is current. Tokens are
+// renewed with the RSTS refresh token; credentials are only replayed if the
+// client was created with WithCredentialReplay.
+// Logins and re-authentications are serialised, so a client can log in again,
+// switch identities or renew its token from several goroutines at once.
+type session struct {
+	sync.Mutex // Held for the duration of a login
+
+	method     LoginMethod                     // Method of the last successful login
+	relogin    func(ctx context.Context) error // Renews the token; nil if it cannot be renewed
+	repeatable atomic.Bool                     // Whether relogin is set, readable without waiting for a login
+	generation atomic.Uint64                   // Incremented by every successful login
+	expiry     atomic.Int64                    // Expiry of the token in Unix nanoseconds; 0 if unknown
+}
+
+// login runs a login while holding the session lock and prepares the renewal
+// of its token. If the client has a token cache, the token of the login is cached.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the login requests.
+//   - method: The login method, reported to the instrumentation on refreshes.
+//   - login: Performs the login and stores the new token in c.AccessToken.
+//   - replay: Repeats the login if the token cannot be renewed with the refresh
+//     token, or nil if the login cannot be repeated.
+//
+// Returns:
+//   - error: The error of login.
+func (c *SafeguardClient) login(ctx context.Context, method LoginMethod, login func(ctx context.Context) error, replay func(ctx context.Context) error) error {
+	c.session.Lock()
+	defer c.session.Unlock()
+
+	if err := c.startSession(ctx, method, login, replay); err != nil {
+		return err
+	}
+	c.storeCachedToken(ctx)
+	return nil
+}
+
+// startSession performs the work of login. The caller must hold the session lock.
+func (c *SafeguardClient) startSession(ctx context.Context, method LoginMethod, login func(ctx context.Context) error, replay func(ctx context.Context) error) error {
+	if err := login(ctx); err != nil {
+		return err
+	}
+
+	c.session.method = method
+	c.session.relogin = nil
+	if replay != nil || c.AccessToken.getRefreshToken() != "" {
+		c.session.relogin = c.renewal(replay)
+	}
+	c.session.repeatable.Store(c.session.relogin != nil)
+	c.recordExpiry()
+	c.session.generation.Add(1)
+	c.signalAuthDone()
+	return nil
+}
+
+// recordExpiry copies the expiry of the current token into the session, so the
+// background refresh can schedule itself without reading c.AccessToken, which
+// callers may replace. The caller must hold the session lock.
+func (c *SafeguardClient) recordExpiry() {
+	var expiry int64
+	if authTime, lifetime := c.AccessToken.getLifetime(); lifetime > 0 {
+		expiry = authTime.Add(lifetime).UnixNano()
+	}
+	c.session.expiry.Store(expiry)
+}
+
+// credentialReplay returns replay if the client keeps credentials to repeat
+// logins, see WithCredentialReplay, and nil otherwise.
+func (c *SafeguardClient) credentialReplay(replay func(ctx context.Context) error) func(ctx context.Context) error {
+	if !c.replay {
+		return nil
+	}
+	return replay
+}
+
+// renewal returns a function that renews the token with the RSTS refresh token
+// and falls back to replay if that fails.
+//
+// Parameters:
+//   - replay: Repeats the last login, or nil.
+//
+// Returns:
+//   - func(ctx context.Context) error: Renews the token.
+func (c *SafeguardClient) renewal(replay func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		err := errNoRefreshToken
+		if c.AccessToken.getRefreshToken() != "" {
+			err = c.loginWithRefreshToken(ctx)
+		}
+		if err == nil || replay == nil {
+			return err
+		}
+
+		c.logger().Warn("Refresh token grant failed, logging in again", "error", err)
+		return replay(ctx)
+	}
+}
+
+// reauthenticate renews the token of the last login. If the token was
+// already renewed since generation was read, nothing is done, so concurrent
+// callers that saw the same expired token cause a single login.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the login requests.
+//   - generation: The token generation the caller found to be expired.
+//
+// Returns:
+//   - error: errNotRepeatable if the token cannot be renewed, or the error of the renewal.
+func (c *SafeguardClient) reauthenticate(ctx context.Context, generation uint64) error {
+	c.session.Lock()
+	defer c.session.Unlock()
+
+	if c.session.generation.Load() != generation {
+		return nil
+	}
+	if c.session.relogin == nil {
+		return errNotRepeatable
+	}
+
+	err := c.session.relogin(ctx)
+	c.instrumentation().TokenRefreshed(ctx, c.session.method, err)
+	if err != nil {
+		c.logger().Error("Failed to renew token", "method", c.session.method, "error", err)
+		return err
+	}
+
+	c.logger().Debug("Token renewed", "method", c.session.method)
+	c.storeCachedToken(ctx)
+	c.recordExpiry()
+	c.session.generation.Add(1)
+	c.signalAuthDone()
+	return nil
+}
+
+// canRefresh reports whether the token can be renewed without the user.
+func (c *SafeguardClient) canRefresh() bool {
+	return c.session.repeatable.Load()
+}
+
+// signalAuthDone notifies the background token refresh that a login has
+// completed, so it schedules the next refresh for the new token. It never blocks
+// and does nothing if background refresh is disabled.
+func (c *SafeguardClient) signalAuthDone() {
+	if c.authDone == nil {
+		return
+	}
+	select {
+	case c.authDone <- struct{}{}:
+	default:
+		// A notification is already pending
+	}
+}
+
+// refreshToken renews the token of the client shortly before it expires. It
+// waits for a login that can be repeated without the user and reschedules
+// itself after every login. It returns when ctx is done, which happens when the
+// client is closed.
+//
+// Parameters:
+//   - ctx: The context to control the lifecycle of the token refresh process.
+func (c *SafeguardClient) refreshToken(ctx context.Context) {
+	for {
+		expiry := c.session.expiry.Load()
+		if !c.canRefresh() || expiry == 0 {
+			// Tokens without a known lifetime are only renewed when rejected
+			c.logger().Debug("token refresh waiting for login")
+			select {
+			case <-c.authDone:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		wait := max(time.Until(time.Unix(0, expiry))-refreshMargin, minRefreshWait)
+		c.logger().Debug("token refresh scheduled", "in", wait)
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+
+		case <-c.authDone:
+			// Logged in again, schedule the refresh for the new token
+			timer.Stop()
+
+		case <-timer.C:
+			c.reauthenticate(ctx, c.session.generation.Load())
+		}
+	}
+}
@@ -0,0 +1,455 @@
+package safeguard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer is an appliance that issues tokens for password logins,
+// authorization codes of the form "code-<username>" and refresh token grants,
+// and serves the "Me" endpoint to holders of a valid user token.
+type fakeAuthServer struct {
+	*httptest.Server
+	mux *http.ServeMux // Tests may add endpoints
+
+	mu            sync.Mutex
+	expiresIn     int
+	refreshTokens bool // Whether refresh tokens are issued
+	issued        int
+	logins        []string          // Usernames of all password, authorization code and certificate logins
+	redirectURI   string            // Redirect URI of the last authorization code grant
+	codeVerifier  string            // PKCE code verifier of the last authorization code grant
+	scope         string            // Scope of the last password login
+	refreshes     int               // Number of refresh token grants
+	sts           map[string]string // STS access token to username
+	refresh       map[string]string // Refresh token to username
+	tokens        map[string]string // User token to username
+}
+
+func newFakeAuthServer(t *testing.T, expiresIn int, refreshTokens bool) *fakeAuthServer {
+	t.Helper()
+
+	f := &fakeAuthServer{
+		expiresIn:     expiresIn,
+		refreshTokens: refreshTokens,
+		sts:           map[string]string{},
+		refresh:       map[string]string{},
+		tokens:        map[string]string{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /RSTS/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		grantType := r.FormValue("grant_type")
+		if r.Header.Get("Content-Type") == "application/json" {
+			var body struct {
+				GrantType string `json:"grant_type"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			grantType = body.GrantType
+		}
+
+		var username string
+		switch grantType {
+		case "password":
+			username = r.FormValue("username")
+			if r.FormValue("password") != username+"-password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			f.scope = r.FormValue("scope")
+			f.logins = append(f.logins, username)
+		case "authorization_code":
+			var ok bool
+			if username, ok = strings.CutPrefix(r.FormValue("code"), "code-"); !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			f.redirectURI, f.codeVerifier = r.FormValue("redirect_uri"), r.FormValue("code_verifier")
+			f.logins = append(f.logins, username)
+		case "client_credentials":
+			// The certificate login, served over TLS by newFakeCertServer
+			if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			username = r.TLS.PeerCertificates[0].Subject.CommonName
+			f.logins = append(f.logins, username)
+		case "refresh_token":
+			var ok bool
+			if username, ok = f.refresh[r.FormValue("refresh_token")]; !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			delete(f.refresh, r.FormValue("refresh_token"))
+			f.refreshes++
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		f.issued++
+		response := map[string]any{
+			"access_token": fmt.Sprintf("sts-%s-%d", username, f.issued),
+			"expires_in":   f.expiresIn,
+			"scope":        AuthProviderLocal.String(),
+		}
+		f.sts[response["access_token"].(string)] = username
+		if f.refreshTokens {
+			refreshToken := fmt.Sprintf("refresh-%s-%d", username, f.issued)
+			f.refresh[refreshToken] = username
+			response["refresh_token"] = refreshToken
+		}
+		json.NewEncoder(w).Encode(response)
+	})
+	mux.HandleFunc("POST /service/core/v4/Token/LoginResponse", func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ StsAccessToken string }
+		json.NewDecoder(r.Body).Decode(&body)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		username, ok := f.sts[body.StsAccessToken]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		token := strings.Replace(body.StsAccessToken, "sts-", "user-", 1)
+		f.tokens[token] = username
+		json.NewEncoder(w).Encode(map[string]any{"UserToken": token})
+	})
+	me := func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		username, ok := f.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+		f.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"Name": username})
+	}
+	mux.HandleFunc("GET /service/core/v4/Me", me)
+	mux.HandleFunc("GET /service/core/v4/me", me) // Used by ValidateAccessToken
+
+	f.mux = mux
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// revokeTokens invalidates all user tokens, as if they had expired.
+func (f *fakeAuthServer) revokeTokens() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clear(f.tokens)
+}
+
+// revokeRefreshTokens invalidates all refresh tokens.
+func (f *fakeAuthServer) revokeRefreshTokens() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clear(f.refresh)
+}
+
+// refreshCount returns the number of refresh token grants.
+func (f *fakeAuthServer) refreshCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.refreshes
+}
+
+// loginCount returns the number of password logins.
+func (f *fakeAuthServer) loginCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.logins)
+}
+
+// lastLogin returns the username of the last password login.
+func (f *fakeAuthServer) lastLogin() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.logins[len(f.logins)-1]
+}
+
+// newSessionTestClient creates a client with background refresh for the server.
+func newSessionTestClient(t *testing.T, f *fakeAuthServer, opts ...ClientOption) *SafeguardClient {
+	t.Helper()
+
+	client, err := NewClientWithOptions(f.URL, append(opts, WithHTTPClient(f.Client()))...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.ClusterLeader.setUrl(f.URL, -1)
+	t.Cleanup(func() { client.Close(context.Background()) })
+	return client
+}
+
+// me returns the name of the user the client is logged in as.
+func me(ctx context.Context, c *SafeguardClient) (string, error) {
+	body, err := c.GetRequest(ctx, "Me")
+	if err != nil {
+		return "", err
+	}
+	var user struct{ Name string }
+	err = json.Unmarshal(body, &user)
+	return user.Name, err
+}
+
+// withTimeout fails the test if fn does not return in time.
+func withTimeout(t *testing.T, d time.Duration, fn func()) {
+	t.Helper()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(d):
+		t.Fatal("timed out, the client is probably deadlocked")
+	}
+}
+
+func TestRepeatedLoginDoesNotBlock(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+
+	withTimeout(t, 5*time.Second, func() {
+		for range 3 {
+			if err := client.LoginWithPassword("alice", "alice-password"); err != nil {
+				t.Errorf("LoginWithPassword() error = %v", err)
+			}
+		}
+	})
+
+	if got := f.loginCount(); got != 3 {
+		t.Errorf("expected 3 logins, got %d", got)
+	}
+}
+
+func TestConcurrentLogins(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+
+	withTimeout(t, 10*time.Second, func() {
+		var wg sync.WaitGroup
+		for i := range 10 {
+			user := []string{"alice", "bob"}[i%2]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := client.LoginWithPassword(user, user+"-password"); err != nil {
+					t.Errorf("LoginWithPassword(%s) error = %v", user, err)
+				}
+				if _, err := me(context.Background(), client); err != nil {
+					t.Errorf("request after login failed: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	if got := client.session.generation.Load(); got < 10 {
+		t.Errorf("expected at least 10 token generations, got %d", got)
+	}
+	name, err := me(context.Background(), client)
+	if err != nil || name != f.lastLogin() {
+		t.Errorf("expected to be logged in as %s, got %q (%v)", f.lastLogin(), name, err)
+	}
+}
+
+func TestSwitchIdentity(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+	ctx := context.Background()
+
+	if err := client.LoginWithPassword("alice", "alice-password"); err != nil {
+		t.Fatalf("LoginWithPassword() error = %v", err)
+	}
+	if err := client.LoginWithPassword("bob", "bob-password"); err != nil {
+		t.Fatalf("LoginWithPassword() error = %v", err)
+	}
+	if name, err := me(ctx, client); err != nil || name != "bob" {
+		t.Fatalf("expected bob, got %q (%v)", name, err)
+	}
+
+	// A failed login keeps the previous session
+	if err := client.LoginWithPassword("alice", "wrong"); err == nil {
+		t.Fatal("expected an error for a wrong password")
+	}
+
+	f.revokeTokens()
+	if name, err := me(ctx, client); err != nil || name != "bob" {
+		t.Errorf("expected to log in again as bob, got %q (%v)", name, err)
+	}
+}
+
+func TestReauthenticateAfterUnauthorized(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+	instrumentation := &recordingRefreshes{}
+	client.Instrumentation = instrumentation
+
+	if err := client.LoginWithPassword("alice", "alice-password"); err != nil {
+		t.Fatalf("LoginWithPassword() error = %v", err)
+	}
+	f.revokeTokens()
+
+	withTimeout(t, 10*time.Second, func() {
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if name, err := me(context.Background(), client); err != nil || name != "alice" {
+					t.Errorf("expected alice, got %q (%v)", name, err)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	if got := f.refreshCount(); got != 1 {
+		t.Errorf("expected a single refresh after the token was rejected, got %d", got)
+	}
+	if got := f.loginCount(); got != 1 {
+		t.Errorf("expected the password not to be sent again, got %d logins", got)
+	}
+	if got := instrumentation.count(); got != 1 {
+		t.Errorf("expected 1 token refresh to be reported, got %d", got)
+	}
+}
+
+func TestNoReauthenticationWithoutLogin(t *testing.T) {
+	var requests int
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	if _, err := client.GetRequest(context.Background(), "Me"); !IsUnauthorized(err) {
+		t.Errorf("expected an unauthorized error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestBackgroundRefresh(t *testing.T) {
+	// The token is refreshed a minute before it expires, i.e. after a second
+	f := newFakeAuthServer(t, 61, true)
+	client := newSessionTestClient(t, f)
+	instrumentation := &recordingRefreshes{}
+	client.Instrumentation = instrumentation
+
+	if err := client.LoginWithPassword("alice", "alice-password"); err != nil {
+		t.Fatalf("LoginWithPassword() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for f.refreshCount() < 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := f.refreshCount(); got < 1 {
+		t.Fatalf("expected the token to be refreshed, got %d refreshes", got)
+	}
+	if got := f.loginCount(); got != 1 {
+		t.Errorf("expected the password not to be sent again, got %d logins", got)
+	}
+	if name, err := me(context.Background(), client); err != nil || name != "alice" {
+		t.Errorf("expected alice after the refresh, got %q (%v)", name, err)
+	}
+
+	// Close stops the refresh
+	withTimeout(t, 5*time.Second, func() {
+		if err := client.Close(context.Background()); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	})
+	if instrumentation.count() < 1 {
+		t.Error("expected the refresh to be reported")
+	}
+}
+
+func TestCredentialsAreNotKept(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, false)
+	client := newSessionTestClient(t, f)
+
+	if err := client.LoginWithPassword("alice", "alice-password"); err != nil {
+		t.Fatalf("LoginWithPassword() error = %v", err)
+	}
+	if username, password := client.AccessToken.getUserNamePassword(); username != "" || password != "" {
+		t.Errorf("expected no stored credentials, got (%q, %q)", username, password)
+	}
+
+	// Without a refresh token the rejected token cannot be renewed
+	f.revokeTokens()
+	if _, err := me(context.Background(), client); !IsUnauthorized(err) {
+		t.Errorf("expected an unauthorized error, got %v", err)
+	}
+	if got := f.loginCount(); got != 1 {
+		t.Errorf("expected 1 login, got %d", got)
+	}
+}
+
+func TestCredentialReplay(t *testing.T) {
+	tests := []struct {
+		name          string
+		refreshTokens bool
+		wantRefreshes int
+	}{
+		{name: "no refresh token issued", refreshTokens: false},
+		{name: "refresh token rejected", refreshTokens: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeAuthServer(t, 3600, tt.refreshTokens)
+			client := newSessionTestClient(t, f, WithCredentialReplay())
+
+			if err := client.LoginWithPassword("alice", "alice-password"); err != nil {
+				t.Fatalf("LoginWithPassword() error = %v", err)
+			}
+			f.revokeTokens()
+			f.revokeRefreshTokens()
+
+			if name, err := me(context.Background(), client); err != nil || name != "alice" {
+				t.Errorf("expected alice, got %q (%v)", name, err)
+			}
+			if got := f.loginCount(); got != 2 {
+				t.Errorf("expected the login to be replayed, got %d logins", got)
+			}
+		})
+	}
+}
+
+// recordingRefreshes counts the token refreshes reported to the instrumentation.
+type recordingRefreshes struct {
+	NopInstrumentation
+
+	mu        sync.Mutex
+	refreshes int
+}
+
+func (r *recordingRefreshes) TokenRefreshed(ctx context.Context, method LoginMethod, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil && method == LoginMethodPassword {
+		r.refreshes++
+	}
+}
+
+func (r *recordingRefreshes) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.refreshes
+}
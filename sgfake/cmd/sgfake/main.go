@@ -0,0 +1,57 @@
+// Command sgfake runs a fake Safeguard appliance, e.g. to run the examples in CI.
+//
+// It writes the certificate of the fake to a file and prints the environment
+// variables the examples read. SSL_CERT_FILE makes clients that use the system
+// roots, such as those created by safeguard.NewClient, trust the fake:
+//
+//	cd examples
+//	go run github.com/sthayduk/safeguard-go/sgfake/cmd/sgfake > /tmp/sgfake.env &
+//	sleep 5 && . /tmp/sgfake.env
+//	go run ./me
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/sthayduk/safeguard-go"
+	"github.com/sthayduk/safeguard-go/sgfake"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:0", "address to listen on")
+	certPath := flag.String("cert", "sgfake.pem", "file to write the server certificate to")
+	seed := flag.Bool("seed", true, "add a sample asset and account")
+	flag.Parse()
+
+	server := sgfake.NewServer(sgfake.WithAddress(*addr))
+	defer server.Close()
+
+	certFile, err := filepath.Abs(*certPath)
+	if err != nil {
+		log.Fatalf("Invalid certificate path: %v", err)
+	}
+	if err := os.WriteFile(certFile, server.CertificatePEM(), 0o644); err != nil {
+		log.Fatalf("Failed to write certificate: %v", err)
+	}
+
+	if *seed {
+		asset := server.AddAsset(safeguard.Asset{Name: "linux01", NetworkAddress: "10.0.0.10", PlatformDisplayName: "Linux"})
+		server.AddAssetAccount(safeguard.AssetAccount{Name: "root", Asset: safeguard.Asset{Id: asset.Id}}, "Passw0rd!")
+	}
+
+	// SSL_CERT_FILE makes clients that use the system roots trust the fake
+	fmt.Printf("export SAFEGUARD_HOST_URL=%s\n", server.URL)
+	fmt.Printf("export SAFEGUARD_API_VERSION=v4\n")
+	fmt.Printf("export SAFEGUARD_USER_TOKEN=%s\n", server.UserToken())
+	fmt.Printf("export SSL_CERT_FILE=%s\n", certFile)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+}
@@ -0,0 +1,633 @@
+package sgfake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sthayduk/safeguard-go"
+)
+
+// Error codes returned by the fake in Safeguard error documents.
+const (
+	codeUnauthorized = 60108
+	codeNotFound     = 70000
+	codeBadRequest   = 90000
+	codeInvalidState = 60657
+)
+
+// userContextKey stores the authenticated user in the request context.
+type userContextKey struct{}
+
+// routes returns the handler serving all endpoints of the fake.
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	core := func(pattern string, handler http.HandlerFunc) {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.Handle(method+" /service/core/{version}/"+path, s.authenticated(handler))
+	}
+
+	mux.HandleFunc("POST /RSTS/oauth2/token", s.handleToken)
+	mux.HandleFunc("POST /service/core/{version}/Token/LoginResponse", s.handleLoginResponse)
+	core("POST Token/Logout", s.handleLogout)
+
+	core("GET me", s.handleMe)
+	core("GET me/AccountEntitlements", s.handleAccountEntitlements)
+
+	core("GET Assets", s.list(&s.assets))
+	core("POST Assets", s.create(&s.assets))
+	core("GET Assets/{id}", s.get(&s.assets))
+	core("PUT Assets/{id}", s.replace(&s.assets))
+	core("DELETE Assets/{id}", s.remove(&s.assets))
+	core("GET Assets/{id}/Accounts", s.handleAssetAccounts)
+
+	core("GET AssetAccounts", s.list(&s.accounts))
+	core("GET AssetAccounts/{id}", s.get(&s.accounts))
+	core("PUT AssetAccounts/{id}", s.replace(&s.accounts))
+	core("DELETE AssetAccounts/{id}", s.remove(&s.accounts))
+	core("POST AssetAccounts/BatchCreate", s.handleAccountBatchCreate)
+	core("POST AssetAccounts/{id}/{action}", s.handleAccountAction)
+
+	core("GET AccessRequests", s.list(&s.accessRequests))
+	core("GET AccessRequests/{id}", s.get(&s.accessRequests))
+	core("POST AccessRequests/BatchCreate", s.handleAccessRequestBatchCreate)
+	core("POST AccessRequests/{id}/{action}", s.handleAccessRequestAction)
+
+	core("GET Cluster/Members", s.list(&s.members))
+	core("GET Cluster/Members/Self", s.handleClusterSelf)
+	core("GET Cluster/Members/{id}", s.get(&s.members))
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("The fake appliance does not implement %s %s.", r.Method, r.URL.Path))
+	})
+	return mux
+}
+
+// authenticated rejects requests without a valid Safeguard user token.
+func (s *Server) authenticated(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		s.mu.Lock()
+		user := s.userTokens[token]
+		s.mu.Unlock()
+
+		if !ok || user == nil {
+			writeError(w, http.StatusUnauthorized, codeUnauthorized, "Authorization is required for this request.")
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, user)))
+	})
+}
+
+// handleToken implements the RSTS token endpoint for the password grant and the
+// client credentials grant used by certificate login.
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	var grantType, username, password string
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+		var body struct {
+			GrantType string `json:"grant_type"`
+			Username  string `json:"username"`
+			Password  string `json:"password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+			return
+		}
+		grantType, username, password = body.GrantType, body.Username, body.Password
+	} else {
+		if err := r.ParseForm(); err != nil {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+			return
+		}
+		grantType, username, password = r.PostForm.Get("grant_type"), r.PostForm.Get("username"), r.PostForm.Get("password")
+	}
+
+	var user *fakeUser
+	var scope safeguard.AuthProvider
+	switch grantType {
+	case "password":
+		s.mu.Lock()
+		if u := s.users[username]; u != nil && u.password == password {
+			user = u
+		}
+		s.mu.Unlock()
+		scope = safeguard.AuthProviderLocal
+	case "client_credentials":
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			user = &fakeUser{name: r.TLS.PeerCertificates[0].Subject.CommonName}
+		}
+		scope = safeguard.AuthProviderCertificate
+	default:
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	if user == nil {
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_grant")
+		return
+	}
+
+	token := randomToken()
+	s.mu.Lock()
+	s.stsTokens[token] = user
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"scope":        scope,
+	})
+}
+
+// handleLoginResponse exchanges an RSTS access token for a Safeguard user token.
+func (s *Server) handleLoginResponse(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		StsAccessToken string `json:"StsAccessToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "The request body is invalid.")
+		return
+	}
+
+	s.mu.Lock()
+	user := s.stsTokens[body.StsAccessToken]
+	delete(s.stsTokens, body.StsAccessToken)
+	s.mu.Unlock()
+
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "The STS access token is invalid.")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"Status":    "Success",
+		"UserToken": s.issueUserToken(user),
+	})
+}
+
+// handleLogout revokes the user token of the request.
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	s.mu.Lock()
+	delete(s.userTokens, token)
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleMe returns the authenticated user.
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(userContextKey{}).(*fakeUser)
+	doc := document{
+		"Id":          float64(user.id),
+		"Name":        user.name,
+		"DisplayName": user.name,
+		"PrimaryAuthenticationProvider": document{
+			"Id":                float64(-1),
+			"Name":              "Local",
+			"TypeReferenceName": "Local",
+		},
+	}
+	if fields := r.URL.Query().Get("fields"); fields != "" {
+		doc = projectFields(doc, fields)
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// handleAccountEntitlements grants every user password access to every enabled
+// account through a single access policy.
+func (s *Server) handleAccountEntitlements(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entitlements []document
+	for _, account := range s.accounts {
+		if disabled, _ := account["Disabled"].(bool); disabled {
+			continue
+		}
+		asset, _ := account["Asset"].(document)
+		entitlements = append(entitlements, document{
+			"Account": document{
+				"Id":          account["Id"],
+				"Name":        account["Name"],
+				"HasPassword": true,
+				"AssetId":     asset["Id"],
+				"AssetName":   asset["Name"],
+			},
+			"Asset": asset,
+			"Policies": []any{document{
+				"Id":                float64(1),
+				"Name":              "sgfake",
+				"AccessRequestType": string(safeguard.AccessRequestTypePassword),
+			}},
+		})
+	}
+
+	result, err := applyQuery(entitlements, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// list returns a handler that queries a collection.
+func (s *Server) list(docs *[]document) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		result, err := applyQuery(*docs, r.URL.Query())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// get returns a handler that returns a single document of a collection.
+func (s *Server) get(docs *[]document) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		doc := findDocument(*docs, r.PathValue("id"))
+		if doc == nil {
+			writeNotFound(w, r)
+			return
+		}
+		if fields := r.URL.Query().Get("fields"); fields != "" {
+			doc = projectFields(doc, fields)
+		}
+		writeJSON(w, http.StatusOK, doc)
+	}
+}
+
+// create returns a handler that adds a document to a collection.
+func (s *Server) create(docs *[]document) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, ok := readDocument(w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(doc, "Id")
+		s.assignId(doc)
+		*docs = append(*docs, doc)
+		writeJSON(w, http.StatusCreated, doc)
+	}
+}
+
+// replace returns a handler that updates a document of a collection.
+func (s *Server) replace(docs *[]document) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, ok := readDocument(w, r)
+		if !ok {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		i := slices.IndexFunc(*docs, func(d document) bool { return formatValue(d["Id"]) == id })
+		if i < 0 {
+			writeNotFound(w, r)
+			return
+		}
+		doc["Id"] = (*docs)[i]["Id"]
+		(*docs)[i] = doc
+		writeJSON(w, http.StatusOK, doc)
+	}
+}
+
+// remove returns a handler that deletes a document from a collection.
+func (s *Server) remove(docs *[]document) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		id := r.PathValue("id")
+		i := slices.IndexFunc(*docs, func(d document) bool { return formatValue(d["Id"]) == id })
+		if i < 0 {
+			writeNotFound(w, r)
+			return
+		}
+		*docs = slices.Delete(*docs, i, i+1)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// handleAssetAccounts lists the accounts of an asset.
+func (s *Server) handleAssetAccounts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assetId := r.PathValue("id")
+	if findDocument(s.assets, assetId) == nil {
+		writeNotFound(w, r)
+		return
+	}
+
+	var accounts []document
+	for _, doc := range s.accounts {
+		if asset, ok := doc["Asset"].(document); ok && formatValue(asset["Id"]) == assetId {
+			accounts = append(accounts, doc)
+		}
+	}
+	result, err := applyQuery(accounts, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleAccountBatchCreate creates asset accounts and reports the result per item.
+func (s *Server) handleAccountBatchCreate(w http.ResponseWriter, r *http.Request) {
+	var items []document
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "The request body is invalid.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]document, 0, len(items))
+	for _, item := range items {
+		asset, _ := item["Asset"].(document)
+		switch {
+		case formatValue(item["Name"]) == "":
+			results = append(results, batchError(item, http.StatusBadRequest, codeBadRequest, "The account name is required."))
+		case asset == nil || findDocument(s.assets, formatValue(asset["Id"])) == nil:
+			results = append(results, batchError(item, http.StatusNotFound, codeNotFound, "The asset of the account was not found."))
+		default:
+			doc := s.storeAccount(cloneDocument(item))
+			s.passwords[documentInt(doc, "Id")] = randomToken()
+			results = append(results, batchSuccess(item, doc))
+		}
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// handleAccountAction implements password tasks and state changes of asset accounts.
+func (s *Server) handleAccountAction(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account := findDocument(s.accounts, r.PathValue("id"))
+	if account == nil {
+		writeNotFound(w, r)
+		return
+	}
+
+	switch action := r.PathValue("action"); action {
+	case "ChangePassword":
+		s.passwords[documentInt(account, "Id")] = randomToken()
+		writeJSON(w, http.StatusOK, activityLog(account, "PasswordChangeSucceeded", "Password Change Succeeded"))
+	case "CheckPassword":
+		writeJSON(w, http.StatusOK, activityLog(account, "PasswordCheckSucceeded", "Password Check Succeeded"))
+	case "SuspendAccount":
+		writeJSON(w, http.StatusOK, activityLog(account, "SuspendAccountSucceeded", "Suspend Account Succeeded"))
+	case "Enable", "Disable":
+		account["Disabled"] = action == "Disable"
+		writeJSON(w, http.StatusOK, account)
+	default:
+		writeNotFound(w, r)
+	}
+}
+
+// handleAccessRequestBatchCreate creates access requests and reports the result per item.
+func (s *Server) handleAccessRequestBatchCreate(w http.ResponseWriter, r *http.Request) {
+	var items []document
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "The request body is invalid.")
+		return
+	}
+	user := r.Context().Value(userContextKey{}).(*fakeUser)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]document, 0, len(items))
+	for _, item := range items {
+		account := findDocument(s.accounts, formatValue(item["AccountId"]))
+		if account == nil {
+			results = append(results, batchError(item, http.StatusNotFound, codeNotFound, "The requested account was not found."))
+			continue
+		}
+		results = append(results, batchSuccess(item, s.newAccessRequest(item, account, user)))
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// newAccessRequest creates and stores an access request for an account.
+// The caller must hold s.mu.
+func (s *Server) newAccessRequest(item, account document, user *fakeUser) document {
+	asset, _ := account["Asset"].(document)
+	now := time.Now().UTC()
+
+	requestType := formatValue(item["AccessRequestType"])
+	if requestType == "" {
+		requestType = string(safeguard.AccessRequestTypePassword)
+	}
+	duration := documentInt(item, "RequestedDurationDays")*24*60 +
+		documentInt(item, "RequestedDurationHours")*60 +
+		documentInt(item, "RequestedDurationMinutes")
+	if duration == 0 {
+		duration = 120
+	}
+
+	doc := document{
+		"Id":                       strconv.Itoa(s.nextId),
+		"AccessRequestType":        requestType,
+		"AccountId":                account["Id"],
+		"AccountName":              account["Name"],
+		"AccountAssetId":           asset["Id"],
+		"AccountAssetName":         asset["Name"],
+		"AssetId":                  asset["Id"],
+		"AssetName":                asset["Name"],
+		"RequesterId":              float64(user.id),
+		"RequesterUsername":        user.name,
+		"RequesterDisplayName":     user.name,
+		"CreatedOn":                now.Format(time.RFC3339),
+		"DurationInMinutes":        float64(duration),
+		"ExpiresOn":                now.Add(time.Duration(duration) * time.Minute).Format(time.RFC3339),
+		"RequestedDurationDays":    item["RequestedDurationDays"],
+		"RequestedDurationHours":   item["RequestedDurationHours"],
+		"RequestedDurationMinutes": item["RequestedDurationMinutes"],
+		"IsEmergency":              item["IsEmergency"],
+		"ReasonComment":            item["ReasonComment"],
+	}
+	s.nextId++
+
+	state := safeguard.StateRequestAvailable
+	if s.requireApproval {
+		state = safeguard.StatePendingApproval
+	}
+	setState(doc, state)
+	s.accessRequests = append(s.accessRequests, doc)
+	return doc
+}
+
+// handleAccessRequestAction implements the workflow actions of access requests.
+func (s *Server) handleAccessRequestAction(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc := findDocument(s.accessRequests, r.PathValue("id"))
+	if doc == nil {
+		writeNotFound(w, r)
+		return
+	}
+	request := fromDocument[safeguard.AccessRequest](doc)
+	action := r.PathValue("action")
+
+	invalidState := func() {
+		writeError(w, http.StatusBadRequest, codeInvalidState,
+			fmt.Sprintf("The access request cannot be processed by %s in state %s.", action, request.State))
+	}
+
+	switch action {
+	case "CheckOutPassword", "RetrievePassword":
+		if !request.IsValid() {
+			invalidState()
+			return
+		}
+		setState(doc, safeguard.StatePasswordCheckedOut)
+		doc["WasCheckedOut"] = true
+		writeJSON(w, http.StatusOK, s.passwords[request.AccountId])
+	case "CheckIn":
+		if !request.IsValid() {
+			invalidState()
+			return
+		}
+		setState(doc, safeguard.StateCompleted)
+		writeJSON(w, http.StatusOK, doc)
+	case "Cancel":
+		if !request.IsPending() && request.State != safeguard.StateRequestAvailable {
+			invalidState()
+			return
+		}
+		setState(doc, safeguard.StateCanceled)
+		doc["WasCancelled"] = true
+		writeJSON(w, http.StatusOK, doc)
+	default:
+		writeNotFound(w, r)
+	}
+}
+
+// handleClusterSelf returns the cluster member that serves the request.
+func (s *Server) handleClusterSelf(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.members) == 0 {
+		writeNotFound(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.members[0])
+}
+
+// setState changes the state of an access request document.
+func setState(doc document, state safeguard.AccessRequestState) {
+	doc["State"] = string(state)
+	doc["StateChangedOn"] = time.Now().UTC().Format(time.RFC3339)
+}
+
+// activityLog creates the activity log entry returned by an account task.
+func activityLog(account document, event, displayName string) document {
+	asset, _ := account["Asset"].(document)
+	return document{
+		"Id":               randomToken(),
+		"LogTime":          time.Now().UTC().Format(time.RFC3339),
+		"EventName":        event,
+		"EventDisplayName": displayName,
+		"AccountId":        account["Id"],
+		"AccountName":      account["Name"],
+		"AssetId":          asset["Id"],
+		"AssetName":        asset["Name"],
+	}
+}
+
+// batchSuccess creates a successful item of a BatchCreate response.
+func batchSuccess(request, response document) document {
+	return document{
+		"Request":          request,
+		"Response":         response,
+		"StatusCode":       "Created",
+		"StatusCodeNumber": http.StatusCreated,
+		"IsSuccess":        true,
+	}
+}
+
+// batchError creates a failed item of a BatchCreate response.
+func batchError(request document, status, code int, message string) document {
+	return document{
+		"Request":          request,
+		"StatusCode":       strings.ReplaceAll(http.StatusText(status), " ", ""),
+		"StatusCodeNumber": status,
+		"IsSuccess":        false,
+		"Error":            document{"Code": code, "Message": message},
+	}
+}
+
+// cloneDocument returns a deep copy of a document.
+func cloneDocument(doc document) document {
+	return fromDocument[document](doc)
+}
+
+// readDocument decodes a JSON object from the request body.
+func readDocument(w http.ResponseWriter, r *http.Request) (document, bool) {
+	data, err := io.ReadAll(r.Body)
+	var doc document
+	if err == nil {
+		err = json.Unmarshal(data, &doc)
+	}
+	if err != nil || doc == nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "The request body is invalid.")
+		return nil, false
+	}
+	return doc, true
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// writeError writes a Safeguard error document.
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, document{"Code": code, "Message": message})
+}
+
+// writeNotFound writes the error for an unknown resource.
+func writeNotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("The resource %s was not found.", url.PathEscape(r.URL.Path)))
+}
+
+// writeOAuthError writes an OAuth 2.0 error response.
+func writeOAuthError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code, "error_description": "Access denied."})
+}
@@ -0,0 +1,487 @@
+package sgfake
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// document is a resource stored by the fake appliance, in its JSON form.
+type document = map[string]any
+
+// predicate reports whether a document matches a filter expression.
+type predicate func(document) bool
+
+// applyQuery evaluates the query parameters produced by safeguard.Filter.ToQueryString
+// against a collection: filter, count, orderby, page/limit and fields.
+//
+// Parameters:
+//   - docs: The documents of the collection.
+//   - query: The request query parameters.
+//
+// Returns:
+//   - any: The matching documents, or their number if count=true was requested.
+//   - error: An error if a query parameter cannot be parsed.
+func applyQuery(docs []document, query url.Values) (any, error) {
+	result := docs
+	if expr := query.Get("filter"); expr != "" {
+		match, err := parseFilter(expr)
+		if err != nil {
+			return nil, err
+		}
+		result = nil
+		for _, doc := range docs {
+			if match(doc) {
+				result = append(result, doc)
+			}
+		}
+	}
+
+	if strings.EqualFold(query.Get("count"), "true") {
+		return len(result), nil
+	}
+
+	if orderBy := query.Get("orderby"); orderBy != "" {
+		result = slices.Clone(result)
+		sortDocuments(result, orderBy)
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid limit %q", limit)
+		}
+		page, _ := strconv.Atoi(query.Get("page"))
+		start := min(max(page, 0)*n, len(result))
+		result = result[start:min(start+n, len(result))]
+	}
+
+	if fields := query.Get("fields"); fields != "" {
+		projected := make([]document, len(result))
+		for i, doc := range result {
+			projected[i] = projectFields(doc, fields)
+		}
+		result = projected
+	}
+
+	if result == nil {
+		result = []document{}
+	}
+	return result, nil
+}
+
+// lookup returns the values at a dotted property path, e.g. "Asset.Name".
+// Property names are matched case-insensitively and arrays along the path are
+// flattened, so "Tags.Name" yields the names of all tags.
+func lookup(v any, path string) []any {
+	name, rest, nested := strings.Cut(path, ".")
+
+	switch v := v.(type) {
+	case []any:
+		var values []any
+		for _, item := range v {
+			values = append(values, lookup(item, path)...)
+		}
+		return values
+	case document:
+		for key, value := range v {
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+			if nested {
+				return lookup(value, rest)
+			}
+			if items, ok := value.([]any); ok {
+				return items
+			}
+			return []any{value}
+		}
+	}
+	return nil
+}
+
+// projectFields returns a copy of doc that only contains the given comma
+// separated fields. Nested fields such as "Asset.Name" keep their structure.
+func projectFields(doc document, fields string) document {
+	projected := document{}
+	for _, field := range strings.Split(fields, ",") {
+		copyField(doc, projected, strings.TrimSpace(field))
+	}
+	return projected
+}
+
+// copyField copies a dotted field path from src to dst.
+func copyField(src, dst document, path string) {
+	name, rest, nested := strings.Cut(path, ".")
+	for key, value := range src {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		if !nested {
+			dst[key] = value
+			return
+		}
+		child, ok := value.(document)
+		if !ok {
+			return
+		}
+		target, ok := dst[key].(document)
+		if !ok {
+			target = document{}
+			dst[key] = target
+		}
+		copyField(child, target, rest)
+		return
+	}
+}
+
+// sortDocuments sorts documents by a comma separated orderby expression.
+// A field is sorted in descending order if it is prefixed with "-" or
+// followed by "desc".
+func sortDocuments(docs []document, orderBy string) {
+	type key struct {
+		path string
+		desc bool
+	}
+	var keys []key
+	for _, part := range strings.Split(orderBy, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		k := key{path: fields[0]}
+		if strings.HasPrefix(k.path, "-") {
+			k.path, k.desc = k.path[1:], true
+		}
+		if len(fields) > 1 && strings.EqualFold(fields[1], "desc") {
+			k.desc = true
+		}
+		keys = append(keys, k)
+	}
+
+	slices.SortStableFunc(docs, func(a, b document) int {
+		for _, k := range keys {
+			c := compareValues(first(lookup(a, k.path)), first(lookup(b, k.path)))
+			if k.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// first returns the first value or nil.
+func first(values []any) any {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+// compareValues orders two JSON values, numerically if both are numbers.
+func compareValues(a, b any) int {
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(formatValue(a), formatValue(b))
+}
+
+// formatValue returns the string form of a JSON value used in comparisons.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// matchValue applies a comparison operator to a single property value.
+func matchValue(actual any, op string, expected []string) bool {
+	if op == "in" {
+		return slices.ContainsFunc(expected, func(e string) bool { return matchValue(actual, "eq", []string{e}) })
+	}
+	want := expected[0]
+
+	// Properties with zero values are omitted from the stored documents
+	if actual == nil {
+		switch want {
+		case "", "false", "0":
+			actual = want
+		}
+	}
+
+	if x, ok := actual.(float64); ok {
+		if y, err := strconv.ParseFloat(want, 64); err == nil {
+			switch op {
+			case "eq", "ieq":
+				return x == y
+			case "ne":
+				return x != y
+			case "gt":
+				return x > y
+			case "ge":
+				return x >= y
+			case "lt":
+				return x < y
+			case "le":
+				return x <= y
+			}
+		}
+	}
+
+	got := formatValue(actual)
+	if _, ok := actual.(bool); ok || strings.HasPrefix(op, "i") {
+		got, want = strings.ToLower(got), strings.ToLower(want)
+	}
+
+	switch op {
+	case "eq", "ieq":
+		return got == want
+	case "ne":
+		return got != want
+	case "gt":
+		return got > want
+	case "ge":
+		return got >= want
+	case "lt":
+		return got < want
+	case "le":
+		return got <= want
+	case "contains", "icontains":
+		return strings.Contains(got, want)
+	case "sw", "isw":
+		return strings.HasPrefix(got, want)
+	case "ew", "iew":
+		return strings.HasSuffix(got, want)
+	}
+	return false
+}
+
+// comparisonOperators are the operators supported between a property and a value.
+var comparisonOperators = []string{"eq", "ne", "gt", "ge", "lt", "le", "contains", "ieq", "icontains", "sw", "isw", "ew", "iew", "in"}
+
+// filterParser parses Safeguard filter expressions such as
+// "(Name eq 'web' and not Disabled eq true) or Id in [1,2]".
+type filterParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseFilter compiles a filter expression into a predicate.
+//
+// Parameters:
+//   - expr: The value of the filter query parameter.
+//
+// Returns:
+//   - predicate: A function that evaluates the expression for a document.
+//   - error: An error if the expression is malformed.
+func parseFilter(expr string) (predicate, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in filter", p.tokens[p.pos])
+	}
+	return match, nil
+}
+
+func (p *filterParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *filterParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *filterParser) parseOr() (predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(d document) bool { return l(d) || right(d) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (predicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(d document) bool { return l(d) && right(d) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (predicate, error) {
+	switch token := p.peek(); {
+	case strings.EqualFold(token, "not"):
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(d document) bool { return !inner(d) }, nil
+	case token == "(":
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis in filter")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (predicate, error) {
+	field := p.next()
+	if field == "" || !isIdentifier(field) {
+		return nil, fmt.Errorf("expected property name in filter, got %q", field)
+	}
+	op := strings.ToLower(p.next())
+	if !slices.Contains(comparisonOperators, op) {
+		return nil, fmt.Errorf("unknown filter operator %q", op)
+	}
+
+	var values []string
+	if op == "in" && p.peek() == "[" {
+		p.next()
+		for p.peek() != "]" {
+			if p.peek() == "" {
+				return nil, fmt.Errorf("missing closing bracket in filter")
+			}
+			if token := p.next(); token != "," {
+				values = append(values, literal(token))
+			}
+		}
+		p.next()
+	} else {
+		token := p.next()
+		if token == "" || token == "(" || token == ")" {
+			return nil, fmt.Errorf("expected value for %s in filter", field)
+		}
+		values = []string{literal(token)}
+	}
+
+	return func(d document) bool {
+		actual := lookup(d, field)
+		if len(actual) == 0 {
+			return matchValue(nil, op, values)
+		}
+		// A comparison with a collection matches if any element matches
+		if op == "ne" {
+			return !slices.ContainsFunc(actual, func(v any) bool { return matchValue(v, "eq", values) })
+		}
+		return slices.ContainsFunc(actual, func(v any) bool { return matchValue(v, op, values) })
+	}, nil
+}
+
+// tokenize splits a filter expression into tokens. Quoted strings keep their
+// quotes so that they can be told apart from property names.
+func tokenize(expr string) ([]string, error) {
+	var tokens []string
+	runes := []rune(expr)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+		case strings.ContainsRune("()[],", r):
+			tokens = append(tokens, string(r))
+		case r == '\'':
+			var sb strings.Builder
+			sb.WriteRune(r)
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					sb.WriteRune(runes[i])
+					continue
+				}
+				if runes[i] == '\'' {
+					closed = true
+					break
+				}
+				sb.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string in filter")
+			}
+			tokens = append(tokens, sb.String()+"'")
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()[],'", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+			i--
+		}
+	}
+	return tokens, nil
+}
+
+// literal returns the value of a token, removing the quotes of strings.
+func literal(token string) string {
+	if len(token) >= 2 && token[0] == '\'' && token[len(token)-1] == '\'' {
+		return token[1 : len(token)-1]
+	}
+	return token
+}
+
+// isIdentifier reports whether a token is a property path.
+func isIdentifier(token string) bool {
+	for _, r := range token {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
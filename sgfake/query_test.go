@@ -0,0 +1,165 @@
+package sgfake
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sthayduk/safeguard-go"
+)
+
+func testDocuments() []document {
+	return []document{
+		{"Id": float64(1), "Name": "web01", "Disabled": false, "Asset": document{"Name": "Linux"}, "Tags": []any{document{"Name": "prod"}}},
+		{"Id": float64(2), "Name": "web02", "Disabled": true, "Asset": document{"Name": "Linux"}},
+		{"Id": float64(3), "Name": "db'01", "Asset": document{"Name": "Windows"}, "Tags": []any{document{"Name": "prod"}, document{"Name": "db"}}},
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		want   []float64
+	}{
+		{name: "eq", filter: "Name eq 'web01'", want: []float64{1}},
+		{name: "ne", filter: "Name ne 'web01'", want: []float64{2, 3}},
+		{name: "numeric", filter: "Id gt 1", want: []float64{2, 3}},
+		{name: "le", filter: "Id le 2", want: []float64{1, 2}},
+		{name: "bool", filter: "Disabled eq true", want: []float64{2}},
+		{name: "missing bool is false", filter: "Disabled eq false", want: []float64{1, 3}},
+		{name: "ieq", filter: "Name ieq 'WEB02'", want: []float64{2}},
+		{name: "contains is case sensitive", filter: "Name contains 'WEB'", want: nil},
+		{name: "icontains", filter: "Name icontains 'WEB'", want: []float64{1, 2}},
+		{name: "sw", filter: "Name sw 'web'", want: []float64{1, 2}},
+		{name: "ew", filter: "Name ew '02'", want: []float64{2}},
+		{name: "escaped quote", filter: `Name eq 'db\'01'`, want: []float64{3}},
+		{name: "dotted path", filter: "Asset.Name eq 'Windows'", want: []float64{3}},
+		{name: "collection", filter: "Tags.Name eq 'db'", want: []float64{3}},
+		{name: "collection ne", filter: "Tags.Name ne 'prod'", want: []float64{2}},
+		{name: "in", filter: "Id in [1, 3]", want: []float64{1, 3}},
+		{name: "in strings", filter: "Name in ['web02','db\\'01']", want: []float64{2, 3}},
+		{name: "and", filter: "Asset.Name eq 'Linux' and Disabled eq false", want: []float64{1}},
+		{name: "or", filter: "Id eq 1 or Id eq 3", want: []float64{1, 3}},
+		{name: "not", filter: "not Disabled eq true", want: []float64{1, 3}},
+		{name: "precedence", filter: "Id eq 3 or Id eq 1 and Disabled eq true", want: []float64{3}},
+		{name: "parentheses", filter: "(Id eq 3 or Id eq 1) and not (Asset.Name eq 'Windows')", want: []float64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := parseFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("parseFilter(%q) error = %v", tt.filter, err)
+			}
+			var got []float64
+			for _, doc := range testDocuments() {
+				if match(doc) {
+					got = append(got, doc["Id"].(float64))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseFilter(%q) matched %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	for _, filter := range []string{
+		"Name",
+		"Name eq",
+		"Name like 'x'",
+		"Name eq 'x",
+		"(Name eq 'x'",
+		"Id in [1, 2",
+		"Name eq 'x' Id",
+	} {
+		t.Run(filter, func(t *testing.T) {
+			if _, err := parseFilter(filter); err == nil {
+				t.Errorf("parseFilter(%q) expected an error", filter)
+			}
+		})
+	}
+}
+
+func TestParseFilterFromFilter(t *testing.T) {
+	var filter safeguard.Filter
+	filter.AddFilter("Name", safeguard.OpStartsWith, "web")
+	filter.AddFilter("Disabled", safeguard.OpEqual, "false")
+
+	query, err := url.ParseQuery(strings.TrimPrefix(filter.ToQueryString(), "?"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := applyQuery(testDocuments(), query)
+	if err != nil {
+		t.Fatalf("applyQuery() error = %v", err)
+	}
+	if got := result.([]document); len(got) != 1 || got[0]["Id"] != float64(1) {
+		t.Errorf("applyQuery(%s) = %v", filter.ToQueryString(), got)
+	}
+}
+
+func TestApplyQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []float64
+		count int
+	}{
+		{name: "all", query: "", want: []float64{1, 2, 3}},
+		{name: "orderby desc", query: "orderby=-Id", want: []float64{3, 2, 1}},
+		{name: "orderby multiple", query: "orderby=Asset.Name desc,Name", want: []float64{3, 1, 2}},
+		{name: "limit", query: "limit=2", want: []float64{1, 2}},
+		{name: "page", query: "page=1&limit=2", want: []float64{3}},
+		{name: "page out of range", query: "page=5&limit=2", want: []float64{}},
+		{name: "filter and order", query: "filter=Asset.Name eq 'Linux'&orderby=-Name", want: []float64{2, 1}},
+		{name: "count", query: "filter=Id gt 1&count=true", count: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := applyQuery(testDocuments(), query)
+			if err != nil {
+				t.Fatalf("applyQuery() error = %v", err)
+			}
+			if tt.want == nil {
+				if result != tt.count {
+					t.Errorf("applyQuery() = %v, want count %d", result, tt.count)
+				}
+				return
+			}
+			got := []float64{}
+			for _, doc := range result.([]document) {
+				got = append(got, doc["Id"].(float64))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("applyQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyQueryFields(t *testing.T) {
+	result, err := applyQuery(testDocuments()[:1], url.Values{"fields": {"Id,asset.name"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := result.([]document)[0]
+	if len(got) != 2 || got["Id"] != float64(1) {
+		t.Errorf("unexpected projection %v", got)
+	}
+	if asset, ok := got["Asset"].(document); !ok || len(asset) != 1 || asset["Name"] != "Linux" {
+		t.Errorf("unexpected nested projection %v", got["Asset"])
+	}
+
+	if _, err := applyQuery(testDocuments(), url.Values{"limit": {"x"}}); err == nil {
+		t.Error("expected an error for an invalid limit")
+	}
+}
@@ -0,0 +1,440 @@
+// Package sgfake provides an in-memory fake of a Safeguard appliance for
+// integration tests. The fake serves the RSTS token endpoint and the core API
+// endpoints used by the safeguard package over TLS, keeps its state in memory
+// and evaluates the filter, fields, orderby and paging query parameters
+// produced by safeguard.Filter.
+//
+// Example:
+//
+//	server := sgfake.NewServer()
+//	defer server.Close()
+//
+//	asset := server.AddAsset(safeguard.Asset{Name: "web01", NetworkAddress: "10.0.0.1"})
+//	server.AddAssetAccount(safeguard.AssetAccount{Name: "root", Asset: asset}, "s3cret")
+//
+//	client, err := server.NewClient()
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	if err := client.LoginWithPassword(sgfake.DefaultUsername, sgfake.DefaultPassword); err != nil {
+//	    t.Fatal(err)
+//	}
+package sgfake
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sthayduk/safeguard-go"
+)
+
+const (
+	// DefaultUsername is the local user that can log in to a fake appliance.
+	DefaultUsername = "Admin"
+	// DefaultPassword is the password of DefaultUsername.
+	DefaultPassword = "Admin123"
+)
+
+// Option configures a fake appliance.
+type Option func(*Server)
+
+// WithUser adds a local user that can log in with a password.
+//
+// Parameters:
+//   - username: The user name.
+//   - password: The password of the user.
+func WithUser(username, password string) Option {
+	return func(s *Server) {
+		s.addUser(username, password)
+	}
+}
+
+// WithApprovalRequired makes new access requests start in the PendingApproval
+// state. Use Server.SetAccessRequestState to approve them.
+func WithApprovalRequired() Option {
+	return func(s *Server) {
+		s.requireApproval = true
+	}
+}
+
+// WithAddress sets the address the fake listens on, e.g. "127.0.0.1:8443".
+// By default a random local port is used.
+//
+// Parameters:
+//   - addr: The TCP address to listen on.
+func WithAddress(addr string) Option {
+	return func(s *Server) {
+		s.addr = addr
+	}
+}
+
+// fakeUser is a local user of the fake appliance.
+type fakeUser struct {
+	id       int
+	name     string
+	password string
+}
+
+// Server is a fake Safeguard appliance backed by an httptest.Server.
+type Server struct {
+	// URL is the appliance URL to pass to the client, e.g. "https://localhost:51234".
+	URL string
+
+	ts       *httptest.Server
+	certPEM  []byte
+	certPool *x509.CertPool
+	addr     string
+
+	mu              sync.Mutex
+	requireApproval bool
+	users           map[string]*fakeUser
+	stsTokens       map[string]*fakeUser
+	userTokens      map[string]*fakeUser
+	staticToken     string
+	nextId          int
+
+	assets         []document
+	accounts       []document
+	accessRequests []document
+	members        []document
+	passwords      map[int]string
+}
+
+// NewServer starts a fake appliance. It has the local user DefaultUsername and a
+// single healthy cluster member that is the leader. Like httptest.NewServer, it
+// panics if the server cannot be started.
+//
+// Parameters:
+//   - opts: Options to configure the fake.
+//
+// Returns:
+//   - *Server: The running fake. Call Close when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		users:      map[string]*fakeUser{},
+		stsTokens:  map[string]*fakeUser{},
+		userTokens: map[string]*fakeUser{},
+		passwords:  map[int]string{},
+		nextId:     1,
+	}
+	s.addUser(DefaultUsername, DefaultPassword)
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	cert, err := s.generateCertificate()
+	if err != nil {
+		panic(fmt.Sprintf("sgfake: failed to create certificate: %v", err))
+	}
+
+	s.ts = httptest.NewUnstartedServer(s.routes())
+	if s.addr != "" {
+		listener, err := net.Listen("tcp", s.addr)
+		if err != nil {
+			panic(fmt.Sprintf("sgfake: failed to listen on %s: %v", s.addr, err))
+		}
+		s.ts.Listener.Close()
+		s.ts.Listener = listener
+	}
+	s.ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequestClientCert,
+	}
+	s.ts.StartTLS()
+
+	port := s.ts.Listener.Addr().(*net.TCPAddr).Port
+	// The client derives the leader URL from the member name, so the fake uses
+	// a host name without a domain
+	s.URL = fmt.Sprintf("https://localhost:%d", port)
+	s.AddClusterMember(safeguard.ClusterMember{
+		Name:       "localhost",
+		IsLeader:   true,
+		IsEnrolled: true,
+		Health:     safeguard.NodeHealth{Status: safeguard.HealthStatusHealthy},
+	})
+
+	s.staticToken = s.issueUserToken(s.users[DefaultUsername])
+	return s
+}
+
+// Close shuts down the fake appliance.
+func (s *Server) Close() {
+	s.ts.Close()
+}
+
+// CertPool returns a pool that trusts the certificate of the fake appliance.
+func (s *Server) CertPool() *x509.CertPool {
+	return s.certPool
+}
+
+// CertificatePEM returns the certificate of the fake appliance in PEM format,
+// e.g. to write it to a file for safeguard.WithCABundle.
+func (s *Server) CertificatePEM() []byte {
+	return s.certPEM
+}
+
+// UserToken returns a valid user token for DefaultUsername, which can be set as
+// the client's access token instead of logging in.
+func (s *Server) UserToken() string {
+	return s.staticToken
+}
+
+// NewClient creates a client for the fake appliance that trusts its certificate.
+//
+// Parameters:
+//   - opts: Additional client options.
+//
+// Returns:
+//   - *safeguard.SafeguardClient: The client, not yet logged in.
+//   - error: An error if the client cannot be created.
+func (s *Server) NewClient(opts ...safeguard.ClientOption) (*safeguard.SafeguardClient, error) {
+	opts = append([]safeguard.ClientOption{safeguard.WithCertPool(s.certPool)}, opts...)
+	return safeguard.NewClientWithOptions(s.URL, opts...)
+}
+
+// AddAsset stores an asset and assigns it an Id if it has none.
+//
+// Parameters:
+//   - asset: The asset to add.
+//
+// Returns:
+//   - safeguard.Asset: The stored asset.
+func (s *Server) AddAsset(asset safeguard.Asset) safeguard.Asset {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc := toDocument(asset)
+	s.assignId(doc)
+	s.assets = append(s.assets, doc)
+	return fromDocument[safeguard.Asset](doc)
+}
+
+// AddAssetAccount stores an account and its password and assigns it an Id if it
+// has none. If Asset.Id refers to a stored asset, the asset's name is filled in.
+//
+// Parameters:
+//   - account: The account to add.
+//   - password: The password returned when the account is checked out.
+//
+// Returns:
+//   - safeguard.AssetAccount: The stored account.
+func (s *Server) AddAssetAccount(account safeguard.AssetAccount, password string) safeguard.AssetAccount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc := s.storeAccount(toDocument(account))
+	s.passwords[documentInt(doc, "Id")] = password
+	return fromDocument[safeguard.AssetAccount](doc)
+}
+
+// AddClusterMember stores a cluster member. Its Id defaults to its Name.
+//
+// Parameters:
+//   - member: The cluster member to add.
+func (s *Server) AddClusterMember(member safeguard.ClusterMember) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if member.Id == "" {
+		member.Id = member.Name
+	}
+	s.members = append(s.members, toDocument(member))
+}
+
+// AccountPassword returns the current password of an account, e.g. to verify a
+// password change.
+//
+// Parameters:
+//   - accountId: The Id of the account.
+//
+// Returns:
+//   - string: The password.
+//   - bool: false if the account has no password.
+func (s *Server) AccountPassword(accountId int) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	password, ok := s.passwords[accountId]
+	return password, ok
+}
+
+// AccessRequest returns a stored access request.
+//
+// Parameters:
+//   - id: The Id of the access request.
+//
+// Returns:
+//   - safeguard.AccessRequest: The access request.
+//   - bool: false if no access request has this Id.
+func (s *Server) AccessRequest(id string) (safeguard.AccessRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc := findDocument(s.accessRequests, id)
+	if doc == nil {
+		return safeguard.AccessRequest{}, false
+	}
+	return fromDocument[safeguard.AccessRequest](doc), true
+}
+
+// SetAccessRequestState changes the state of an access request, e.g. to approve
+// a request that is pending approval.
+//
+// Parameters:
+//   - id: The Id of the access request.
+//   - state: The new state.
+//
+// Returns:
+//   - error: An error if no access request has this Id.
+func (s *Server) SetAccessRequestState(id string, state safeguard.AccessRequestState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc := findDocument(s.accessRequests, id)
+	if doc == nil {
+		return fmt.Errorf("access request %s not found", id)
+	}
+	setState(doc, state)
+	return nil
+}
+
+// addUser adds a local user.
+func (s *Server) addUser(username, password string) {
+	s.users[username] = &fakeUser{id: len(s.users) + 1, name: username, password: password}
+}
+
+// assignId gives a document the next free numeric Id unless it has one.
+// The caller must hold s.mu.
+func (s *Server) assignId(doc document) {
+	if id := documentInt(doc, "Id"); id != 0 {
+		s.nextId = max(s.nextId, id+1)
+		return
+	}
+	doc["Id"] = float64(s.nextId)
+	s.nextId++
+}
+
+// storeAccount assigns an Id to an account, links it to its asset and stores it.
+// The caller must hold s.mu.
+func (s *Server) storeAccount(doc document) document {
+	s.assignId(doc)
+	if ref, ok := doc["Asset"].(document); ok {
+		if asset := findDocument(s.assets, formatValue(ref["Id"])); asset != nil {
+			doc["Asset"] = document{
+				"Id":             asset["Id"],
+				"Name":           asset["Name"],
+				"NetworkAddress": asset["NetworkAddress"],
+			}
+		}
+	}
+	s.accounts = append(s.accounts, doc)
+	return doc
+}
+
+// issueUserToken creates a Safeguard user token for a user.
+func (s *Server) issueUserToken(user *fakeUser) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := randomToken()
+	s.userTokens[token] = user
+	return token
+}
+
+// generateCertificate creates a self-signed certificate for localhost.
+func (s *Server) generateCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "sgfake"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	s.certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	s.certPool = x509.NewCertPool()
+	s.certPool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+// randomToken returns a random opaque token.
+func randomToken() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// toDocument converts a typed resource into its stored JSON form.
+func toDocument(v any) document {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("sgfake: failed to encode %T: %v", v, err))
+	}
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		panic(fmt.Sprintf("sgfake: failed to decode %T: %v", v, err))
+	}
+	return doc
+}
+
+// fromDocument converts a stored document into a typed resource.
+func fromDocument[T any](doc document) T {
+	var v T
+	data, _ := json.Marshal(doc)
+	json.Unmarshal(data, &v)
+	return v
+}
+
+// findDocument returns the document with the given Id, or nil.
+func findDocument(docs []document, id string) document {
+	for _, doc := range docs {
+		if formatValue(doc["Id"]) == id {
+			return doc
+		}
+	}
+	return nil
+}
+
+// documentInt returns a numeric property of a document, or 0.
+func documentInt(doc document, name string) int {
+	switch v := doc[name].(type) {
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	}
+	return 0
+}
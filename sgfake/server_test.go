@@ -0,0 +1,243 @@
+package sgfake
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sthayduk/safeguard-go"
+)
+
+// newLoggedInClient starts a fake appliance and returns a client logged in as DefaultUsername.
+func newLoggedInClient(t *testing.T, opts ...Option) (*Server, *safeguard.SafeguardClient) {
+	t.Helper()
+
+	server := NewServer(opts...)
+	t.Cleanup(server.Close)
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := client.LoginWithPassword(DefaultUsername, DefaultPassword); err != nil {
+		t.Fatalf("LoginWithPassword() error = %v", err)
+	}
+	return server, client
+}
+
+func TestLogin(t *testing.T) {
+	server := NewServer(WithUser("alice", "Passw0rd"))
+	defer server.Close()
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LoginWithPassword("alice", "wrong"); err == nil {
+		t.Error("expected an error for a wrong password")
+	}
+	if err := client.LoginWithPassword("alice", "Passw0rd"); err != nil {
+		t.Fatalf("LoginWithPassword() error = %v", err)
+	}
+
+	me, err := client.GetMe(context.Background(), safeguard.Filter{})
+	if err != nil {
+		t.Fatalf("GetMe() error = %v", err)
+	}
+	if me.Name != "alice" {
+		t.Errorf("GetMe() name = %q, want alice", me.Name)
+	}
+
+	token := client.AccessToken.UserToken
+	if err := client.Close(context.Background(), safeguard.LogoutOnClose()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	other, err := server.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.AccessToken.UserToken = token
+	if _, err := other.GetMe(context.Background(), safeguard.Filter{}); err == nil {
+		t.Error("expected the user token to be revoked")
+	}
+}
+
+func TestUnauthenticatedRequest(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetAssets(context.Background(), safeguard.Filter{})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected a 401 error, got %v", err)
+	}
+
+	if err := client.LoginWithTokenSource(context.Background(), safeguard.StaticTokenSource(server.UserToken())); err != nil {
+		t.Fatalf("LoginWithTokenSource() error = %v", err)
+	}
+	if _, err := client.GetAssets(context.Background(), safeguard.Filter{}); err != nil {
+		t.Errorf("GetAssets() with a static token error = %v", err)
+	}
+}
+
+func TestAssetsAndAccounts(t *testing.T) {
+	server, client := newLoggedInClient(t)
+	ctx := context.Background()
+
+	web := server.AddAsset(safeguard.Asset{Name: "web01", NetworkAddress: "10.0.0.1"})
+	server.AddAsset(safeguard.Asset{Name: "db01", NetworkAddress: "10.0.0.2"})
+	root := server.AddAssetAccount(safeguard.AssetAccount{Name: "root", Asset: safeguard.Asset{Id: web.Id}}, "s3cret")
+
+	var filter safeguard.Filter
+	filter.AddFilter("Name", safeguard.OpStartsWith, "web")
+	assets, err := client.GetAssets(ctx, filter)
+	if err != nil {
+		t.Fatalf("GetAssets() error = %v", err)
+	}
+	if len(assets) != 1 || assets[0].Id != web.Id {
+		t.Fatalf("GetAssets() = %+v, want only web01", assets)
+	}
+
+	accounts, err := client.GetAssetAccounts(ctx, safeguard.Filter{})
+	if err != nil {
+		t.Fatalf("GetAssetAccounts() error = %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Asset.Name != "web01" {
+		t.Fatalf("GetAssetAccounts() = %+v, want root on web01", accounts)
+	}
+
+	if _, err := accounts[0].ChangePassword(ctx); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+	if password, _ := server.AccountPassword(root.Id); password == "s3cret" {
+		t.Error("ChangePassword() did not change the password")
+	}
+
+	disabled, err := accounts[0].Disable(ctx)
+	if err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+	if !disabled.Disabled {
+		t.Error("Disable() did not disable the account")
+	}
+
+	if _, err := client.GetAsset(ctx, 999, nil); err == nil {
+		t.Error("expected an error for an unknown asset")
+	}
+}
+
+func TestAccessRequestWorkflow(t *testing.T) {
+	server, client := newLoggedInClient(t)
+	ctx := context.Background()
+
+	asset := server.AddAsset(safeguard.Asset{Name: "web01"})
+	account := server.AddAssetAccount(safeguard.AssetAccount{Name: "root", Asset: safeguard.Asset{Id: asset.Id}}, "s3cret")
+
+	responses, err := client.NewAccessRequests(ctx, []safeguard.AccountEntitlement{{
+		Account: safeguard.AccountInfo{Id: account.Id},
+		Asset:   safeguard.AssetInfo{Id: asset.Id},
+	}}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewAccessRequests() error = %v", err)
+	}
+	if len(responses) != 1 || !responses[0].IsSuccess {
+		t.Fatalf("NewAccessRequests() = %+v", responses)
+	}
+
+	request := responses[0].Response
+	if request.State != safeguard.StateRequestAvailable || request.AccountName != "root" || request.RequesterUsername != DefaultUsername {
+		t.Errorf("unexpected access request %+v", request)
+	}
+
+	password, err := request.CheckOutPassword(ctx, false)
+	if err != nil {
+		t.Fatalf("CheckOutPassword() error = %v", err)
+	}
+	if password != "s3cret" {
+		t.Errorf("CheckOutPassword() = %q, want s3cret", password)
+	}
+
+	var filter safeguard.Filter
+	filter.AddFilter("State", safeguard.OpEqual, string(safeguard.StatePasswordCheckedOut))
+	requests, err := client.GetAccessRequests(ctx, filter)
+	if err != nil {
+		t.Fatalf("GetAccessRequests() error = %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("GetAccessRequests() returned %d requests, want 1", len(requests))
+	}
+
+	checkedIn, err := requests[0].CheckIn(ctx)
+	if err != nil {
+		t.Fatalf("CheckIn() error = %v", err)
+	}
+	if checkedIn.State != safeguard.StateCompleted {
+		t.Errorf("CheckIn() state = %s, want %s", checkedIn.State, safeguard.StateCompleted)
+	}
+	if _, err := checkedIn.Cancel(ctx); err == nil {
+		t.Error("expected an error when canceling a completed request")
+	}
+}
+
+func TestAccessRequestApproval(t *testing.T) {
+	server, client := newLoggedInClient(t, WithApprovalRequired())
+	ctx := context.Background()
+
+	account := server.AddAssetAccount(safeguard.AssetAccount{Name: "root"}, "s3cret")
+	responses, err := client.NewAccessRequests(ctx, []safeguard.AccountEntitlement{
+		{Account: safeguard.AccountInfo{Id: account.Id}},
+		{Account: safeguard.AccountInfo{Id: 999}},
+	}, time.Hour)
+	if err == nil {
+		t.Error("expected an error for the unknown account")
+	}
+	if len(responses) != 2 || !responses[0].IsSuccess || responses[1].IsSuccess || responses[1].StatusCodeNumber != 404 {
+		t.Fatalf("unexpected batch responses %+v", responses)
+	}
+
+	request := responses[0].Response
+	if !request.IsPending() {
+		t.Fatalf("state = %s, want a pending state", request.State)
+	}
+	if _, err := request.CheckOutPassword(ctx, false); err == nil {
+		t.Error("expected an error when checking out a pending request")
+	}
+
+	if err := server.SetAccessRequestState(request.Id, safeguard.StateRequestAvailable); err != nil {
+		t.Fatal(err)
+	}
+	canceled, err := request.Cancel(ctx)
+	if err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if stored, _ := server.AccessRequest(request.Id); stored.State != safeguard.StateCanceled || canceled.State != safeguard.StateCanceled {
+		t.Errorf("Cancel() state = %s, want %s", stored.State, safeguard.StateCanceled)
+	}
+}
+
+func TestClusterMembers(t *testing.T) {
+	server, client := newLoggedInClient(t)
+	ctx := context.Background()
+
+	server.AddClusterMember(safeguard.ClusterMember{Name: "replica", Health: safeguard.NodeHealth{Status: safeguard.HealthStatusHealthy}})
+
+	members, err := client.GetClusterMembers(ctx, safeguard.Filter{})
+	if err != nil {
+		t.Fatalf("GetClusterMembers() error = %v", err)
+	}
+	if len(members) != 2 {
+		t.Fatalf("GetClusterMembers() returned %d members, want 2", len(members))
+	}
+
+	leader, err := client.GetClusterLeader(ctx)
+	if err != nil {
+		t.Fatalf("GetClusterLeader() error = %v", err)
+	}
+	if leader.Name != "localhost" {
+		t.Errorf("GetClusterLeader() = %q, want localhost", leader.Name)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -69,9 +70,15 @@ type EventHandler struct {
 	ctx     context.Context
 	started bool
 
-	// logger is the applications logger.
+	// logger is the logger of the client the handler belongs to.
 	logger *slog.Logger
 
+	// connections counts the created SignalR connections to detect reconnects.
+	connections atomic.Int32
+
+	// done is closed when Run returns.
+	done chan struct{}
+
 	// Channel for handling Events
 	EventChannel chan SignalREvent
 
@@ -90,14 +97,15 @@ func (h *EventHandler) Log(keyVals ...interface{}) error {
 func NewEventHandler(client *SafeguardClient) *EventHandler {
 	return &EventHandler{
 		client:       client,
-		logger:       client.Logger,
+		logger:       client.logger(),
 		EventChannel: make(chan SignalREvent, 100), // buffered channel to prevent blocking
 	}
 }
 
 // Run starts the event handler if it is not already running. It validates the access token,
 // creates a SignalR connection and client, and starts the SignalR client. The function
-// blocks until the SignalR client shuts down.
+// blocks until the SignalR client shuts down or the client is closed with
+// SafeguardClient.Close, in which case it returns nil.
 //
 // Parameters:
 //   - ctx: The context to control cancellation and timeout.
@@ -111,17 +119,28 @@ func (h *EventHandler) Run(ctx context.Context) error {
 		return fmt.Errorf("event handler already running")
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	h.done = make(chan struct{})
+	defer close(h.done)
+	if err := h.client.registerEventHandler(h, cancel); err != nil {
+		return err
+	}
+	defer h.client.unregisterEventHandler(h)
+
 	// Validate access token
-	if err := h.client.ValidateAccessToken(); err != nil {
+	if err := h.client.ValidateAccessToken(ctx); err != nil {
 		h.logger.Error("token validation failed", "error", err)
 		return err
 	}
 
 	// Create the signalr connection
 	connector := func() (signalr.Connection, error) {
+		reconnect := h.connections.Add(1) > 1
 		conn, err := signalr.NewHTTPConnection(
 			ctx,
-			fmt.Sprintf("%s/service/event/signalr", h.client.getClusterLeaderUrl()),
+			fmt.Sprintf("%s/service/event/signalr", h.client.getClusterLeaderUrl(ctx)),
 			signalr.WithHTTPHeaders(func() http.Header {
 				header := http.Header{
 					"Authorization": []string{"Bearer " + h.client.AccessToken.getUserToken()},
@@ -129,8 +148,9 @@ func (h *EventHandler) Run(ctx context.Context) error {
 				}
 				return header
 			}),
-			signalr.WithHTTPClient(h.client.HttpClient),
+			signalr.WithHTTPClient(h.client.httpClient()),
 		)
+		h.client.instrumentation().SignalRConnected(ctx, reconnect, err)
 		if err != nil {
 			h.logger.Error("creating signalr connection failed", "error", err)
 			return nil, err
@@ -165,7 +185,14 @@ func (h *EventHandler) Run(ctx context.Context) error {
 	client.Start()
 
 	// Wait for the SignalR client to shut down
-	return <-client.WaitForState(ctx, signalr.ClientClosed)
+	err = <-client.WaitForState(ctx, signalr.ClientClosed)
+	select {
+	case <-h.client.lifecycle.done():
+		h.logger.Info("signalr client stopped because the client was closed")
+		return nil
+	default:
+		return err
+	}
 }
 
 func (h *EventHandler) NotifyEventAsync(rawEvent interface{}) {
@@ -189,5 +216,15 @@ func (h *EventHandler) NotifyEventAsync(rawEvent interface{}) {
 		h.logger.Debug("event received", "type", fmt.Sprintf("%T", rawEvent), "event", event)
 	default:
 		h.logger.Warn("event channel is full, dropping event", "event", event)
+		h.client.instrumentation().EventDropped(h.context(), event)
+	}
+}
+
+// context returns the context the handler runs with, or context.Background()
+// before Run was called.
+func (h *EventHandler) context() context.Context {
+	if h.ctx == nil {
+		return context.Background()
 	}
+	return h.ctx
 }
@@ -0,0 +1,148 @@
+package safeguard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// bodyReader consumes the body of a successful response while it is received.
+type bodyReader func(body io.Reader) error
+
+// bodyError reports that a response was received but consuming its body failed.
+// Such requests are neither retried nor failed over, because part of the body
+// may already have been processed.
+type bodyError struct {
+	err error
+}
+
+func (e *bodyError) Error() string {
+	return e.err.Error()
+}
+
+func (e *bodyError) Unwrap() error {
+	return e.err
+}
+
+// Stream sends a GET request to a Safeguard API endpoint that returns a JSON array
+// and decodes the array one element at a time while the response is received.
+// Unlike Do, the response body is never held in memory as a whole, so exporting
+// large collections keeps the memory use flat. Requests are routed, retried and
+// rate limited like all other client requests.
+//
+// Elements that implement ClientHolder are associated with the client. Breaking
+// out of the loop closes the response. The loop body may send further requests
+// with the same client.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
+//   - c: The client used to send the request.
+//   - path: The API path relative to the core service root, e.g. "AssetAccounts".
+//   - query: Query parameters to append to the path, or nil.
+//
+// Returns:
+//   - iter.Seq2[T, error]: Yields each element with a nil error. A failed request
+//     or an undecodable element is yielded once as an error, after which
+//     iteration ends.
+//
+// Example:
+//
+//	query := url.Values{"fields": {"Id,Name,Asset.Name"}}
+//	for account, err := range safeguard.Stream[safeguard.AssetAccount](ctx, client, "AssetAccounts", query) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Fprintf(w, "%d,%s\n", account.Id, account.Name)
+//	}
+func Stream[T any](ctx context.Context, c *SafeguardClient, path string, query url.Values) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		if len(query) > 0 {
+			path = path + "?" + query.Encode()
+		}
+
+		if _, err := streamArray(ctx, c, path, func(item T) bool {
+			return yield(attachClient(c, item), nil)
+		}); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// streamArray sends a GET request for path and passes each element of the JSON
+// array in the response to fn.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the request.
+//   - c: The client used to send the request.
+//   - path: The API path relative to the core service root, including the query.
+//   - fn: Receives each element; returning false stops decoding.
+//
+// Returns:
+//   - int: The number of elements passed to fn.
+//   - error: An error if the request fails or the response is not a JSON array.
+//     It is nil if fn stopped the decoding.
+func streamArray[T any](ctx context.Context, c *SafeguardClient, path string, fn func(T) bool) (int, error) {
+	var count int
+	_, err := c.routedRequest(ctx, http.MethodGet, path, nil, func(body io.Reader) error {
+		return decodeJSONArray(body, func(item T) bool {
+			count++
+			return lendSlot(body, func() bool { return fn(item) })
+		})
+	})
+
+	var bodyErr *bodyError
+	if errors.As(err, &bodyErr) {
+		return count, fmt.Errorf("failed to decode GET %s response: %w", path, err)
+	}
+	return count, err
+}
+
+// decodeJSONArray decodes the elements of a JSON array from r one at a time.
+// An empty body and a JSON null are treated as an empty array.
+//
+// Parameters:
+//   - r: The reader containing the JSON array.
+//   - fn: Receives each element; returning false stops decoding.
+//
+// Returns:
+//   - error: An error if r does not contain a JSON array or an element cannot
+//     be decoded into T.
+func decodeJSONArray[T any](r io.Reader, fn func(T) bool) error {
+	dec := json.NewDecoder(r)
+
+	token, err := dec.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read JSON array: %w", err)
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected a JSON array, got %v", token)
+	}
+
+	for i := 0; dec.More(); i++ {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return fmt.Errorf("failed to decode array element %d: %w", i, err)
+		}
+		if !fn(item) {
+			return nil
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("failed to read end of JSON array: %w", err)
+	}
+	return nil
+}
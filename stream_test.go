@@ -0,0 +1,174 @@
+package safeguard
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDecodeJSONArray(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		stopAt  int
+		want    []int
+		wantErr bool
+	}{
+		{name: "array", body: `[1, 2, 3]`, want: []int{1, 2, 3}},
+		{name: "empty array", body: `[]`},
+		{name: "empty body", body: ``},
+		{name: "null", body: `null`},
+		{name: "stop early", body: `[1, 2, 3]`, stopAt: 2, want: []int{1, 2}},
+		{name: "object", body: `{"Id":1}`, wantErr: true},
+		{name: "invalid element", body: `[1, "two", 3]`, want: []int{1}, wantErr: true},
+		{name: "truncated", body: `[1, 2`, want: []int{1, 2}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			err := decodeJSONArray(strings.NewReader(tt.body), func(item int) bool {
+				got = append(got, item)
+				return len(got) != tt.stopAt
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeJSONArray() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("decodeJSONArray() yielded %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/service/core/v4/AssetAccounts" || r.URL.Query().Get("fields") != "Id,Name" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`[{"Id":1,"Name":"root"},{"Id":2,"Name":"admin"}]`))
+	})
+
+	var names []string
+	for account, err := range Stream[AssetAccount](context.Background(), client, "AssetAccounts", url.Values{"fields": {"Id,Name"}}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if account.apiClient != client {
+			t.Error("expected client to be set on yielded item")
+		}
+		names = append(names, account.Name)
+	}
+
+	if !slices.Equal(names, []string{"root", "admin"}) {
+		t.Errorf("unexpected accounts %v", names)
+	}
+}
+
+func TestStreamDecodeErrorIsNotRetried(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`[{"Id":1},{"Id":`))
+	})
+	client.RetryPolicy = testRetryPolicy()
+
+	var ids []int
+	var errs int
+	for account, err := range Stream[AssetAccount](context.Background(), client, "AssetAccounts", nil) {
+		if err != nil {
+			errs++
+			continue
+		}
+		ids = append(ids, account.Id)
+	}
+
+	if !slices.Equal(ids, []int{1}) || errs != 1 {
+		t.Errorf("expected account 1 and one error, got %v and %d errors", ids, errs)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+}
+
+func TestStreamAllowsRequestsWhileIterating(t *testing.T) {
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/AssetAccounts") {
+			w.Write([]byte(`[{"Id":1},{"Id":2}]`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+	client.SetReadLimit(RateLimit{MaxConcurrent: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for account, err := range Stream[AssetAccount](ctx, client, "AssetAccounts", nil) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := client.GetAssetAccount(ctx, account.Id, nil); err != nil {
+			t.Fatalf("request inside the loop failed: %v", err)
+		}
+	}
+}
+
+func TestStreamHoldsSlotWhileReceiving(t *testing.T) {
+	gate := make(chan struct{})
+	client := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Id":1},`))
+		w.(http.Flusher).Flush()
+		select {
+		case <-gate:
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{"Id":2}]`))
+	})
+	client.SetReadLimit(RateLimit{MaxConcurrent: 1})
+	slots := client.readLimiter.slots
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first := make(chan struct{})
+	done := make(chan []int)
+	go func() {
+		var ids []int
+		for account, err := range Stream[AssetAccount](ctx, client, "AssetAccounts", nil) {
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				break
+			}
+			ids = append(ids, account.Id)
+			if account.Id == 1 {
+				close(first)
+			}
+		}
+		done <- ids
+	}()
+
+	// The rest of the array is still being received, so the slot stays taken
+	<-first
+	deadline := time.Now().Add(time.Second)
+	for len(slots) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("concurrency slot was released before the body was closed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	close(gate)
+	if ids := <-done; !slices.Equal(ids, []int{1, 2}) {
+		t.Errorf("expected Ids [1 2], got %v", ids)
+	}
+	if got := len(slots); got != 0 {
+		t.Errorf("expected the slot to be released, %d still taken", got)
+	}
+}
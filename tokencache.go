@@ -0,0 +1,404 @@
+package safeguard
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// TokenCache persists user tokens across process restarts, keyed by appliance
+// and identity. A client with a token cache stores every new token and restores
+// the cached one with RestoreCachedToken, see WithTokenCache.
+type TokenCache interface {
+	// Load returns the cached token, or nil if there is none.
+	Load(ctx context.Context, appliance, identity string) (*Token, error)
+	// Store caches a token, replacing a previous one.
+	Store(ctx context.Context, appliance, identity string, token *Token) error
+	// Delete removes a cached token. Deleting a missing token is not an error.
+	Delete(ctx context.Context, appliance, identity string) error
+}
+
+// TokenCacheKey provides the secret that a FileTokenCache derives its
+// encryption key from. It is called once per salt of the cache file.
+type TokenCacheKey func() ([]byte, error)
+
+// PassphraseKey returns a TokenCacheKey for a passphrase.
+//
+// Parameters:
+//   - passphrase: The passphrase; must not be empty.
+//
+// Returns:
+//   - TokenCacheKey: The key.
+func PassphraseKey(passphrase string) TokenCacheKey {
+	return func() ([]byte, error) {
+		if passphrase == "" {
+			return nil, errors.New("token cache passphrase must not be empty")
+		}
+		return []byte(passphrase), nil
+	}
+}
+
+// KeyFileKey returns a TokenCacheKey that reads the secret from a file, e.g.
+// one created with `head -c 32 /dev/urandom > key`.
+//
+// Parameters:
+//   - path: Path to the key file.
+//
+// Returns:
+//   - TokenCacheKey: The key.
+func KeyFileKey(path string) TokenCacheKey {
+	return func() ([]byte, error) {
+		secret, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token cache key file: %w", err)
+		}
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("token cache key file %s is empty", path)
+		}
+		return secret, nil
+	}
+}
+
+// DefaultTokenCachePath returns the default location of a FileTokenCache in the
+// cache directory of the user, e.g. ~/.cache/safeguard-go/tokens on Linux.
+//
+// Returns:
+//   - string: The path.
+//   - error: An error if the cache directory of the user cannot be determined.
+func DefaultTokenCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine cache directory: %w", err)
+	}
+	return filepath.Join(dir, "safeguard-go", "tokens"), nil
+}
+
+// FileTokenCache is a TokenCache that keeps all tokens in a single file,
+// encrypted with AES-256-GCM. The key is derived from a TokenCacheKey with
+// scrypt and a random salt stored in the file. The file is only readable by the
+// current user.
+//
+// Processes sharing the file should use the same key; a file that cannot be
+// decrypted is treated as empty and overwritten on the next Store.
+type FileTokenCache struct {
+	mu   sync.Mutex
+	path string
+	key  TokenCacheKey
+	salt []byte      // Salt of the last derived key
+	aead cipher.AEAD // Cipher with the last derived key
+}
+
+// tokenCacheFile is the on-disk format of a FileTokenCache.
+type tokenCacheFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// cachedToken is a token in a FileTokenCache.
+type cachedToken struct {
+	UserToken    string       `json:"userToken"`
+	Expiry       time.Time    `json:"expiry"`
+	AuthProvider AuthProvider `json:"authProvider,omitempty"`
+}
+
+// tokenCacheVersion is the version of the file format written by FileTokenCache.
+const tokenCacheVersion = 1
+
+// NewFileTokenCache creates a TokenCache that stores tokens in an encrypted file.
+// The file and its directory are created on the first Store.
+//
+// Parameters:
+//   - path: Path to the cache file, e.g. from DefaultTokenCachePath.
+//   - key: The secret to encrypt the file with, see PassphraseKey and KeyFileKey.
+//
+// Returns:
+//   - *FileTokenCache: The cache.
+//
+// Example:
+//
+//	path, _ := safeguard.DefaultTokenCachePath()
+//	cache := safeguard.NewFileTokenCache(path, safeguard.KeyFileKey("/etc/safeguard/cache.key"))
+func NewFileTokenCache(path string, key TokenCacheKey) *FileTokenCache {
+	return &FileTokenCache{path: path, key: key}
+}
+
+// Load returns the cached token of identity on appliance, or nil if there is none.
+func (f *FileTokenCache) Load(ctx context.Context, appliance, identity string) (*Token, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tokens, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	cached, ok := tokens[tokenCacheEntry(appliance, identity)]
+	if !ok {
+		return nil, nil
+	}
+	return &Token{UserToken: cached.UserToken, Expiry: cached.Expiry, AuthProvider: cached.AuthProvider}, nil
+}
+
+// Store caches the token of identity on appliance. Expired tokens of other
+// identities are removed.
+func (f *FileTokenCache) Store(ctx context.Context, appliance, identity string, token *Token) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tokens, err := f.read()
+	if err != nil {
+		// Start over, e.g. after the key was changed
+		tokens = map[string]cachedToken{}
+	}
+	for entry, cached := range tokens {
+		if !cached.Expiry.IsZero() && time.Now().After(cached.Expiry) {
+			delete(tokens, entry)
+		}
+	}
+	tokens[tokenCacheEntry(appliance, identity)] = cachedToken{
+		UserToken:    token.UserToken,
+		Expiry:       token.Expiry,
+		AuthProvider: token.AuthProvider,
+	}
+	return f.write(tokens)
+}
+
+// Delete removes the cached token of identity on appliance.
+func (f *FileTokenCache) Delete(ctx context.Context, appliance, identity string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+	entry := tokenCacheEntry(appliance, identity)
+	if _, ok := tokens[entry]; !ok {
+		return nil
+	}
+	delete(tokens, entry)
+	return f.write(tokens)
+}
+
+// tokenCacheEntry returns the key of a token in the cache file.
+func tokenCacheEntry(appliance, identity string) string {
+	return appliance + "\x00" + identity
+}
+
+// read decrypts the cache file. A missing file is an empty cache.
+func (f *FileTokenCache) read() (map[string]cachedToken, error) {
+	tokens := map[string]cachedToken{}
+
+	raw, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token cache: %w", err)
+	}
+
+	var file tokenCacheFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse token cache: %w", err)
+	}
+	if file.Version != tokenCacheVersion {
+		return nil, fmt.Errorf("unsupported token cache version %d", file.Version)
+	}
+
+	aead, err := f.cipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token cache, the key may be wrong: %w", err)
+	}
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse token cache: %w", err)
+	}
+	return tokens, nil
+}
+
+// write encrypts tokens and replaces the cache file atomically.
+func (f *FileTokenCache) write(tokens map[string]cachedToken) error {
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	// Keep the salt of the file, so the derived key can be reused
+	file := tokenCacheFile{Version: tokenCacheVersion, Salt: f.salt}
+	if file.Salt == nil {
+		file.Salt = make([]byte, 16)
+		if _, err := rand.Read(file.Salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+	}
+	aead, err := f.cipher(file.Salt)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	file.Data = aead.Seal(nil, file.Nonce, plaintext, nil)
+
+	raw, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	return nil
+}
+
+// cipher derives the encryption key for salt. The cipher of the last salt is
+// reused, as deriving the key is deliberately slow.
+func (f *FileTokenCache) cipher(salt []byte) (cipher.AEAD, error) {
+	if f.aead != nil && bytes.Equal(salt, f.salt) {
+		return f.aead, nil
+	}
+	if f.key == nil {
+		return nil, errors.New("token cache key must not be nil")
+	}
+	secret, err := f.key()
+	if err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(secret, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive token cache key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	f.salt, f.aead = salt, aead
+	return aead, nil
+}
+
+// clientTokenCache is the token cache of a client, see WithTokenCache.
+type clientTokenCache struct {
+	sync.Mutex
+
+	cache    TokenCache
+	identity string
+}
+
+// RestoreCachedToken loads the token cached for the identity configured with
+// WithTokenCache, checks it with ValidateAccessToken and uses it if the
+// appliance accepts it. Restoring is never done implicitly: Login methods always
+// authenticate, so their credentials are checked even if a token is cached.
+// A client that already holds a token keeps it.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the validation.
+//
+// Returns:
+//   - bool: Whether the cached token was restored; if not, the client has to log in.
+//   - error: An error if the client has no token cache or the cache cannot be read.
+//
+// Example:
+//
+//	if restored, err := client.RestoreCachedToken(ctx); err != nil || !restored {
+//	    err = client.LoginWithOauth()
+//	}
+func (c *SafeguardClient) RestoreCachedToken(ctx context.Context) (bool, error) {
+	if c.tokenCache == nil {
+		return false, errors.New("client has no token cache, see WithTokenCache")
+	}
+
+	c.tokenCache.Lock()
+	defer c.tokenCache.Unlock()
+	if c.AccessToken.getUserToken() != "" {
+		return false, nil
+	}
+
+	appliance := c.Appliance.getUrl()
+	token, err := c.tokenCache.cache.Load(ctx, appliance, c.tokenCache.identity)
+	if err != nil {
+		return false, fmt.Errorf("failed to load cached token: %w", err)
+	}
+	if token == nil || token.UserToken == "" || (!token.Expiry.IsZero() && time.Until(token.Expiry) < refreshMargin) {
+		c.logger().Debug("No usable cached token", "identity", c.tokenCache.identity)
+		return false, nil
+	}
+
+	c.AccessToken.setToken(token, time.Now())
+	if err := c.ValidateAccessToken(ctx); err != nil {
+		c.logger().Info("Cached token was rejected", "identity", c.tokenCache.identity, "error", err)
+		c.AccessToken.setLogin("", time.Time{})
+		if err := c.tokenCache.cache.Delete(ctx, appliance, c.tokenCache.identity); err != nil {
+			c.logger().Warn("Failed to delete cached token", "error", err)
+		}
+		return false, nil
+	}
+
+	var renewal func(ctx context.Context) error
+	if c.tokenSource != nil {
+		renewal = c.tokenSourceRenewal(c.tokenSource)
+	}
+	c.session.Lock()
+	defer c.session.Unlock()
+	c.startSession(ctx, LoginMethodTokenCache, func(context.Context) error { return nil }, renewal)
+
+	c.logger().Info("Using cached token", "identity", c.tokenCache.identity)
+	return true, nil
+}
+
+// storeCachedToken caches the current token of the client. Failures are logged
+// and do not fail the login.
+func (c *SafeguardClient) storeCachedToken(ctx context.Context) {
+	if c.tokenCache == nil {
+		return
+	}
+	if err := c.tokenCache.cache.Store(ctx, c.Appliance.getUrl(), c.tokenCache.identity, c.AccessToken.token()); err != nil {
+		c.logger().Warn("Failed to cache token", "error", err)
+	}
+}
+
+// deleteCachedToken removes the token of the client from the cache.
+func (c *SafeguardClient) deleteCachedToken(ctx context.Context) error {
+	if c.tokenCache == nil {
+		return nil
+	}
+	if err := c.tokenCache.cache.Delete(ctx, c.Appliance.getUrl(), c.tokenCache.identity); err != nil {
+		return fmt.Errorf("failed to delete cached token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package safeguard
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileTokenCache(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache", "tokens")
+	cache := NewFileTokenCache(path, PassphraseKey("secret"))
+
+	if token, err := cache.Load(ctx, "https://a", "alice"); err != nil || token != nil {
+		t.Fatalf("expected no token in a missing file, got %v (%v)", token, err)
+	}
+
+	expiry := time.Now().Add(time.Hour).Round(time.Second)
+	stored := &Token{UserToken: "user-alice", Expiry: expiry, RefreshToken: "not-cached", AuthProvider: AuthProviderLocal}
+	if err := cache.Store(ctx, "https://a", "alice", stored); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if err := cache.Store(ctx, "https://b", "alice", &Token{UserToken: "user-alice-b"}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected the cache file to exist: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected file mode 0600, got %o", perm)
+	}
+	if raw, _ := os.ReadFile(path); strings.Contains(string(raw), "user-alice") {
+		t.Error("expected the token to be encrypted")
+	}
+
+	token, err := NewFileTokenCache(path, PassphraseKey("secret")).Load(ctx, "https://a", "alice")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if token.UserToken != "user-alice" || !token.Expiry.Equal(expiry) || token.AuthProvider != AuthProviderLocal || token.RefreshToken != "" {
+		t.Errorf("unexpected token %+v", token)
+	}
+	if token, _ := cache.Load(ctx, "https://a", "bob"); token != nil {
+		t.Errorf("expected no token for another identity, got %+v", token)
+	}
+
+	if _, err := NewFileTokenCache(path, PassphraseKey("wrong")).Load(ctx, "https://a", "alice"); err == nil {
+		t.Error("expected an error with the wrong key")
+	}
+
+	if err := cache.Delete(ctx, "https://a", "alice"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if token, _ := cache.Load(ctx, "https://a", "alice"); token != nil {
+		t.Errorf("expected the token to be deleted, got %+v", token)
+	}
+	if token, _ := cache.Load(ctx, "https://b", "alice"); token == nil {
+		t.Error("expected the token of the other appliance to be kept")
+	}
+}
+
+func TestFileTokenCacheKeys(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	os.WriteFile(keyFile, []byte("0123456789abcdef0123456789abcdef"), 0o600)
+	emptyFile := filepath.Join(dir, "empty")
+	os.WriteFile(emptyFile, nil, 0o600)
+
+	tests := []struct {
+		name    string
+		key     TokenCacheKey
+		wantErr bool
+	}{
+		{name: "passphrase", key: PassphraseKey("secret")},
+		{name: "key file", key: KeyFileKey(keyFile)},
+		{name: "empty passphrase", key: PassphraseKey(""), wantErr: true},
+		{name: "missing key file", key: KeyFileKey(filepath.Join(dir, "missing")), wantErr: true},
+		{name: "empty key file", key: KeyFileKey(emptyFile), wantErr: true},
+		{name: "nil key", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewFileTokenCache(filepath.Join(t.TempDir(), "tokens"), tt.key)
+			err := cache.Store(context.Background(), "https://a", "alice", &Token{UserToken: "user-alice"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Store() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithTokenCache(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	cache := NewFileTokenCache(filepath.Join(t.TempDir(), "tokens"), PassphraseKey("secret"))
+	ctx := context.Background()
+
+	// The first process logs in and caches the token
+	first := newSessionTestClient(t, f, WithTokenCache(cache, "alice"))
+	if restored, err := first.RestoreCachedToken(ctx); err != nil || restored {
+		t.Fatalf("RestoreCachedToken() = %v, %v; want false with an empty cache", restored, err)
+	}
+	if err := first.LoginWithPassword("alice", "alice-password"); err != nil {
+		t.Fatalf("LoginWithPassword() error = %v", err)
+	}
+
+	// The next one reuses it
+	second := newSessionTestClient(t, f, WithTokenCache(cache, "alice"))
+	if restored, err := second.RestoreCachedToken(ctx); err != nil || !restored {
+		t.Fatalf("RestoreCachedToken() = %v, %v; want true", restored, err)
+	}
+	if name, err := me(ctx, second); err != nil || name != "alice" {
+		t.Errorf("expected alice, got %q (%v)", name, err)
+	}
+	if got := f.loginCount(); got != 1 {
+		t.Errorf("expected the cached token to be used, got %d logins", got)
+	}
+
+	// A rejected token is not restored
+	f.revokeTokens()
+	third := newSessionTestClient(t, f, WithTokenCache(cache, "alice"))
+	if restored, err := third.RestoreCachedToken(ctx); err != nil || restored {
+		t.Fatalf("RestoreCachedToken() = %v, %v; want false for a rejected token", restored, err)
+	}
+	if err := third.LoginWithPassword("alice", "alice-password"); err != nil {
+		t.Fatalf("LoginWithPassword() error = %v", err)
+	}
+	if got := f.loginCount(); got != 2 {
+		t.Errorf("expected a new login after the cached token was rejected, got %d logins", got)
+	}
+
+	// A client with a token source keeps using the restored token
+	fourth := newSessionTestClient(t, f,
+		WithTokenCache(cache, "alice"),
+		WithTokenSource(PasswordTokenSource("alice", "alice-password")),
+	)
+	if restored, err := fourth.RestoreCachedToken(ctx); err != nil || !restored {
+		t.Fatalf("RestoreCachedToken() = %v, %v; want true", restored, err)
+	}
+	if name, err := me(ctx, fourth); err != nil || name != "alice" {
+		t.Errorf("expected alice, got %q (%v)", name, err)
+	}
+	if got := f.loginCount(); got != 2 {
+		t.Errorf("expected the cached token to be used, got %d logins", got)
+	}
+}
+
+func TestRestoreCachedTokenWithoutCache(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f)
+	if restored, err := client.RestoreCachedToken(context.Background()); err == nil || restored {
+		t.Errorf("RestoreCachedToken() = %v, %v; want an error", restored, err)
+	}
+}
+
+func TestTokenCacheDoesNotSkipLogins(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	cache := NewFileTokenCache(filepath.Join(t.TempDir(), "tokens"), PassphraseKey("secret"))
+	ctx := context.Background()
+
+	first := newSessionTestClient(t, f, WithTokenCache(cache, "alice"))
+	if err := first.LoginWithPassword("alice", "alice-password"); err != nil {
+		t.Fatalf("LoginWithPassword() error = %v", err)
+	}
+
+	// Logins never use the cached token, so credentials are always checked
+	tests := []struct {
+		name     string
+		username string
+		password string
+		wantErr  bool
+		wantUser string
+	}{
+		{name: "wrong password", username: "alice", password: "wrong-password", wantErr: true},
+		{name: "other user", username: "bob", password: "bob-password", wantUser: "bob"},
+		{name: "wrong password of other user", username: "bob", password: "wrong-password", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newSessionTestClient(t, f, WithTokenCache(cache, "alice"))
+			err := client.LoginWithPassword(tt.username, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoginWithPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if name, err := me(ctx, client); err != nil || name != tt.wantUser {
+				t.Errorf("expected %s, got %q (%v)", tt.wantUser, name, err)
+			}
+		})
+	}
+}
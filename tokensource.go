@@ -0,0 +1,225 @@
+package safeguard
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Token is a Safeguard user token returned by a TokenSource.
+type Token struct {
+	UserToken    string       // Safeguard user token sent as bearer token
+	Expiry       time.Time    // When the user token expires; zero if unknown
+	RefreshToken string       // RSTS refresh token to renew the user token; optional
+	AuthProvider AuthProvider // Provider the user authenticated with; optional
+}
+
+// TokenSource provides Safeguard user tokens. A client with a token source asks
+// it for a token before its first request and whenever the token cannot be
+// renewed with the RSTS refresh token, similar to oauth2.TokenSource.
+//
+// Token is called with the client that needs the token, so implementations can
+// log in against its appliance, e.g. by delegating to PasswordTokenSource.
+// Calls are serialised by the client.
+type TokenSource interface {
+	Token(ctx context.Context, c *SafeguardClient) (*Token, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource, e.g. to fetch credentials
+// from a vault before logging in.
+//
+// Example:
+//
+//	source := safeguard.TokenSourceFunc(func(ctx context.Context, c *safeguard.SafeguardClient) (*safeguard.Token, error) {
+//	    password, err := vault.Secret(ctx, "safeguard/svc-backup")
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return safeguard.PasswordTokenSource("svc-backup", password).Token(ctx, c)
+//	})
+type TokenSourceFunc func(ctx context.Context, c *SafeguardClient) (*Token, error)
+
+// Token calls f(ctx, c).
+func (f TokenSourceFunc) Token(ctx context.Context, c *SafeguardClient) (*Token, error) {
+	return f(ctx, c)
+}
+
+// PasswordTokenSource returns a TokenSource that logs in with a username and
+// password of the local authentication provider.
+//
+// Parameters:
+//   - username: The username of the user.
+//   - password: The password of the user.
+//
+// Returns:
+//   - TokenSource: The token source.
+func PasswordTokenSource(username, password string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context, c *SafeguardClient) (*Token, error) {
+		if err := c.loginWithPassword(ctx, username, password); err != nil {
+			return nil, err
+		}
+		return c.AccessToken.token(), nil
+	})
+}
+
+// CertificateFileTokenSource returns a TokenSource that logs in with a PKCS12
+// certificate file. The file is read on every login, so a renewed certificate
+// is picked up without restarting.
+//
+// Parameters:
+//   - certPath: Path to the PKCS12 certificate file.
+//   - certPassword: Password for the certificate.
+//
+// Returns:
+//   - TokenSource: The token source.
+func CertificateFileTokenSource(certPath, certPassword string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context, c *SafeguardClient) (*Token, error) {
+		if err := c.loginWithCertificate(ctx, certPath, certPassword); err != nil {
+			return nil, err
+		}
+		return c.AccessToken.token(), nil
+	})
+}
+
+// PKCS12TokenSource returns a TokenSource that logs in with a PKCS12 certificate
+// held in memory, e.g. one loaded from a secret store.
+//
+// Parameters:
+//   - pfx: The PKCS12 encoded certificate and private key.
+//   - password: Password for the certificate.
+//
+// Returns:
+//   - TokenSource: The token source.
+func PKCS12TokenSource(pfx []byte, password string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context, c *SafeguardClient) (*Token, error) {
+		if err := c.loginWithPKCS12(ctx, pfx, password); err != nil {
+			return nil, err
+		}
+		return c.AccessToken.token(), nil
+	})
+}
+
+// TLSCertificateTokenSource returns a TokenSource that logs in with a client
+// certificate held in memory, e.g. one from PEMCertificate or SignerCertificate.
+//
+// Parameters:
+//   - cert: The certificate chain and its private key.
+//
+// Returns:
+//   - TokenSource: The token source.
+func TLSCertificateTokenSource(cert tls.Certificate) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context, c *SafeguardClient) (*Token, error) {
+		cert, err := checkClientCertificate(cert)
+		if err != nil {
+			return nil, fmt.Errorf("create tls config failed: %w", err)
+		}
+		if err := c.loginWithTLSCertificate(ctx, cert); err != nil {
+			return nil, err
+		}
+		return c.AccessToken.token(), nil
+	})
+}
+
+// StaticTokenSource returns a TokenSource that always returns the same pre-issued
+// user token, e.g. one passed in through an environment variable. The token is
+// never renewed; once the appliance rejects it, requests fail.
+//
+// Parameters:
+//   - userToken: The Safeguard user token.
+//
+// Returns:
+//   - TokenSource: The token source.
+func StaticTokenSource(userToken string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context, c *SafeguardClient) (*Token, error) {
+		return &Token{UserToken: userToken}, nil
+	})
+}
+
+// LoginWithTokenSource logs in with a token from source. Later renewals use the
+// RSTS refresh token of the login if there is one and ask source again otherwise.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the login.
+//   - source: The token source.
+//
+// Returns:
+//   - error: An error if source fails or returns no user token.
+func (c *SafeguardClient) LoginWithTokenSource(ctx context.Context, source TokenSource) (err error) {
+	ctx, end := c.traceLogin(ctx, LoginMethodTokenSource)
+	defer end(&err)
+
+	return c.login(ctx, LoginMethodTokenSource, func(ctx context.Context) error {
+		return c.loginWithTokenSource(ctx, source)
+	}, c.tokenSourceRenewal(source))
+}
+
+// loginWithTokenSource stores a token from source in c.AccessToken.
+func (c *SafeguardClient) loginWithTokenSource(ctx context.Context, source TokenSource) error {
+	if c.AccessToken == nil {
+		c.AccessToken = &RSTSAuthResponse{}
+	}
+
+	token, err := source.Token(ctx, c)
+	if err != nil {
+		return fmt.Errorf("token source failed: %w", err)
+	}
+	if token == nil || token.UserToken == "" {
+		return errors.New("token source returned no user token")
+	}
+
+	c.AccessToken.setToken(token, time.Now())
+	return nil
+}
+
+// tokenSourceRenewal returns a function that renews the token by asking source
+// again. It fails with errNotRepeatable if source returns the token that is
+// being renewed, as a StaticTokenSource does.
+func (c *SafeguardClient) tokenSourceRenewal(source TokenSource) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		previous := c.AccessToken.getUserToken()
+		if err := c.loginWithTokenSource(ctx, source); err != nil {
+			return err
+		}
+		if c.AccessToken.getUserToken() == previous {
+			return errNotRepeatable
+		}
+		return nil
+	}
+}
+
+// ensureToken logs in with the token source configured with WithTokenSource if
+// the client has no user token yet. Concurrent callers share a single login.
+//
+// Parameters:
+//   - ctx: Context for cancellation and deadlines of the login.
+//
+// Returns:
+//   - error: An error if the login fails.
+func (c *SafeguardClient) ensureToken(ctx context.Context) (err error) {
+	if c.AccessToken != nil && c.AccessToken.getUserToken() != "" {
+		return nil
+	}
+	if c.tokenSource == nil {
+		return nil
+	}
+
+	c.session.Lock()
+	defer c.session.Unlock()
+	if c.AccessToken != nil && c.AccessToken.getUserToken() != "" {
+		// Another request logged in while waiting for the lock
+		return nil
+	}
+
+	ctx, end := c.traceLogin(ctx, LoginMethodTokenSource)
+	defer end(&err)
+	err = c.startSession(ctx, LoginMethodTokenSource, func(ctx context.Context) error {
+		return c.loginWithTokenSource(ctx, c.tokenSource)
+	}, c.tokenSourceRenewal(c.tokenSource))
+	if err != nil {
+		return fmt.Errorf("login with token source failed: %w", err)
+	}
+	c.storeCachedToken(ctx)
+	return nil
+}
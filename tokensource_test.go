@@ -0,0 +1,130 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// issueToken makes the server accept a user token that was issued elsewhere.
+func (f *fakeAuthServer) issueToken(token, username string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens[token] = username
+}
+
+func TestWithTokenSourceLogsInOnFirstRequest(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	client := newSessionTestClient(t, f, WithTokenSource(PasswordTokenSource("alice", "alice-password")))
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if name, err := me(context.Background(), client); err != nil || name != "alice" {
+				t.Errorf("expected alice, got %q (%v)", name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := f.loginCount(); got != 1 {
+		t.Errorf("expected a single login, got %d", got)
+	}
+}
+
+func TestStaticTokenSource(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, true)
+	f.issueToken("static-token", "carol")
+	client := newSessionTestClient(t, f)
+	ctx := context.Background()
+
+	if err := client.LoginWithTokenSource(ctx, StaticTokenSource("static-token")); err != nil {
+		t.Fatalf("LoginWithTokenSource() error = %v", err)
+	}
+	if name, err := me(ctx, client); err != nil || name != "carol" {
+		t.Fatalf("expected carol, got %q (%v)", name, err)
+	}
+
+	f.revokeTokens()
+	if _, err := me(ctx, client); !IsUnauthorized(err) {
+		t.Errorf("expected an unauthorized error, got %v", err)
+	}
+}
+
+func TestTokenSourceRenewal(t *testing.T) {
+	f := newFakeAuthServer(t, 3600, false)
+	client := newSessionTestClient(t, f)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	source := TokenSourceFunc(func(ctx context.Context, c *SafeguardClient) (*Token, error) {
+		calls.Add(1)
+		return PasswordTokenSource("alice", "alice-password").Token(ctx, c)
+	})
+
+	if err := client.LoginWithTokenSource(ctx, source); err != nil {
+		t.Fatalf("LoginWithTokenSource() error = %v", err)
+	}
+	if expiry := client.GetTokenExpirationTime(); time.Until(expiry) < 59*time.Minute {
+		t.Errorf("unexpected token expiry %v", expiry)
+	}
+
+	f.revokeTokens()
+	if name, err := me(ctx, client); err != nil || name != "alice" {
+		t.Errorf("expected alice, got %q (%v)", name, err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected the token source to be asked again, got %d calls", got)
+	}
+	if username, _ := client.AccessToken.getUserNamePassword(); username != "" {
+		t.Errorf("expected no stored credentials, got %q", username)
+	}
+}
+
+func TestTokenSourceErrors(t *testing.T) {
+	errVault := errors.New("vault unavailable")
+	tests := []struct {
+		name    string
+		source  TokenSource
+		wantErr error
+	}{
+		{
+			name: "source error",
+			source: TokenSourceFunc(func(context.Context, *SafeguardClient) (*Token, error) {
+				return nil, errVault
+			}),
+			wantErr: errVault,
+		},
+		{
+			name: "no token",
+			source: TokenSourceFunc(func(context.Context, *SafeguardClient) (*Token, error) {
+				return nil, nil
+			}),
+		},
+		{name: "empty token", source: StaticTokenSource("")},
+		{name: "invalid PKCS12", source: PKCS12TokenSource([]byte("not a certificate"), "secret")},
+		{name: "wrong password", source: PasswordTokenSource("alice", "wrong")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeAuthServer(t, 3600, true)
+			client := newSessionTestClient(t, f, WithTokenSource(tt.source))
+
+			if err := client.LoginWithTokenSource(context.Background(), tt.source); err == nil {
+				t.Fatal("expected an error from LoginWithTokenSource")
+			} else if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if _, err := me(context.Background(), client); err == nil {
+				t.Error("expected the request to fail without a token")
+			}
+		})
+	}
+}
@@ -1 +1,39 @@
 package safeguard
+
+import "testing"
+
+func TestBatchResponseSetClient(t *testing.T) {
+	client := &SafeguardClient{}
+
+	tests := []struct {
+		name   string
+		client func() (batch, response *SafeguardClient)
+	}{
+		{
+			name: "access request batch response",
+			client: func() (*SafeguardClient, *SafeguardClient) {
+				r := addClientToSlice(client, []AccessRequestBatchResponse{{Response: AccessRequest{Id: "1"}}})[0]
+				return r.apiClient, r.Response.apiClient
+			},
+		},
+		{
+			name: "asset account batch response",
+			client: func() (*SafeguardClient, *SafeguardClient) {
+				r := addClientToSlice(client, []AssetAccountBatchResponse{{Response: AssetAccount{Id: 1}}})[0]
+				return r.apiClient, r.Response.apiClient
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch, response := tt.client()
+			if batch != client {
+				t.Error("expected the client on the batch item")
+			}
+			if response != client {
+				t.Error("expected the client on the nested Response")
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@
 package safeguard
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"time"
 )
 
@@ -149,17 +151,18 @@ func (u UserGroup) ToJson() (string, error) {
 //	groups, err := GetUserGroups(fields)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: A Filter object containing field comparisons and ordering preferences
 //
 // Returns:
 //   - []UserGroup: A slice of UserGroup objects matching the filter criteria
 //   - error: An error if the request fails or response parsing fails, nil otherwise
-func (c *SafeguardClient) GetUserGroups(fields Filter) ([]UserGroup, error) {
+func (c *SafeguardClient) GetUserGroups(ctx context.Context, fields Filter) ([]UserGroup, error) {
 	var userGroups []UserGroup
 
 	query := "UserGroups" + fields.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +173,42 @@ func (c *SafeguardClient) GetUserGroups(fields Filter) ([]UserGroup, error) {
 	return addClientToSlice(c, userGroups), nil
 }
 
+// UserGroups returns an iterator over all user groups matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.UserGroups(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[UserGroup, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) UserGroups(ctx context.Context, filter Filter) iter.Seq2[UserGroup, error] {
+	return paginate[UserGroup](ctx, c, "UserGroups", filter)
+}
+
+// CountUserGroups returns the total number of user groups matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching user groups
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountUserGroups(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "UserGroups", filter)
+}
+
 // GetUserGroup retrieves a single user group by its unique identifier.
 //
 // The method can include additional related objects in the response based on the
@@ -182,13 +221,14 @@ func (c *SafeguardClient) GetUserGroups(fields Filter) ([]UserGroup, error) {
 //	group, err := GetUserGroup(123, fields)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the user group to retrieve
 //   - fields: Optional Fields object specifying which related objects to include
 //
 // Returns:
 //   - UserGroup: The requested user group with all specified related objects
 //   - error: An error if the group is not found or request fails, nil otherwise
-func (c *SafeguardClient) GetUserGroup(id int, fields Fields) (UserGroup, error) {
+func (c *SafeguardClient) GetUserGroup(ctx context.Context, id int, fields Fields) (UserGroup, error) {
 	var userGroup UserGroup
 
 	query := fmt.Sprintf("UserGroups/%d", id)
@@ -196,7 +236,7 @@ func (c *SafeguardClient) GetUserGroup(id int, fields Fields) (UserGroup, error)
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return userGroup, err
 	}
@@ -2,8 +2,10 @@ package safeguard
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"time"
 )
 
@@ -96,17 +98,18 @@ func (u User) ToJson() (string, error) {
 //	users, err := GetUsers(filter)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - fields: Filter object containing field comparisons and ordering preferences
 //
 // Returns:
 //   - []User: A slice of users matching the filter criteria
 //   - error: An error if the request fails, nil otherwise
-func (c *SafeguardClient) GetUsers(filter Filter) ([]User, error) {
+func (c *SafeguardClient) GetUsers(ctx context.Context, filter Filter) ([]User, error) {
 	var users []User
 
 	query := "users" + filter.ToQueryString()
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +121,42 @@ func (c *SafeguardClient) GetUsers(filter Filter) ([]User, error) {
 	return addClientToSlice(c, users), nil
 }
 
+// Users returns an iterator over all users matching the filter.
+// Pages are fetched lazily using filter.Page and filter.Limit, so large
+// collections can be processed without loading them into memory at once.
+//
+// Example:
+//
+//	for item, err := range client.Users(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Id)
+//	}
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - iter.Seq2[User, error]: Yields each matching item, or an error if a page request fails
+func (c *SafeguardClient) Users(ctx context.Context, filter Filter) iter.Seq2[User, error] {
+	return paginate[User](ctx, c, "users", filter)
+}
+
+// CountUsers returns the total number of users matching the filter.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - filter: Query parameters to filter the results
+//
+// Returns:
+//   - int: The number of matching users
+//   - error: An error if the request fails
+func (c *SafeguardClient) CountUsers(ctx context.Context, filter Filter) (int, error) {
+	return countItems(ctx, c, "users", filter)
+}
+
 // GetUser retrieves details for a specific user by ID.
 //
 // This method returns detailed information about a single user, optionally including
@@ -130,13 +169,14 @@ func (c *SafeguardClient) GetUsers(filter Filter) ([]User, error) {
 //	user, err := GetUser(123, fields)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the user to retrieve
 //   - fields: Optional Fields object specifying which related objects to include
 //
 // Returns:
 //   - User: The requested user with all specified related objects
 //   - error: An error if the user is not found or request fails, nil otherwise
-func (c *SafeguardClient) GetUser(id int, fields Fields) (User, error) {
+func (c *SafeguardClient) GetUser(ctx context.Context, id int, fields Fields) (User, error) {
 	var user User
 
 	query := fmt.Sprintf("users/%d", id)
@@ -144,7 +184,7 @@ func (c *SafeguardClient) GetUser(id int, fields Fields) (User, error) {
 		query += fields.ToQueryString()
 	}
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return user, err
 	}
@@ -165,17 +205,18 @@ func (c *SafeguardClient) GetUser(id int, fields Fields) (User, error) {
 //	accounts, err := GetLinkedAccounts("123")
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The string identifier of the user
 //
 // Returns:
 //   - []PolicyAccount: A slice of linked policy accounts
 //   - error: An error if the request fails, nil otherwise
-func (c *SafeguardClient) GetLinkedAccounts(id string) ([]PolicyAccount, error) {
+func (c *SafeguardClient) GetLinkedAccounts(ctx context.Context, id string) ([]PolicyAccount, error) {
 	var linkedAccounts []PolicyAccount
 
 	query := fmt.Sprintf("users/%s/LinkedPolicyAccounts", id)
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -199,8 +240,8 @@ func (c *SafeguardClient) GetLinkedAccounts(id string) ([]PolicyAccount, error)
 // Returns:
 //   - []PolicyAccount: A slice of linked policy accounts
 //   - error: An error if the request fails, nil otherwise
-func (u User) GetLinkedAccounts() ([]PolicyAccount, error) {
-	return u.apiClient.GetLinkedAccounts(fmt.Sprintf("%d", u.Id))
+func (u User) GetLinkedAccounts(ctx context.Context) ([]PolicyAccount, error) {
+	return u.apiClient.GetLinkedAccounts(ctx, fmt.Sprintf("%d", u.Id))
 }
 
 // GetUserRoles retrieves the roles assigned to a specific user.
@@ -212,17 +253,18 @@ func (u User) GetLinkedAccounts() ([]PolicyAccount, error) {
 //	roles, err := GetUserRoles("123")
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The string identifier of the user
 //
 // Returns:
 //   - []Role: A slice of assigned roles
 //   - error: An error if the request fails, nil otherwise
-func (c *SafeguardClient) GetUserRoles(id string) ([]Role, error) {
+func (c *SafeguardClient) GetUserRoles(ctx context.Context, id string) ([]Role, error) {
 	var roles []Role
 
 	query := fmt.Sprintf("users/%s/roles", id)
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -245,8 +287,8 @@ func (c *SafeguardClient) GetUserRoles(id string) ([]Role, error) {
 // Returns:
 //   - []Role: A slice of assigned roles
 //   - error: An error if the request fails, nil otherwise
-func (u User) GetRoles() ([]Role, error) {
-	return u.apiClient.GetUserRoles(fmt.Sprintf("%d", u.Id))
+func (u User) GetRoles(ctx context.Context) ([]Role, error) {
+	return u.apiClient.GetUserRoles(ctx, fmt.Sprintf("%d", u.Id))
 }
 
 // GetGroups retrieves the groups that a specific user belongs to.
@@ -258,17 +300,18 @@ func (u User) GetRoles() ([]Role, error) {
 //	groups, err := GetGroups("123")
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The string identifier of the user
 //
 // Returns:
 //   - []UserGroup: A slice of user groups
 //   - error: An error if the request fails, nil otherwise
-func (c *SafeguardClient) GetGroups(id string) ([]UserGroup, error) {
+func (c *SafeguardClient) GetGroups(ctx context.Context, id string) ([]UserGroup, error) {
 	var userGroups []UserGroup
 
 	query := fmt.Sprintf("users/%s/UserGroups", id)
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -291,8 +334,8 @@ func (c *SafeguardClient) GetGroups(id string) ([]UserGroup, error) {
 // Returns:
 //   - []UserGroup: A slice of user groups
 //   - error: An error if the request fails, nil otherwise
-func (u User) GetGroups() ([]UserGroup, error) {
-	return u.apiClient.GetGroups(fmt.Sprintf("%d", u.Id))
+func (u User) GetGroups(ctx context.Context) ([]UserGroup, error) {
+	return u.apiClient.GetGroups(ctx, fmt.Sprintf("%d", u.Id))
 }
 
 // GetUserPreferences retrieves the preferences for a specific user.
@@ -304,17 +347,18 @@ func (u User) GetGroups() ([]UserGroup, error) {
 //	prefs, err := GetUserPreferences(123)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the user
 //
 // Returns:
 //   - []Preference: A slice of user preferences
 //   - error: An error if the request fails, nil otherwise
-func (c *SafeguardClient) GetUserPreferences(id int) ([]Preference, error) {
+func (c *SafeguardClient) GetUserPreferences(ctx context.Context, id int) ([]Preference, error) {
 	var userPreferences []Preference
 
 	query := fmt.Sprintf("users/%d/preferences", id)
 
-	response, err := c.GetRequest(query)
+	response, err := c.GetRequest(ctx, query)
 	if err != nil {
 		return userPreferences, err
 	}
@@ -337,8 +381,8 @@ func (c *SafeguardClient) GetUserPreferences(id int) ([]Preference, error) {
 // Returns:
 //   - []Preference: A slice of user preferences
 //   - error: An error if the request fails, nil otherwise
-func (u User) GetPreferences() ([]Preference, error) {
-	return u.apiClient.GetUserPreferences(u.Id)
+func (u User) GetPreferences(ctx context.Context) ([]Preference, error) {
+	return u.apiClient.GetUserPreferences(ctx, u.Id)
 }
 
 // AddLinkedAccounts adds policy accounts to a user's linked accounts.
@@ -351,17 +395,18 @@ func (u User) GetPreferences() ([]Preference, error) {
 //	linked, err := AddLinkedAccounts(user, accounts)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - user: The user to link accounts to
 //   - policyAccount: A slice of policy accounts to link
 //
 // Returns:
 //   - []PolicyAccount: The linked policy accounts
 //   - error: An error if the operation fails, nil otherwise
-func (c *SafeguardClient) AddLinkedAccounts(user User, policyAccount []PolicyAccount) ([]PolicyAccount, error) {
+func (c *SafeguardClient) AddLinkedAccounts(ctx context.Context, user User, policyAccount []PolicyAccount) ([]PolicyAccount, error) {
 	var linkedAccounts []PolicyAccount
 
 	query := fmt.Sprintf("users/%d/LinkedPolicyAccounts/Add", user.Id)
-	response, err := c.fetchAndPostPolicyAccount(policyAccount, query)
+	response, err := c.fetchAndPostPolicyAccount(ctx, policyAccount, query)
 	if err != nil {
 		return nil, err
 	}
@@ -383,13 +428,14 @@ func (c *SafeguardClient) AddLinkedAccounts(user User, policyAccount []PolicyAcc
 //	linked, err := user.AddLinkedAccounts(accounts)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - policyAccount: A slice of policy accounts to link
 //
 // Returns:
 //   - []PolicyAccount: The linked policy accounts
 //   - error: An error if the operation fails, nil otherwise
-func (u User) AddLinkedAccounts(policyAccount []PolicyAccount) ([]PolicyAccount, error) {
-	return u.apiClient.AddLinkedAccounts(u, policyAccount)
+func (u User) AddLinkedAccounts(ctx context.Context, policyAccount []PolicyAccount) ([]PolicyAccount, error) {
+	return u.apiClient.AddLinkedAccounts(ctx, u, policyAccount)
 }
 
 // RemoveLinkedAccounts removes policy accounts from a user's linked accounts.
@@ -403,17 +449,18 @@ func (u User) AddLinkedAccounts(policyAccount []PolicyAccount) ([]PolicyAccount,
 //	removed, err := RemoveLinkedAccounts(user, accounts)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - user: The user to remove links from
 //   - policyAccount: A slice of policy accounts to unlink
 //
 // Returns:
 //   - []PolicyAccount: The unlinked policy accounts
 //   - error: An error if the operation fails, nil otherwise
-func (c *SafeguardClient) RemoveLinkedAccounts(user User, policyAccount []PolicyAccount) ([]PolicyAccount, error) {
+func (c *SafeguardClient) RemoveLinkedAccounts(ctx context.Context, user User, policyAccount []PolicyAccount) ([]PolicyAccount, error) {
 	var linkedAccounts []PolicyAccount
 
 	query := fmt.Sprintf("users/%d/LinkedPolicyAccounts/Remove", user.Id)
-	response, err := c.fetchAndPostPolicyAccount(policyAccount, query)
+	response, err := c.fetchAndPostPolicyAccount(ctx, policyAccount, query)
 	if err != nil {
 		return nil, err
 	}
@@ -435,13 +482,14 @@ func (c *SafeguardClient) RemoveLinkedAccounts(user User, policyAccount []Policy
 //	removed, err := user.RemoveLinkedAccounts(accounts)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - policyAccount: A slice of policy accounts to unlink
 //
 // Returns:
 //   - []PolicyAccount: The unlinked policy accounts
 //   - error: An error if the operation fails, nil otherwise
-func (u User) RemoveLinkedAccounts(policyAccount []PolicyAccount) ([]PolicyAccount, error) {
-	return u.apiClient.RemoveLinkedAccounts(u, policyAccount)
+func (u User) RemoveLinkedAccounts(ctx context.Context, policyAccount []PolicyAccount) ([]PolicyAccount, error) {
+	return u.apiClient.RemoveLinkedAccounts(ctx, u, policyAccount)
 }
 
 // fetchAndPostPolicyAccount sends a POST request to the Safeguard API with the given policy accounts and query.
@@ -449,6 +497,7 @@ func (u User) RemoveLinkedAccounts(policyAccount []PolicyAccount) ([]PolicyAccou
 // It returns the response as a byte slice and an error if any occurred.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: A pointer to a SafeguardClient used to make API requests.
 //   - policyAccount: A slice of PolicyAccount objects to be sent in the request body.
 //   - query: A string representing the API endpoint to which the request will be sent.
@@ -456,13 +505,13 @@ func (u User) RemoveLinkedAccounts(policyAccount []PolicyAccount) ([]PolicyAccou
 // Returns:
 //   - []byte: The response from the API as a byte slice.
 //   - error: An error object if an error occurred during the operation, otherwise nil.
-func (c *SafeguardClient) fetchAndPostPolicyAccount(policyAccount []PolicyAccount, query string) ([]byte, error) {
+func (c *SafeguardClient) fetchAndPostPolicyAccount(ctx context.Context, policyAccount []PolicyAccount, query string) ([]byte, error) {
 	policyAccountJson, err := json.Marshal(policyAccount)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.PostRequest(query, bytes.NewReader(policyAccountJson))
+	return c.PostRequest(ctx, query, bytes.NewReader(policyAccountJson))
 }
 
 // CreateUser creates a new user in Safeguard.
@@ -479,12 +528,13 @@ func (c *SafeguardClient) fetchAndPostPolicyAccount(policyAccount []PolicyAccoun
 //	created, err := CreateUser(newUser)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - user: The user object containing the new user's details
 //
 // Returns:
 //   - User: The created user object
 //   - error: An error if the creation fails, nil otherwise
-func (c *SafeguardClient) CreateUser(user User) (User, error) {
+func (c *SafeguardClient) CreateUser(ctx context.Context, user User) (User, error) {
 	var createdUser User
 
 	userJson, err := json.Marshal(user)
@@ -492,7 +542,7 @@ func (c *SafeguardClient) CreateUser(user User) (User, error) {
 		return createdUser, err
 	}
 
-	response, err := c.PostRequest("users", bytes.NewReader(userJson))
+	response, err := c.PostRequest(ctx, "users", bytes.NewReader(userJson))
 	if err != nil {
 		return createdUser, err
 	}
@@ -515,17 +565,18 @@ func (c *SafeguardClient) CreateUser(user User) (User, error) {
 //	updated, err := user.SetAuthenticationProvider(provider)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - authProvider: The new authentication provider to set
 //
 // Returns:
 //   - User: The updated user object
 //   - error: An error if the update fails, nil otherwise
-func (u User) SetAuthenticationProvider(authProvider AuthenticationProvider) (User, error) {
+func (u User) SetAuthenticationProvider(ctx context.Context, authProvider AuthenticationProvider) (User, error) {
 	var updatedUser User
 
 	u.PrimaryAuthenticationProvider = authProvider
 
-	updatedUser, err := u.apiClient.updateUser(u)
+	updatedUser, err := u.apiClient.updateUser(ctx, u)
 	if err != nil {
 		return updatedUser, err
 	}
@@ -537,13 +588,14 @@ func (u User) SetAuthenticationProvider(authProvider AuthenticationProvider) (Us
 // It takes a SafeguardClient and a User object as parameters, and returns the updated User object and an error, if any.
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - c: A pointer to a SafeguardClient used to make the request.
 //   - user: A User object containing the updated user details.
 //
 // Returns:
 //   - User: The updated User object.
 //   - error: An error object if an error occurred during the update process, otherwise nil.
-func (c *SafeguardClient) updateUser(user User) (User, error) {
+func (c *SafeguardClient) updateUser(ctx context.Context, user User) (User, error) {
 	var updatedUser User
 
 	query := fmt.Sprintf("users/%d", user.Id)
@@ -553,7 +605,7 @@ func (c *SafeguardClient) updateUser(user User) (User, error) {
 		return updatedUser, err
 	}
 
-	response, err := c.PutRequest(query, bytes.NewReader(userJson))
+	response, err := c.PutRequest(ctx, query, bytes.NewReader(userJson))
 	if err != nil {
 		return updatedUser, err
 	}
@@ -574,14 +626,15 @@ func (c *SafeguardClient) updateUser(user User) (User, error) {
 //	err := DeleteUser(123)
 //
 // Parameters:
+//   - ctx: Context for cancellation and timeouts.
 //   - id: The unique identifier of the user to delete
 //
 // Returns:
 //   - error: An error if the deletion fails, nil otherwise
-func (c *SafeguardClient) DeleteUser(id int) error {
+func (c *SafeguardClient) DeleteUser(ctx context.Context, id int) error {
 	query := fmt.Sprintf("users/%d", id)
 
-	_, err := c.DeleteRequest(query)
+	_, err := c.DeleteRequest(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -600,6 +653,6 @@ func (c *SafeguardClient) DeleteUser(id int) error {
 //
 // Returns:
 //   - error: An error if the deletion fails, nil otherwise
-func (u *User) Delete() error {
-	return u.apiClient.DeleteUser(u.Id)
+func (u *User) Delete(ctx context.Context) error {
+	return u.apiClient.DeleteUser(ctx, u.Id)
 }
@@ -2,6 +2,7 @@ package safeguard
 
 import (
 	"fmt"
+	"log/slog"
 	"net/url"
 	"os/exec"
 	"runtime"
@@ -46,12 +47,13 @@ func addClientToSlice[T ClientHolder](client *SafeguardClient, v []T) []T {
 // a warning is logged.
 //
 // Parameters:
+//   - logger: The logger used to report failures.
 //   - url: The URL to be opened in the web browser.
 //
 // Example usage:
 //
-//	openBrowser("https://example.com")
-func openBrowser(url string) {
+//	openBrowser(c.logger(), "https://example.com")
+func openBrowser(logger *slog.Logger, url string) {
 	var cmd string
 	var args []string
 